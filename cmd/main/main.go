@@ -7,14 +7,38 @@ import (
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/config"
 	"github.com/ilyadubrovsky/tracking-bars/internal/database/pg"
+	accesstokensrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/access_tokens"
+	authorizationfailuresrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/authorization_failures"
+	broadcastsrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/broadcasts"
+	chatsrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/chats"
+	feedtokensrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/feed_tokens"
+	gradedistributionsrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/grade_distributions"
 	gradeschangesoutboxrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/grades_changes_outbox"
+	remindersrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/reminders"
+	usersettingsrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/user_settings"
 	"github.com/ilyadubrovsky/tracking-bars/internal/repository/users"
+	webhooksrepo "github.com/ilyadubrovsky/tracking-bars/internal/repository/webhooks"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/access_tokens"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/api"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/authorization_failures"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service/bars"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/broadcasts"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/broadcasts_sender"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/calendar"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/chats"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/email"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/feed_tokens"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/grade_distributions"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service/grades_changes"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service/grades_changes_outbox"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/grades_feed"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/reminders"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/renderer"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/schedule"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service/telegram"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service/user"
-	"github.com/jellydator/ttlcache/v3"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/user_settings"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/webhooks"
 	"github.com/rs/zerolog"
 )
 
@@ -36,18 +60,100 @@ func main() {
 
 	usersRepository := users.NewRepository(db)
 	gradesChangesOutboxRepository := gradeschangesoutboxrepo.NewRepository(db)
-	authorizationFailedRetriesCountCache := ttlcache.New[int64, int](
-		ttlcache.WithTTL[int64, int](60 * time.Minute),
-	)
+	authorizationFailuresRepository := authorizationfailuresrepo.NewRepository(db)
+	userSettingsRepository := usersettingsrepo.NewRepository(db)
+	webhooksRepository := webhooksrepo.NewRepository(db)
+	accessTokensRepository := accesstokensrepo.NewRepository(db)
+	feedTokensRepository := feedtokensrepo.NewRepository(db)
+	remindersRepository := remindersrepo.NewRepository(db)
+	gradeDistributionsRepository := gradedistributionsrepo.NewRepository(db)
+	chatsRepository := chatsrepo.NewRepository(db)
+	broadcastsRepository := broadcastsrepo.NewRepository(db)
 
 	userService := user.NewService(usersRepository)
+	emailService := email.NewService(cfg.Email)
+	userSettingsService := user_settings.NewService(
+		userSettingsRepository,
+		emailService,
+		cfg.Email,
+	)
+	webhooksService := webhooks.NewService(
+		webhooksRepository,
+		cfg.Bars.EncryptionKey,
+		cfg.Webhooks,
+	)
+	accessTokensService := access_tokens.NewService(
+		accessTokensRepository,
+		cfg.API,
+	)
+	feedTokensService := feed_tokens.NewService(
+		feedTokensRepository,
+		cfg.Bars.EncryptionKey,
+	)
+	calendarService, err := calendar.NewService(
+		feedTokensService,
+		userService,
+		userSettingsService,
+		cfg.API.PublicURL,
+		cfg.Calendar,
+	)
+	if err != nil {
+		log.Fatalf("cant initialize calendar service: %v", err)
+	}
+	rendererService, err := renderer.NewService(cfg.Render)
+	if err != nil {
+		log.Fatalf("cant initialize renderer service: %v", err)
+	}
+	gradesFeedService := grades_feed.NewService(
+		feedTokensService,
+		userService,
+		userSettingsService,
+		rendererService,
+		cfg.API.PublicURL,
+		cfg.GradesFeed,
+	)
+	gradeDistributionsService, err := grade_distributions.NewService(
+		gradeDistributionsRepository,
+		userService,
+		userSettingsService,
+		cfg.Distributions,
+	)
+	if err != nil {
+		log.Fatalf("cant initialize grade distributions service: %v", err)
+	}
+	chatsService := chats.NewService(
+		chatsRepository,
+		cfg.GroupChats,
+	)
+	broadcastsService := broadcasts.NewService(broadcastsRepository)
+	authorizationFailuresService := authorization_failures.NewService(
+		authorizationFailuresRepository,
+		cfg.Bars,
+	)
 	barsService := bars.NewService(
 		userService,
+		authorizationFailuresService,
+		cfg.Bars,
+	)
+	scheduleService := schedule.NewService(
+		userService,
+		barsService,
 		cfg.Bars,
 	)
 	telegramService, err := telegram.NewService(
 		userService,
 		barsService,
+		authorizationFailuresService,
+		scheduleService,
+		userSettingsService,
+		rendererService,
+		webhooksService,
+		accessTokensService,
+		calendarService,
+		gradesFeedService,
+		gradeDistributionsService,
+		chatsService,
+		broadcastsService,
 		cfg.Telegram,
 	)
 	if err != nil {
@@ -57,17 +163,50 @@ func main() {
 		telegramService,
 		barsService,
 		userService,
-		authorizationFailedRetriesCountCache,
+		authorizationFailuresService,
+		userSettingsService,
 		cfg.Bars,
 	)
 	gradesChangesOutboxService := grades_changes_outbox.NewService(
 		gradesChangesOutboxRepository,
 		telegramService,
+		emailService,
+		webhooksService,
+		chatsService,
+		userSettingsService,
+		rendererService,
 		cfg.Bars,
 	)
+	remindersService, err := reminders.NewService(
+		remindersRepository,
+		telegramService,
+		rendererService,
+		cfg.Calendar,
+		cfg.Reminders,
+	)
+	if err != nil {
+		log.Fatalf("cant initialize reminders service: %v", err)
+	}
+	broadcastsSenderService := broadcasts_sender.NewService(
+		broadcastsService,
+		telegramService,
+		userSettingsService,
+		cfg.Broadcasts,
+	)
+
+	apiService := api.NewService(
+		userService,
+		accessTokensService,
+		calendarService,
+		gradesFeedService,
+		cfg.API,
+	)
 
+	go apiService.Start()
 	go gradesChangesOutboxService.Start()
-	go authorizationFailedRetriesCountCache.Start()
+	go remindersService.Start()
+	go broadcastsSenderService.Start()
+	go gradeDistributionsService.Start()
 	go gradesChangesService.Start()
 	telegramService.Start()
 
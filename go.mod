@@ -4,11 +4,13 @@ go 1.21.6
 
 require (
 	github.com/PuerkitoBio/goquery v1.8.0
+	github.com/go-pdf/fpdf v0.9.0
 	github.com/ilyakaznacheev/cleanenv v1.2.6
 	github.com/jackc/pgconn v1.12.1
 	github.com/jackc/pgx/v4 v4.16.1
 	github.com/jellydator/ttlcache/v3 v3.2.0
 	github.com/rs/zerolog v1.32.0
+	golang.org/x/image v0.15.0
 	gopkg.in/telebot.v3 v3.0.0
 )
 
@@ -1,36 +1,275 @@
+// Package answers каталог пользовательских текстов. Тексты на каждом языке лежат в отдельном файле,
+// при отсутствии перевода используется русский текст
 package answers
 
+import (
+	"fmt"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Key int
+
 const (
-	Start = "Привет! Бот позволяет взаимодействовать с БАРС в телеграм." +
-		" Вы можете смотреть оценки в удобной форме и получать уведомления об их изменениях. " +
-		"Информация – /help.\n\nБот не является официальной разработкой НИУ «МЭИ»."
-	Help = "/auth Логин Пароль – авторизация в БАРС;\n" +
-		"/pt – просмотр оценок в удобной форме;\n" +
-		"/logout – удалить свои данные;\n" +
-		"/gh – github репозиторий." +
-		"\n\nСвязь / предложения / помощь: @dbrvskwork"
-	Default                 = "Я понимаю только команды из списка: /help."
-	BotError                = "Внутренняя ошибка бота, попробуйте позже."
-	CredentialsFormIgnored  = "Данные введены не по форме. Для авторизации введите /auth Логин Пароль."
-	CredentialsNoEntered    = "Данные для авторизации в БАРС не введены. Для авторизации введите /auth Логин Пароль."
-	CredentialsIncorrectly  = "Введённые данные некорректны. Для авторизации введите /auth Логин Пароль."
-	CredentialsWrong        = "Ошибка авторизации. Вероятно, введён неверный логин и/или пароль."
-	CredentialsExpired      = "Авторизационные данные устарели. Для отслеживания изменений оценок выполните авторизацию повторно. Возможно, возникла ошибка на сервере БАРС."
-	ClientNotAuthorized     = "Вы не авторизованы в БАРС. Для авторизации введите: /auth Логин Пароль."
-	ClientAlreadyAuthorized = "Вы уже авторизованы в БАРС. Для повторной авторизации введите /logout, затем /auth Логин Пароль."
-	SuccessfulAuthorization = "Авторизация в БАРС выполнена успешно. Теперь Вы будете получать уведомления об изменениях оценок."
-	SuccessfulLogout        = "Ваши данные успешно удалены. Для авторизации введите /auth Логин Пароль."
-	GradesPageWrong         = "Бот не может получить Ваши оценки, воспользуйтесь командой /fixgrades для получения инструкции по исправлению ошибки."
-	GradesPageNotProvided   = "Ваши оценки не были получены, попробуйте позже или напишите обращение в поддержку бота."
-	GradesPageUnavailable   = "Данные о Вашей успеваемости пока недоступны. Скорее всего они появятся позже."
-	Github                  = "Github репозиторий бота: [ссылка](github.com/ilyadubrovsky/tracking-bars)."
-	FixGrades               = "Ваши оценки не могут быть получены, поскольку страница с оценками не является основной страницей в Вашем аккаунте БАРС." +
-		"\n\n*Для того, чтобы это исправить и бот заработал, выполните следующие действия:*\n" +
-		"*1.* Зайдите в БАРС (через браузер телефона, компьютера или иным способом);\n" +
-		"*2.* Зайдите на страницу оценок (именно в раздел \"Оценки БАРС\", а не \"Сводка\";\n" +
-		"*3.* Нажмите на значок шестерёнки в верхнем меню страницы (правый верхний угол), затем на кнопку \"Установить\";\n" +
-		"*4.* Выполните авторизацию в боте повторно, всё должно заработать.\n\n" +
-		"Если возникнут вопросы или эти действия не помогут, Вы можете обратиться по контакту в /help."
-	AdminInvalidArgument = "Неправильно указаны аргументы."
-	AdminSuccess         = "Успешно!"
+	Start Key = iota
+	Help
+	Default
+	BotError
+	CredentialsFormIgnored
+	CredentialsNoEntered
+	CredentialsIncorrectly
+	CredentialsWrong
+	CredentialsExpired
+	CredentialsDeletionWarning
+	CredentialsSuspended
+	SuspensionExpired
+	ReauthorizationButton
+	ReauthorizationPrompt
+	ReauthorizationNotNeeded
+	ClientNotAuthorized
+	ClientAlreadyAuthorized
+	SuccessfulAuthorization
+	SuccessfulLogout
+	GradesPageWrong
+	GradesPageNotProvided
+	GradesPageUnavailable
+	SemestersUnavailable
+	SemesterChoose
+	SemestersButton
+	RecordBookUnavailable
+	RecordBookEmpty
+	ScheduleUnavailable
+	ScheduleTodayButton
+	ScheduleTomorrowButton
+	ScheduleWeekButton
+	ExportChooseFormat
+	ExportWithHistoryButton
+	ProgressTableImageButton
+	ProgressTablePDFButton
+	TrendChartButton
+	TrendsChartButton
+	TrendsChartTitle
+	TrendsChartUnavailable
+	ForecastButton
+	LanguageChoose
+	LanguageChanged
+	LanguageUnknown
+	EmailUsage
+	EmailStatus
+	EmailStatusVerified
+	EmailStatusNotVerified
+	EmailInvalid
+	EmailCodeSent
+	EmailSendFailed
+	EmailResendTooSoon
+	EmailCodesLimit
+	VerifyUsage
+	EmailVerified
+	VerificationNotFound
+	VerificationExpired
+	VerificationWrongCode
+	VerificationAttemptsEnded
+	NotifyChoose
+	NotifyChanged
+	NotifyUnknown
+	NotifyEmailNotVerified
+	NotifyTelegramButton
+	NotifyEmailButton
+	NotifyBothButton
+	WebhooksUsage
+	WebhooksEmpty
+	WebhooksHeader
+	WebhooksItem
+	WebhookAdded
+	WebhookInvalidURL
+	WebhooksLimitReached
+	WebhookNotFound
+	WebhookRemoved
+	WebhookTestSucceeded
+	WebhookTestFailed
+	WebhookTestButton
+	WebhookRemoveButton
+	TokensUsage
+	TokensEmpty
+	TokensHeader
+	TokensItem
+	TokenNeverUsed
+	TokenIssued
+	TokensLimitReached
+	TokenNotFound
+	TokenRevoked
+	CalendarFeedURL
+	CalendarFeedRotated
+	CalendarUsage
+	CalendarName
+	CalendarEventWeek
+	CalendarEventGrade
+	GradesFeedURL
+	GradesFeedRotated
+	GradesFeedUsage
+	GradesFeedTitle
+	GradesFeedEntryTitle
+	ReminderDueWeek
+	ReminderSettings
+	ReminderSummaryOn
+	ReminderSummaryOff
+	ReminderDaysOn
+	ReminderDaysOff
+	ReminderSummaryEnableButton
+	ReminderSummaryDisableButton
+	ReminderDaysButton
+	ReminderDaysOffButton
+	ReminderUsage
+	CourseMedian
+	ShareSettings
+	ShareOn
+	ShareOff
+	ShareEnableButton
+	ShareDisableButton
+	ShareUsage
+	AdminDistributionsHeader
+	AdminDistribution
+	AdminNoDistributions
+	GroupHelp
+	GroupJoined
+	GroupLeft
+	GroupNotMember
+	GroupNotAuthorized
+	GroupOnlyCommand
+	InlineNotAuthorized
+	InlineNoGrades
+	InlineDisciplineDescription
+
+	EmailVerificationSubject
+	EmailVerificationBody
+	EmailGradeChangeSubject
+
+	GradeNotGraded
+	RecordBookGradeChange
+
+	ControlEventShortName
+
+	StatisticsNoGrades
+
+	// WeekdaySunday и следующие за ним ключи идут в порядке time.Weekday
+	WeekdaySunday
+	WeekdayMonday
+	WeekdayTuesday
+	WeekdayWednesday
+	WeekdayThursday
+	WeekdayFriday
+	WeekdaySaturday
+
+	ReportTitle
+	ReportSemesterTitle
+	ReportDisciplineColumn
+	ReportCurrentControlColumn
+	ReportIntermediateAttestationColumn
+	ReportFinalGradeColumn
+	ReportGeneratedAt
+	ReportSummary
+	ReportAverage
+	ReportEventsCount
+	ReportAtRiskHeader
+
+	ExportSemesterColumn
+	ExportDisciplineColumn
+	ExportControlEventColumn
+	ExportGradeColumn
+	ExportScoreColumn
+	ExportWeightColumn
+	ExportWeekColumn
+	ExportDateColumn
+	ExportOldGradeColumn
+	ExportNewGradeColumn
+	ExportHistorySheet
+	ExportDefaultSheet
+
+	AdminInvalidArgument
+	AdminSuccess
+	AdminNoAuthorizationFailures
+	AdminBroadcastUsage
+	AdminBroadcastCreated
+	AdminBroadcastsEmpty
+	AdminBroadcastsHeader
+	AdminBroadcast
+	AdminBroadcastStatusActive
+	AdminBroadcastStatusPaused
+	AdminBroadcastStatusCancelled
+	AdminBroadcastStatusCompleted
+	AdminBroadcastNotFound
+	AdminBroadcastStatusConflict
+	AdminBroadcastPaused
+	AdminBroadcastResumed
+	AdminBroadcastCancelled
+	AdminBroadcastReport
+	AdminMessageSent
+	AdminAuthorizationFailuresHeader
+	AdminAuthorizationFailuresMore
+	AdminAuthorizationFailure
+	AdminUserNotFound
+	AdminUserStatus
+	AdminUserStateNotAuthorized
+	AdminUserStateAuthorized
+	AdminUserStateSuspended
+	AdminUserStateDeleted
+	AdminNone
+	AdminCheckRequested
+	AdminCheckNotTracked
+	AdminLogoutDone
+	AdminUsersCounts
+	LogoutByAdmin
 )
+
+var catalogs = map[domain.Language]map[Key]string{
+	domain.LanguageRussian: ru,
+	domain.LanguageEnglish: en,
+}
+
+// LanguageNames названия языков для кнопок выбора, каждое на своём языке
+var LanguageNames = map[domain.Language]string{
+	domain.LanguageRussian: "Русский",
+	domain.LanguageEnglish: "English",
+}
+
+func Text(language domain.Language, key Key) string {
+	if text, ok := catalogs[language][key]; ok {
+		return text
+	}
+
+	return ru[key]
+}
+
+func Textf(language domain.Language, key Key, args ...interface{}) string {
+	return fmt.Sprintf(Text(language, key), args...)
+}
+
+// IsText проверяет, совпадает ли text с текстом key хотя бы на одном языке.
+// Нужно для ответов на сообщения бота, отправленные до смены языка
+func IsText(key Key, text string) bool {
+	for _, catalog := range catalogs {
+		if catalog[key] == text {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Grade текст оценки для пользователя: в Raw хранится текст из БАРС, кроме невыставленной оценки
+func Grade(language domain.Language, grade domain.Grade) string {
+	if !grade.IsGraded() {
+		return Text(language, GradeNotGraded)
+	}
+
+	return grade.Raw
+}
+
+// ControlEvent название мероприятия изменения для пользователя: у изменений зачётной книжки добавляется семестр
+func ControlEvent(language domain.Language, gradeChange *domain.GradeChange) string {
+	if !gradeChange.RecordBook {
+		return gradeChange.ControlEvent
+	}
+
+	return Textf(language, RecordBookGradeChange, gradeChange.ControlEvent, gradeChange.Semester)
+}
@@ -0,0 +1,260 @@
+package answers
+
+var en = map[Key]string{
+	Start: "Hi! This bot brings BARS to Telegram." +
+		" You can view your grades in a convenient form and get notified when they change. " +
+		"More information – /help.\n\nThe bot is not an official MPEI product.",
+	Help: "/auth Login Password – sign in to BARS;\n" +
+		"/pt – view your grades;\n" +
+		"/stats – academic statistics;\n" +
+		"/record – record book;\n" +
+		"/schedule – class schedule;\n" +
+		"/export – export grades to CSV, JSON or XLSX;\n" +
+		"/lang – interface language / язык;\n" +
+		"/email Address – get notifications by email;\n" +
+		"/notify – where to send notifications: Telegram, email or both;\n" +
+		"/webhooks – send grade changes to your own services;\n" +
+		"/token – API access tokens;\n" +
+		"/calendar – calendar of control events;\n" +
+		"/feed – grade changes feed for RSS readers;\n" +
+		"/remind – control event reminders;\n" +
+		"/share – anonymous course statistics;\n" +
+		"/join – in a study group chat: post when grades are published;\n" +
+		"@trackingbarsbot Discipline – in any chat: share your grades for a discipline;\n" +
+		"/logout – delete your data;\n" +
+		"/gh – github repository." +
+		"\n\nContact / suggestions / help: @dbrvskwork",
+	Default:                "I only understand the commands from the list: /help.",
+	BotError:               "Internal bot error, please try again later.",
+	CredentialsFormIgnored: "The data does not match the format. To sign in, send /auth Login Password.",
+	CredentialsNoEntered:   "BARS credentials were not provided. To sign in, send /auth Login Password.",
+	CredentialsIncorrectly: "The provided data is invalid. To sign in, send /auth Login Password.",
+	CredentialsWrong:       "Sign-in failed. The login and/or password are probably wrong.",
+	CredentialsExpired:     "Your credentials are out of date. Sign in again to keep tracking grade changes. The BARS server may also be experiencing problems.",
+	CredentialsDeletionWarning: "Unable to get your grades from BARS (failed attempts in a row: %d of %d). " +
+		"If the next check fails too, grade tracking will be paused. " +
+		"Make sure your BARS login and password have not changed and the grades page is your main page (/fixgrades).",
+	CredentialsSuspended: "Grade tracking is paused. Your saved grades are kept: after you sign in again " +
+		"you will receive every change made in the meantime. If you do not sign in within %d days, your data will be deleted.",
+	SuspensionExpired:         "BARS sign-in was not restored, so your data has been deleted. To sign in, send /auth Login Password.",
+	ReauthorizationButton:     "Enter password again",
+	ReauthorizationPrompt:     "Send your BARS password as a reply to this message.",
+	ReauthorizationNotNeeded:  "No need to sign in again.",
+	ClientNotAuthorized:       "You are not signed in to BARS. To sign in, send: /auth Login Password.",
+	ClientAlreadyAuthorized:   "You are already signed in to BARS. To sign in again, send /logout, then /auth Login Password.",
+	SuccessfulAuthorization:   "Signed in to BARS successfully. You will now be notified about grade changes.",
+	SuccessfulLogout:          "Your data has been deleted. To sign in, send /auth Login Password.",
+	GradesPageWrong:           "The bot cannot get your grades, use /fixgrades for instructions on how to fix it.",
+	GradesPageNotProvided:     "Your grades could not be retrieved, try again later or contact the bot support.",
+	GradesPageUnavailable:     "Your grades are not available yet. They will most likely appear later.",
+	SemestersUnavailable:      "The list of semesters is not available yet, please try again later.",
+	SemesterChoose:            "Choose a semester:",
+	SemestersButton:           "Semesters",
+	RecordBookUnavailable:     "The record book is unavailable. It may not be filled in BARS yet.",
+	RecordBookEmpty:           "There are no grades in the record book yet.",
+	ScheduleUnavailable:       "The schedule is unavailable, please try again later.",
+	ScheduleTodayButton:       "Today",
+	ScheduleTomorrowButton:    "Tomorrow",
+	ScheduleWeekButton:        "Week",
+	ExportChooseFormat:        "Choose a file format:",
+	ExportWithHistoryButton:   "%s + history",
+	ProgressTableImageButton:  "As image",
+	ProgressTablePDFButton:    "PDF report",
+	TrendChartButton:          "Chart",
+	TrendsChartButton:         "Overall chart",
+	TrendsChartTitle:          "Score trends by discipline",
+	TrendsChartUnavailable:    "There is not enough grade history for a chart yet: it builds up as new grades arrive.",
+	ForecastButton:            "Forecast",
+	LanguageChanged:           "Interface language: English.",
+	LanguageUnknown:           "Unknown language. Available languages: ru, en.",
+	EmailUsage:                "To get notifications by email, send /email Address, then confirm the address with /verify Code from the email.",
+	EmailStatus:               "Email: %s (%s).\nNotification channels: %s.\n\nChange the address: /email Address, choose channels: /notify.",
+	EmailStatusVerified:       "verified",
+	EmailStatusNotVerified:    "not verified",
+	EmailInvalid:              "Invalid email address. Send /email Address, for example /email student@mpei.ru.",
+	EmailCodeSent:             "A verification code has been sent to %s. Send /verify Code.",
+	EmailSendFailed:           "Failed to send an email to this address, please try again later.",
+	EmailResendTooSoon:        "A code has just been sent. If the email has not arrived, request a new code a bit later.",
+	EmailCodesLimit:           "Too many verification codes have been sent, please try again tomorrow.",
+	VerifyUsage:               "Send /verify Code from the email.",
+	EmailVerified:             "The address %s is verified. To get notifications by email, choose the channel: /notify.",
+	VerificationNotFound:      "There is no address awaiting verification. Send /email Address.",
+	VerificationExpired:       "The code has expired. Request a new one: /email Address.",
+	VerificationWrongCode:     "Wrong code, please try again.",
+	VerificationAttemptsEnded: "No attempts left, please try again tomorrow.",
+	NotifyChoose:              "Where should grade change notifications be sent?",
+	NotifyChanged:             "Notification channels: %s.",
+	NotifyUnknown:             "Unknown channel. Send /notify telegram, /notify email or /notify both.",
+	NotifyEmailNotVerified:    "Add and verify your email first: /email Address.",
+	NotifyTelegramButton:      "Telegram",
+	NotifyEmailButton:         "Email",
+	NotifyBothButton:          "Both",
+	WebhooksUsage: "Grade changes are sent to every webhook as a POST request with JSON. " +
+		"The X-Tracking-Bars-Signature header contains sha256= and the HMAC-SHA256 of the body keyed with the webhook secret.\n\n" +
+		"/webhooks add https://address – add a webhook;\n" +
+		"/webhooks test Number – send a test request;\n" +
+		"/webhooks remove Number – remove a webhook.",
+	WebhooksEmpty:        "You have no webhooks yet.",
+	WebhooksHeader:       "Your webhooks:",
+	WebhooksItem:         "#%d %s",
+	WebhookAdded:         "Webhook #%d added: %s\nSecret for signature verification: %s\n\nThe secret will not be shown again, save it.",
+	WebhookInvalidURL:    "Invalid address. Only https:// addresses are supported.",
+	WebhooksLimitReached: "The maximum number of webhooks has been reached. Remove the ones you do not need: /webhooks remove Number.",
+	WebhookNotFound:      "Webhook not found. List of webhooks: /webhooks.",
+	WebhookRemoved:       "Webhook #%d removed.",
+	WebhookTestSucceeded: "The test request to webhook #%d was delivered.",
+	WebhookTestFailed:    "Failed to deliver the test request to webhook #%d: %s",
+	WebhookTestButton:    "Test #%d",
+	WebhookRemoveButton:  "Remove #%d",
+	TokensUsage: "A token gives read-only access to your grades through the API: " +
+		"pass it in the Authorization: Bearer Token header. API description: /openapi.yaml on the bot server.\n\n" +
+		"/token new – issue a token;\n" +
+		"/token revoke Number – revoke a token.",
+	TokensEmpty:        "You have no tokens yet.",
+	TokensHeader:       "Your tokens:",
+	TokensItem:         "#%d issued %s, last used: %s",
+	TokenNeverUsed:     "never",
+	TokenIssued:        "Token #%d:\n%s\n\nThe token will not be shown again, save it. To revoke it: /token revoke %d.",
+	TokensLimitReached: "The maximum number of tokens has been reached. Revoke the ones you do not need: /token revoke Number.",
+	TokenNotFound:      "Token not found. List of tokens: /token.",
+	TokenRevoked:       "Token #%d revoked.",
+	CalendarFeedURL: "Link to the calendar of control events:\n%s\n\n" +
+		"Add it to your calendar app as a subscription by URL. " +
+		"The link is secret: if someone else got it, issue a new one with /calendar rotate.",
+	CalendarFeedRotated: "New calendar link:\n%s\n\nThe old link no longer works, update the subscription in your calendar app.",
+	CalendarUsage:       "Usage: /calendar – calendar link, /calendar rotate – issue a new link.",
+	CalendarName:        "BARS: control events",
+	CalendarEventWeek:   "Semester week: %d",
+	CalendarEventGrade:  "Grade: %s",
+	GradesFeedURL: "Link to the Atom feed of grade changes:\n%s\n\n" +
+		"Add it to your RSS reader. " +
+		"The link is secret: if someone else got it, issue a new one with /feed rotate.",
+	GradesFeedRotated:    "New feed link:\n%s\n\nThe old link no longer works, update the subscription in your reader.",
+	GradesFeedUsage:      "Usage: /feed – feed link, /feed rotate – issue a new link.",
+	GradesFeedTitle:      "BARS: grade changes",
+	GradesFeedEntryTitle: "%s: %s",
+	ReminderDueWeek:      "week %d, %s",
+	ReminderSettings: "Reminders about control events that are not graded yet.\n\n" +
+		"Weekly summary on Mondays: %s.\n" +
+		"Reminder before an event: %s.",
+	ReminderSummaryOn:            "on",
+	ReminderSummaryOff:           "off",
+	ReminderDaysOn:               "%d day(s) before",
+	ReminderDaysOff:              "off",
+	ReminderSummaryEnableButton:  "Turn summary on",
+	ReminderSummaryDisableButton: "Turn summary off",
+	ReminderDaysButton:           "%d day(s) before",
+	ReminderDaysOffButton:        "Don't remind",
+	ReminderUsage: "Usage: /remind – reminder settings, /remind summary on|off – weekly summary, " +
+		"/remind days N – how many days before an event to remind (0 to 14, 0 turns reminders off).",
+
+	CourseMedian: "course median %s, middle half %s to %s (%d students)",
+	ShareSettings: "Anonymous course statistics: %s.\n\n" +
+		"Participants anonymously share their control event scores and see the course median next to their grade in /pt. " +
+		"A distribution is published only when enough students have a score for the event. " +
+		"Only the rounded median and quartiles and the number of participants are published, without grades or names.",
+	ShareOn:                  "you participate",
+	ShareOff:                 "you don't participate",
+	ShareEnableButton:        "Participate",
+	ShareDisableButton:       "Don't participate",
+	ShareUsage:               "Usage: /share – statistics settings, /share on|off – participate or not.",
+	AdminDistributionsHeader: "Score distributions (%d):\n\n",
+	AdminDistribution:        "%s | %s | %s\nquartiles %.2f / %.2f / %.2f, participants %d\n\n",
+	AdminNoDistributions:     "No published distributions.",
+	GroupHelp: "I post to this chat when grades for a control event of a discipline are published. " +
+		"Neither the grades nor whose they are get into the chat.\n\n" +
+		"/join – post to the chat when my grades are published (requires BARS authorization in a private chat with the bot);\n" +
+		"/leave – stop posting about my grades.",
+	GroupJoined:                 "From now on this chat will be notified when your grades are published, without the grades themselves.",
+	GroupLeft:                   "This chat will no longer be notified about your grades.",
+	GroupNotMember:              "You haven't connected this chat. To connect it, send /join.",
+	GroupNotAuthorized:          "Authorize in BARS in a private chat with the bot first, don't enter your password here.",
+	GroupOnlyCommand:            "This command only works in a group chat: add the bot to your study group chat and send /join there.",
+	InlineNotAuthorized:         "Authorize in BARS to share your grades",
+	InlineNoGrades:              "Grades haven't been received from BARS yet",
+	InlineDisciplineDescription: "Average grade: %s (graded: %d, pending: %d)",
+	EmailVerificationSubject:    "tracking-bars verification code",
+	EmailVerificationBody: "Your verification code: %s\n\nSend /verify %s to the bot. The code is valid for %d min.\n" +
+		"If you did not request a code, just ignore this email.",
+	EmailGradeChangeSubject: "Grade change: %s",
+
+	GradeNotGraded:        "not graded",
+	RecordBookGradeChange: "%s (record book, %s)",
+
+	ControlEventShortName: "CE-%d",
+
+	StatisticsNoGrades: "no grades",
+
+	WeekdaySunday:    "Sun",
+	WeekdayMonday:    "Mon",
+	WeekdayTuesday:   "Tue",
+	WeekdayWednesday: "Wed",
+	WeekdayThursday:  "Thu",
+	WeekdayFriday:    "Fri",
+	WeekdaySaturday:  "Sat",
+
+	ReportTitle:                         "Grades",
+	ReportSemesterTitle:                 "Grades: %s",
+	ReportDisciplineColumn:              "Discipline",
+	ReportCurrentControlColumn:          "Current",
+	ReportIntermediateAttestationColumn: "Exam",
+	ReportFinalGradeColumn:              "Final",
+	ReportGeneratedAt:                   "Generated %s",
+	ReportSummary:                       "Summary",
+	ReportAverage:                       "Average grade: %s",
+	ReportEventsCount:                   "Grades received: %d, pending: %d",
+	ReportAtRiskHeader:                  "At risk:",
+
+	ExportSemesterColumn:     "Semester",
+	ExportDisciplineColumn:   "Discipline",
+	ExportControlEventColumn: "Control event",
+	ExportGradeColumn:        "Grade",
+	ExportScoreColumn:        "Score",
+	ExportWeightColumn:       "Weight",
+	ExportWeekColumn:         "Week",
+	ExportDateColumn:         "Date",
+	ExportOldGradeColumn:     "Old grade",
+	ExportNewGradeColumn:     "New grade",
+	ExportHistorySheet:       "History",
+	ExportDefaultSheet:       "Sheet",
+
+	AdminInvalidArgument:         "Invalid arguments.",
+	AdminSuccess:                 "Done!",
+	AdminNoAuthorizationFailures: "There are no failed authorization attempts.",
+	AdminBroadcastUsage: "/abroadcast – recent broadcasts;\n" +
+		"/abroadcast send Audience[:language] Text – new broadcast, audience: all, authorized, not_authorized, suspended, language: ru, en;\n" +
+		"/abroadcast Number – broadcast progress;\n" +
+		"/abroadcast pause|resume|cancel Number – pause, resume or cancel a broadcast.",
+	AdminBroadcastCreated:            "Broadcast #%d created, recipients: %d. Progress: /abroadcast %d.",
+	AdminBroadcastsEmpty:             "There have been no broadcasts yet.",
+	AdminBroadcastsHeader:            "Recent broadcasts:\n\n",
+	AdminBroadcast:                   "#%d – %s\nAudience: %s, created %s\nSent: %d of %d, failed: %d, queued: %d\n%s\n\n",
+	AdminBroadcastStatusActive:       "sending",
+	AdminBroadcastStatusPaused:       "paused",
+	AdminBroadcastStatusCancelled:    "cancelled",
+	AdminBroadcastStatusCompleted:    "completed",
+	AdminBroadcastNotFound:           "Broadcast not found.",
+	AdminBroadcastStatusConflict:     "Action unavailable: broadcast #%d is %s.",
+	AdminBroadcastPaused:             "Broadcast #%d paused. To resume: /abroadcast resume %d.",
+	AdminBroadcastResumed:            "Broadcast #%d will resume within seconds.",
+	AdminBroadcastCancelled:          "Broadcast #%d cancelled, sent: %d of %d.",
+	AdminBroadcastReport:             "Broadcast #%d completed: sent %d of %d, failed: %d.",
+	AdminMessageSent:                 "Message sent to user %d:\n%s",
+	AdminAuthorizationFailuresHeader: "Failed authorization attempts (%d):\n\n",
+	AdminAuthorizationFailuresMore:   "...and %d more",
+	AdminAuthorizationFailure:        "%d – attempts: %d, first: %s, last: %s\n%s\n\n",
+	AdminUserNotFound:                "User not found.",
+	AdminUserStatus: "User %d\nState: %s\nBARS username: %s\nRegistered: %s\n" +
+		"Last successful check: %s\nCheck requested: %s\n" +
+		"Failed attempts in a row: %d\nLast error: %s\nUndelivered changes: %d\n\n",
+	AdminUserStateNotAuthorized: "not authorized",
+	AdminUserStateAuthorized:    "authorized",
+	AdminUserStateSuspended:     "tracking suspended %s",
+	AdminUserStateDeleted:       "deleted",
+	AdminNone:                   "none",
+	AdminCheckRequested:         "Grades check for user %d is scheduled, the result will be shown in /auser %d.",
+	AdminCheckNotTracked:        "Grades of user %d aren't tracked now: the user isn't authorized, is deleted or tracking is suspended.",
+	AdminLogoutDone:             "Data of user %d has been deleted.",
+	AdminUsersCounts:            "Users\nregistered: %d\nauthorized: %d\nsuspended: %d\ndeleted: %d",
+	LogoutByAdmin:               "Your data has been deleted by the bot administrator. To sign in, send /auth Login Password.",
+}
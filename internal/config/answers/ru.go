@@ -0,0 +1,261 @@
+package answers
+
+var ru = map[Key]string{
+	Start: "Привет! Бот позволяет взаимодействовать с БАРС в телеграм." +
+		" Вы можете смотреть оценки в удобной форме и получать уведомления об их изменениях. " +
+		"Информация – /help.\n\nБот не является официальной разработкой НИУ «МЭИ».",
+	Help: "/auth Логин Пароль – авторизация в БАРС;\n" +
+		"/pt – просмотр оценок в удобной форме;\n" +
+		"/stats – статистика успеваемости;\n" +
+		"/record – зачётная книжка;\n" +
+		"/schedule – расписание занятий;\n" +
+		"/export – выгрузить оценки в CSV, JSON или XLSX;\n" +
+		"/lang – язык интерфейса / language;\n" +
+		"/email Адрес – подключить уведомления на почту;\n" +
+		"/notify – куда присылать уведомления: Telegram, почта или оба канала;\n" +
+		"/webhooks – отправка изменений оценок в свои сервисы;\n" +
+		"/token – токены доступа к API;\n" +
+		"/calendar – календарь контрольных мероприятий;\n" +
+		"/feed – лента изменений оценок для RSS-читалок;\n" +
+		"/remind – напоминания о контрольных мероприятиях;\n" +
+		"/share – обезличенная статистика курса;\n" +
+		"/join – в чате учебной группы: сообщать, что выставлены оценки;\n" +
+		"@trackingbarsbot Дисциплина – в любом чате: поделиться оценками по дисциплине;\n" +
+		"/logout – удалить свои данные;\n" +
+		"/gh – github репозиторий." +
+		"\n\nСвязь / предложения / помощь: @dbrvskwork",
+	Default:                "Я понимаю только команды из списка: /help.",
+	BotError:               "Внутренняя ошибка бота, попробуйте позже.",
+	CredentialsFormIgnored: "Данные введены не по форме. Для авторизации введите /auth Логин Пароль.",
+	CredentialsNoEntered:   "Данные для авторизации в БАРС не введены. Для авторизации введите /auth Логин Пароль.",
+	CredentialsIncorrectly: "Введённые данные некорректны. Для авторизации введите /auth Логин Пароль.",
+	CredentialsWrong:       "Ошибка авторизации. Вероятно, введён неверный логин и/или пароль.",
+	CredentialsExpired:     "Авторизационные данные устарели. Для отслеживания изменений оценок выполните авторизацию повторно. Возможно, возникла ошибка на сервере БАРС.",
+	CredentialsDeletionWarning: "Не удаётся получить Ваши оценки из БАРС (неудачных попыток подряд: %d из %d). " +
+		"Если следующая проверка тоже завершится ошибкой, отслеживание оценок будет приостановлено. " +
+		"Проверьте, что логин и пароль от БАРС не менялись, а страница оценок является основной (/fixgrades).",
+	CredentialsSuspended: "Отслеживание оценок приостановлено. Сохранённые оценки не удалены: после повторной авторизации " +
+		"Вы получите все изменения, произошедшие за это время. Если авторизация не будет выполнена в течение %d дн., Ваши данные будут удалены.",
+	SuspensionExpired:         "Авторизация в БАРС не была восстановлена, поэтому Ваши данные удалены. Для авторизации введите /auth Логин Пароль.",
+	ReauthorizationButton:     "Ввести пароль заново",
+	ReauthorizationPrompt:     "Введите пароль от БАРС ответом на это сообщение.",
+	ReauthorizationNotNeeded:  "Повторная авторизация не требуется.",
+	ClientNotAuthorized:       "Вы не авторизованы в БАРС. Для авторизации введите: /auth Логин Пароль.",
+	ClientAlreadyAuthorized:   "Вы уже авторизованы в БАРС. Для повторной авторизации введите /logout, затем /auth Логин Пароль.",
+	SuccessfulAuthorization:   "Авторизация в БАРС выполнена успешно. Теперь Вы будете получать уведомления об изменениях оценок.",
+	SuccessfulLogout:          "Ваши данные успешно удалены. Для авторизации введите /auth Логин Пароль.",
+	GradesPageWrong:           "Бот не может получить Ваши оценки, воспользуйтесь командой /fixgrades для получения инструкции по исправлению ошибки.",
+	GradesPageNotProvided:     "Ваши оценки не были получены, попробуйте позже или напишите обращение в поддержку бота.",
+	GradesPageUnavailable:     "Данные о Вашей успеваемости пока недоступны. Скорее всего они появятся позже.",
+	SemestersUnavailable:      "Список семестров пока недоступен, попробуйте позже.",
+	SemesterChoose:            "Выберите семестр:",
+	SemestersButton:           "Семестры",
+	RecordBookUnavailable:     "Зачётная книжка недоступна. Возможно, она ещё не заполнена в БАРС.",
+	RecordBookEmpty:           "В зачётной книжке пока нет оценок.",
+	ScheduleUnavailable:       "Расписание недоступно, попробуйте позже.",
+	ScheduleTodayButton:       "Сегодня",
+	ScheduleTomorrowButton:    "Завтра",
+	ScheduleWeekButton:        "Неделя",
+	ExportChooseFormat:        "Выберите формат файла:",
+	ExportWithHistoryButton:   "%s + история",
+	ProgressTableImageButton:  "Картинкой",
+	ProgressTablePDFButton:    "PDF-отчёт",
+	TrendChartButton:          "График",
+	TrendsChartButton:         "Общий график",
+	TrendsChartTitle:          "Динамика баллов по дисциплинам",
+	TrendsChartUnavailable:    "Для графика пока недостаточно истории изменений оценок: она накапливается с момента появления новых оценок.",
+	ForecastButton:            "Прогноз",
+	LanguageChoose:            "Выберите язык / Choose language:",
+	LanguageChanged:           "Язык интерфейса: русский.",
+	LanguageUnknown:           "Неизвестный язык. Доступные языки: ru, en.",
+	EmailUsage:                "Для подключения уведомлений на почту введите /email Адрес, затем подтвердите адрес командой /verify Код из письма.",
+	EmailStatus:               "Почта: %s (%s).\nКаналы уведомлений: %s.\n\nСменить адрес: /email Адрес, выбрать каналы: /notify.",
+	EmailStatusVerified:       "подтверждена",
+	EmailStatusNotVerified:    "не подтверждена",
+	EmailInvalid:              "Некорректный адрес почты. Введите /email Адрес, например /email student@mpei.ru.",
+	EmailCodeSent:             "Код подтверждения отправлен на %s. Введите /verify Код.",
+	EmailSendFailed:           "Не удалось отправить письмо на указанный адрес, попробуйте позже.",
+	EmailResendTooSoon:        "Код только что отправлен. Если письмо не пришло, запросите новый код чуть позже.",
+	EmailCodesLimit:           "Отправлено слишком много кодов подтверждения, попробуйте завтра.",
+	VerifyUsage:               "Введите /verify Код из письма.",
+	EmailVerified:             "Адрес %s подтверждён. Чтобы получать уведомления на почту, выберите канал: /notify.",
+	VerificationNotFound:      "Нет адреса, ожидающего подтверждения. Введите /email Адрес.",
+	VerificationExpired:       "Срок действия кода истёк. Запросите новый код: /email Адрес.",
+	VerificationWrongCode:     "Неверный код, попробуйте ещё раз.",
+	VerificationAttemptsEnded: "Попытки ввода кода закончились, попробуйте завтра.",
+	NotifyChoose:              "Куда присылать уведомления об изменениях оценок?",
+	NotifyChanged:             "Каналы уведомлений: %s.",
+	NotifyUnknown:             "Неизвестный канал. Введите /notify telegram, /notify email или /notify both.",
+	NotifyEmailNotVerified:    "Сначала подключите и подтвердите почту: /email Адрес.",
+	NotifyTelegramButton:      "Telegram",
+	NotifyEmailButton:         "Почта",
+	NotifyBothButton:          "Оба канала",
+	WebhooksUsage: "Изменения оценок отправляются POST-запросом с JSON на каждый вебхук. " +
+		"Заголовок X-Tracking-Bars-Signature содержит sha256= и HMAC-SHA256 тела запроса на секрете вебхука.\n\n" +
+		"/webhooks add https://адрес – добавить вебхук;\n" +
+		"/webhooks test Номер – отправить тестовый запрос;\n" +
+		"/webhooks remove Номер – удалить вебхук.",
+	WebhooksEmpty:        "Вебхуков пока нет.",
+	WebhooksHeader:       "Ваши вебхуки:",
+	WebhooksItem:         "#%d %s",
+	WebhookAdded:         "Вебхук #%d добавлен: %s\nСекрет для проверки подписи: %s\n\nСекрет больше не будет показан, сохраните его.",
+	WebhookInvalidURL:    "Некорректный адрес. Поддерживаются только адреса https://.",
+	WebhooksLimitReached: "Достигнуто максимальное число вебхуков. Удалите ненужные: /webhooks remove Номер.",
+	WebhookNotFound:      "Вебхук не найден. Список вебхуков: /webhooks.",
+	WebhookRemoved:       "Вебхук #%d удалён.",
+	WebhookTestSucceeded: "Тестовый запрос на вебхук #%d доставлен.",
+	WebhookTestFailed:    "Не удалось доставить тестовый запрос на вебхук #%d: %s",
+	WebhookTestButton:    "Проверить #%d",
+	WebhookRemoveButton:  "Удалить #%d",
+	TokensUsage: "Токен даёт доступ к Вашим оценкам через API только для чтения: " +
+		"передавайте его в заголовке Authorization: Bearer Токен. Описание API: /openapi.yaml на сервере бота.\n\n" +
+		"/token new – выпустить токен;\n" +
+		"/token revoke Номер – отозвать токен.",
+	TokensEmpty:        "Токенов пока нет.",
+	TokensHeader:       "Ваши токены:",
+	TokensItem:         "#%d выпущен %s, последнее использование: %s",
+	TokenNeverUsed:     "не использовался",
+	TokenIssued:        "Токен #%d:\n%s\n\nТокен больше не будет показан, сохраните его. Отозвать токен: /token revoke %d.",
+	TokensLimitReached: "Достигнуто максимальное число токенов. Отзовите ненужные: /token revoke Номер.",
+	TokenNotFound:      "Токен не найден. Список токенов: /token.",
+	TokenRevoked:       "Токен #%d отозван.",
+	CalendarFeedURL: "Ссылка на календарь контрольных мероприятий:\n%s\n\n" +
+		"Добавьте её в приложение календаря как подписку по адресу (URL). " +
+		"Ссылка секретная: если она попала к посторонним, выпустите новую командой /calendar rotate.",
+	CalendarFeedRotated: "Новая ссылка на календарь:\n%s\n\nСтарая ссылка больше не работает, обновите подписку в приложении календаря.",
+	CalendarUsage:       "Использование: /calendar – ссылка на календарь, /calendar rotate – выпустить новую ссылку.",
+	CalendarName:        "БАРС: контрольные мероприятия",
+	CalendarEventWeek:   "Неделя семестра: %d",
+	CalendarEventGrade:  "Оценка: %s",
+	GradesFeedURL: "Ссылка на Atom-ленту изменений оценок:\n%s\n\n" +
+		"Добавьте её в RSS-читалку. " +
+		"Ссылка секретная: если она попала к посторонним, выпустите новую командой /feed rotate.",
+	GradesFeedRotated:    "Новая ссылка на ленту:\n%s\n\nСтарая ссылка больше не работает, обновите подписку в читалке.",
+	GradesFeedUsage:      "Использование: /feed – ссылка на ленту, /feed rotate – выпустить новую ссылку.",
+	GradesFeedTitle:      "БАРС: изменения оценок",
+	GradesFeedEntryTitle: "%s: %s",
+	ReminderDueWeek:      "неделя %d, %s",
+	ReminderSettings: "Напоминания о контрольных мероприятиях, которые ещё не оценены.\n\n" +
+		"Сводка на неделю по понедельникам: %s.\n" +
+		"Напоминание перед мероприятием: %s.",
+	ReminderSummaryOn:            "включена",
+	ReminderSummaryOff:           "выключена",
+	ReminderDaysOn:               "за %d дн.",
+	ReminderDaysOff:              "выключено",
+	ReminderSummaryEnableButton:  "Включить сводку",
+	ReminderSummaryDisableButton: "Выключить сводку",
+	ReminderDaysButton:           "За %d дн.",
+	ReminderDaysOffButton:        "Не напоминать",
+	ReminderUsage: "Использование: /remind – настройки напоминаний, /remind summary on|off – сводка на неделю, " +
+		"/remind days N – за сколько дней напоминать о мероприятии (от 0 до 14, 0 – не напоминать).",
+
+	CourseMedian: "медиана курса %s, у половины от %s до %s (%d чел.)",
+	ShareSettings: "Обезличенная статистика курса: %s.\n\n" +
+		"Участники анонимно делятся баллами за контрольные мероприятия и видят в /pt медиану курса рядом со своей оценкой. " +
+		"Распределение по мероприятию публикуется, только когда баллы есть у достаточного числа студентов. " +
+		"Публикуются лишь округлённые медиана и квартили и число участников, без оценок и имён.",
+	ShareOn:                  "вы участвуете",
+	ShareOff:                 "вы не участвуете",
+	ShareEnableButton:        "Участвовать",
+	ShareDisableButton:       "Не участвовать",
+	ShareUsage:               "Использование: /share – настройки статистики, /share on|off – участвовать или нет.",
+	AdminDistributionsHeader: "Распределения баллов (%d):\n\n",
+	AdminDistribution:        "%s | %s | %s\nквартили %.2f / %.2f / %.2f, участников %d\n\n",
+	AdminNoDistributions:     "Опубликованных распределений нет.",
+	GroupHelp: "Я сообщаю в этот чат, когда по дисциплине выставлены оценки за контрольное мероприятие. " +
+		"Сами оценки и то, чья это оценка, в чат не попадают.\n\n" +
+		"/join – сообщать в чат о выставленных мне оценках (нужна авторизация в БАРС в личном чате с ботом);\n" +
+		"/leave – больше не сообщать о моих оценках.",
+	GroupJoined:                 "Теперь в этот чат будут приходить сообщения о выставленных Вам оценках, без самих оценок.",
+	GroupLeft:                   "Сообщения о Ваших оценках больше не будут приходить в этот чат.",
+	GroupNotMember:              "Вы не подключали этот чат. Чтобы подключить, введите /join.",
+	GroupNotAuthorized:          "Сначала авторизуйтесь в БАРС в личном чате с ботом, не вводите пароль здесь.",
+	GroupOnlyCommand:            "Команда работает только в групповом чате: добавьте бота в чат учебной группы и введите там /join.",
+	InlineNotAuthorized:         "Авторизуйтесь в БАРС, чтобы делиться оценками",
+	InlineNoGrades:              "Оценки ещё не получены из БАРС",
+	InlineDisciplineDescription: "Средняя оценка: %s (оценено: %d, ожидается: %d)",
+	EmailVerificationSubject:    "Код подтверждения tracking-bars",
+	EmailVerificationBody: "Ваш код подтверждения: %s\n\nВведите в боте /verify %s. Код действует %d мин.\n" +
+		"Если Вы не запрашивали код, просто проигнорируйте это письмо.",
+	EmailGradeChangeSubject: "Изменение оценки: %s",
+
+	GradeNotGraded:        "отсутствует",
+	RecordBookGradeChange: "%s (зачётная книжка, %s)",
+
+	ControlEventShortName: "КМ-%d",
+
+	StatisticsNoGrades: "нет оценок",
+
+	WeekdaySunday:    "Вс",
+	WeekdayMonday:    "Пн",
+	WeekdayTuesday:   "Вт",
+	WeekdayWednesday: "Ср",
+	WeekdayThursday:  "Чт",
+	WeekdayFriday:    "Пт",
+	WeekdaySaturday:  "Сб",
+
+	ReportTitle:                         "Успеваемость",
+	ReportSemesterTitle:                 "Успеваемость: %s",
+	ReportDisciplineColumn:              "Дисциплина",
+	ReportCurrentControlColumn:          "Текущий",
+	ReportIntermediateAttestationColumn: "Пром. атт.",
+	ReportFinalGradeColumn:              "Итог",
+	ReportGeneratedAt:                   "Сформирован %s",
+	ReportSummary:                       "Сводка",
+	ReportAverage:                       "Средний балл: %s",
+	ReportEventsCount:                   "Оценок выставлено: %d, ожидается: %d",
+	ReportAtRiskHeader:                  "Есть задолженности:",
+
+	ExportSemesterColumn:     "Семестр",
+	ExportDisciplineColumn:   "Дисциплина",
+	ExportControlEventColumn: "Контрольное мероприятие",
+	ExportGradeColumn:        "Оценка",
+	ExportScoreColumn:        "Балл",
+	ExportWeightColumn:       "Вес",
+	ExportWeekColumn:         "Неделя",
+	ExportDateColumn:         "Дата",
+	ExportOldGradeColumn:     "Старая оценка",
+	ExportNewGradeColumn:     "Новая оценка",
+	ExportHistorySheet:       "История",
+	ExportDefaultSheet:       "Лист",
+
+	AdminInvalidArgument:         "Неправильно указаны аргументы.",
+	AdminSuccess:                 "Успешно!",
+	AdminNoAuthorizationFailures: "Неудачных попыток авторизации нет.",
+	AdminBroadcastUsage: "/abroadcast – последние рассылки;\n" +
+		"/abroadcast send Аудитория[:язык] Текст – новая рассылка, аудитория: all, authorized, not_authorized, suspended, язык: ru, en;\n" +
+		"/abroadcast Номер – ход рассылки;\n" +
+		"/abroadcast pause|resume|cancel Номер – приостановить, продолжить или отменить рассылку.",
+	AdminBroadcastCreated:            "Рассылка #%d создана, получателей: %d. Ход рассылки: /abroadcast %d.",
+	AdminBroadcastsEmpty:             "Рассылок ещё не было.",
+	AdminBroadcastsHeader:            "Последние рассылки:\n\n",
+	AdminBroadcast:                   "#%d – %s\nАудитория: %s, создана %s\nОтправлено: %d из %d, ошибок: %d, в очереди: %d\n%s\n\n",
+	AdminBroadcastStatusActive:       "отправляется",
+	AdminBroadcastStatusPaused:       "приостановлена",
+	AdminBroadcastStatusCancelled:    "отменена",
+	AdminBroadcastStatusCompleted:    "завершена",
+	AdminBroadcastNotFound:           "Рассылка не найдена.",
+	AdminBroadcastStatusConflict:     "Действие недоступно: рассылка #%d %s.",
+	AdminBroadcastPaused:             "Рассылка #%d приостановлена. Продолжить: /abroadcast resume %d.",
+	AdminBroadcastResumed:            "Рассылка #%d продолжится в ближайшие секунды.",
+	AdminBroadcastCancelled:          "Рассылка #%d отменена, отправлено: %d из %d.",
+	AdminBroadcastReport:             "Рассылка #%d завершена: отправлено %d из %d, ошибок: %d.",
+	AdminMessageSent:                 "Пользователю %d успешно отправлено сообщение:\n%s",
+	AdminAuthorizationFailuresHeader: "Неудачные попытки авторизации (%d):\n\n",
+	AdminAuthorizationFailuresMore:   "...и ещё %d",
+	AdminAuthorizationFailure:        "%d – попыток: %d, первая: %s, последняя: %s\n%s\n\n",
+	AdminUserNotFound:                "Пользователь не найден.",
+	AdminUserStatus: "Пользователь %d\nСостояние: %s\nЛогин БАРС: %s\nЗарегистрирован: %s\n" +
+		"Последняя успешная проверка: %s\nЗапрошена проверка: %s\n" +
+		"Неудачных попыток подряд: %d\nПоследняя ошибка: %s\nНе доставлено изменений: %d\n\n",
+	AdminUserStateNotAuthorized: "не авторизован",
+	AdminUserStateAuthorized:    "авторизован",
+	AdminUserStateSuspended:     "отслеживание приостановлено %s",
+	AdminUserStateDeleted:       "удалён",
+	AdminNone:                   "нет",
+	AdminCheckRequested:         "Проверка оценок пользователя %d запланирована, результат будет виден в /auser %d.",
+	AdminCheckNotTracked:        "Оценки пользователя %d сейчас не отслеживаются: он не авторизован, удалён или отслеживание приостановлено.",
+	AdminLogoutDone:             "Данные пользователя %d удалены.",
+	AdminUsersCounts:            "Пользователи\nзарегистрировано: %d\nавторизовано: %d\nприостановлено: %d\nудалено: %d",
+	LogoutByAdmin:               "Ваши данные удалены администратором бота. Для авторизации введите /auth Логин Пароль.",
+}
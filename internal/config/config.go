@@ -11,12 +11,27 @@ const (
 	BARSRegistrationPageURL = "https://bars.mpei.ru/bars_web/"
 	BARSMainPageURL         = "https://bars.mpei.ru/bars_web/?sod=1"
 	BARSGradesPageURL       = "https://bars.mpei.ru/bars_web/"
+	BARSRecordBookPageURL   = "https://bars.mpei.ru/bars_web/Student/RecordBook"
+	BARSSchedulePageURL     = "https://bars.mpei.ru/bars_web/Student/Schedule"
 )
 
+// BARSLocation часовой пояс, в котором БАРС показывает даты
+var BARSLocation = time.FixedZone("MSK", 3*60*60)
+
 type Config struct {
-	Telegram Telegram
-	Bars     Bars
-	Postgres Postgres
+	Telegram      Telegram
+	Bars          Bars
+	Postgres      Postgres
+	Render        Render
+	Email         Email
+	Webhooks      Webhooks
+	API           API
+	Calendar      Calendar
+	GradesFeed    GradesFeed
+	Reminders     Reminders
+	Distributions Distributions
+	GroupChats    GroupChats
+	Broadcasts    Broadcasts
 }
 
 func NewConfig() (*Config, error) {
@@ -34,14 +49,107 @@ type Bars struct {
 	CronWorkerDelay                 time.Duration `env:"BARS_CRON_WORKER_DELAY" env-default:"10s"`
 	CronWorkerPoolSize              int           `env:"BARS_CRON_WORKER_POOL_SIZE" env-default:"5"`
 	AuthorizationFailedRetriesCount int           `env:"BARS_AUTHORIZATION_FAILED_RETRIES_COUNT" env-default:"3"`
+	AuthorizationFailedRetriesTTL   time.Duration `env:"BARS_AUTHORIZATION_FAILED_RETRIES_TTL" env-default:"60m"`
+	CredentialsSuspensionPeriod     time.Duration `env:"BARS_CREDENTIALS_SUSPENSION_PERIOD" env-default:"720h"`
 	EncryptionKey                   string        `env:"BARS_ENCRYPTION_KEY"`
 	OutboxCronDelay                 time.Duration `env:"BARS_OUTBOX_CRON_DELAY" env-default:"5m"`
+	// OutboxMaxDeliveryFailures после стольких неудач подряд канал перестаёт доставлять изменение
+	OutboxMaxDeliveryFailures int `env:"BARS_OUTBOX_MAX_DELIVERY_FAILURES" env-default:"10"`
+	// OutboxRetryBackoff пауза после первой неудачной доставки, дальше она удваивается до OutboxMaxRetryBackoff
+	OutboxRetryBackoff    time.Duration `env:"BARS_OUTBOX_RETRY_BACKOFF" env-default:"1m"`
+	OutboxMaxRetryBackoff time.Duration `env:"BARS_OUTBOX_MAX_RETRY_BACKOFF" env-default:"6h"`
+	ScheduleCacheTTL      time.Duration `env:"BARS_SCHEDULE_CACHE_TTL" env-default:"12h"`
+	// CheckRequestsDelay как часто выполняются внеочередные проверки, запрошенные администратором
+	CheckRequestsDelay time.Duration `env:"BARS_CHECK_REQUESTS_DELAY" env-default:"30s"`
 }
 
 type Telegram struct {
 	BotToken        string        `env:"TELEGRAM_BOT_TOKEN"`
 	LongPollerDelay time.Duration `env:"TELEGRAM_LONG_POLLER_DELAY" env-default:"60s"`
 	AdminID         int64         `env:"TELEGRAM_ADMIN_ID"`
+	// InlineCacheTTL сколько inline-ответ с оценками хранится в кэше бота и телеграма
+	InlineCacheTTL time.Duration `env:"TELEGRAM_INLINE_CACHE_TTL" env-default:"1m"`
+	// LanguageCacheTTL сколько язык пользователя хранится в памяти бота без повторного чтения из БД
+	LanguageCacheTTL time.Duration `env:"TELEGRAM_LANGUAGE_CACHE_TTL" env-default:"1h"`
+}
+
+type Render struct {
+	// TemplatesDir каталог с шаблонами сообщений, переопределяющими встроенные
+	TemplatesDir string `env:"RENDER_TEMPLATES_DIR"`
+}
+
+// Email по умолчанию настроен на локальный SMTP-стенд без авторизации и TLS, например Mailpit
+type Email struct {
+	SMTPHost     string        `env:"SMTP_HOST" env-default:"localhost"`
+	SMTPPort     int           `env:"SMTP_PORT" env-default:"1025"`
+	SMTPUsername string        `env:"SMTP_USERNAME"`
+	SMTPPassword string        `env:"SMTP_PASSWORD"`
+	SMTPTimeout  time.Duration `env:"SMTP_TIMEOUT" env-default:"15s"`
+	From         string        `env:"EMAIL_FROM" env-default:"tracking-bars@localhost"`
+	// VerificationCodeTTL время жизни кода подтверждения адреса
+	VerificationCodeTTL time.Duration `env:"EMAIL_VERIFICATION_CODE_TTL" env-default:"15m"`
+	// VerificationMaxAttempts попыток ввода кода за сутки, включая все запрошенные коды
+	VerificationMaxAttempts int `env:"EMAIL_VERIFICATION_MAX_ATTEMPTS" env-default:"5"`
+	// VerificationResendInterval через сколько после отправки кода можно запросить новый
+	VerificationResendInterval time.Duration `env:"EMAIL_VERIFICATION_RESEND_INTERVAL" env-default:"1m"`
+	VerificationMaxCodesPerDay int           `env:"EMAIL_VERIFICATION_MAX_CODES_PER_DAY" env-default:"5"`
+}
+
+type Webhooks struct {
+	Timeout    time.Duration `env:"WEBHOOKS_TIMEOUT" env-default:"10s"`
+	MaxPerUser int           `env:"WEBHOOKS_MAX_PER_USER" env-default:"5"`
+	// AllowPrivateNetworks разрешает http и адреса локальных сетей. Только для проверки на локальном стенде
+	AllowPrivateNetworks bool `env:"WEBHOOKS_ALLOW_PRIVATE_NETWORKS" env-default:"false"`
+}
+
+type API struct {
+	Addr              string        `env:"API_ADDR" env-default:":8080"`
+	ReadHeaderTimeout time.Duration `env:"API_READ_HEADER_TIMEOUT" env-default:"5s"`
+	WriteTimeout      time.Duration `env:"API_WRITE_TIMEOUT" env-default:"30s"`
+	MaxTokensPerUser  int           `env:"API_MAX_TOKENS_PER_USER" env-default:"5"`
+	// PublicURL внешний адрес API, из него собираются ссылки на ленты календаря и оценок.
+	// CALENDAR_FEED_BASE_URL - прежнее название, читается, если API_PUBLIC_URL не задан
+	PublicURL string `env:"API_PUBLIC_URL,CALENDAR_FEED_BASE_URL" env-default:"http://localhost:8080"`
+}
+
+type Calendar struct {
+	// AutumnSemesterStart и SpringSemesterStart в формате ММ-ДД, от них отсчитываются недели семестра
+	AutumnSemesterStart string `env:"CALENDAR_AUTUMN_SEMESTER_START" env-default:"09-01"`
+	SpringSemesterStart string `env:"CALENDAR_SPRING_SEMESTER_START" env-default:"02-09"`
+}
+
+type Reminders struct {
+	CronDelay time.Duration `env:"REMINDERS_CRON_DELAY" env-default:"1h"`
+	// SendHour раньше этого часа по времени БАРС напоминания не отправляются
+	SendHour int `env:"REMINDERS_SEND_HOUR" env-default:"9"`
+	// SentRetention сколько хранятся отметки об отправленных напоминаниях
+	SentRetention time.Duration `env:"REMINDERS_SENT_RETENTION" env-default:"2160h"`
+}
+
+type Distributions struct {
+	CronDelay time.Duration `env:"DISTRIBUTIONS_CRON_DELAY" env-default:"6h"`
+	// MinContributors порог k-анонимности: распределение с меньшим числом участников не публикуется
+	// Тот же порог действует на смену состава: пока с последней публикации добавилось и выбыло меньше
+	// MinContributors участников, распределение не обновляется
+	MinContributors int `env:"DISTRIBUTIONS_MIN_CONTRIBUTORS" env-default:"10"`
+}
+
+type GradesFeed struct {
+	// MaxEntries сколько последних изменений оценок попадает в ленту
+	MaxEntries int `env:"GRADES_FEED_MAX_ENTRIES" env-default:"50"`
+}
+
+type GroupChats struct {
+	// AnnouncementPeriod в течение этого срока о мероприятии дисциплины в чат повторно не пишут
+	AnnouncementPeriod time.Duration `env:"GROUP_CHATS_ANNOUNCEMENT_PERIOD" env-default:"2160h"`
+}
+
+type Broadcasts struct {
+	CronDelay time.Duration `env:"BROADCASTS_CRON_DELAY" env-default:"10s"`
+	// MessagesPerSecond телеграм разрешает боту около 30 сообщений в секунду, часть остаётся уведомлениям об оценках
+	MessagesPerSecond int `env:"BROADCASTS_MESSAGES_PER_SECOND" env-default:"20"`
+	// BatchSize сколько получателей читается из БД за раз, статус рассылки перечитывается перед каждой отправкой
+	BatchSize int `env:"BROADCASTS_BATCH_SIZE" env-default:"100"`
 }
 
 type Postgres struct {
@@ -0,0 +1,13 @@
+package domain
+
+import "time"
+
+// AccessToken персональный токен доступа к API. Сам токен не хранится, только его хэш
+type AccessToken struct {
+	ID        int64
+	UserID    int64
+	TokenHash []byte
+	CreatedAt time.Time
+	// LastUsedAt nil, если токеном ещё не пользовались
+	LastUsedAt *time.Time
+}
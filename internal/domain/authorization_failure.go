@@ -0,0 +1,11 @@
+package domain
+
+import "time"
+
+type AuthorizationFailure struct {
+	UserID         int64
+	Count          int
+	LastError      string
+	FirstFailureAt time.Time
+	LastFailureAt  time.Time
+}
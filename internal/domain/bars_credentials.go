@@ -1,7 +1,17 @@
 package domain
 
+import "time"
+
 // TODO разделить на Password и RawPassword
 type BarsCredentials struct {
 	Username string
 	Password []byte
+	// SuspendedAt не nil, если отслеживание приостановлено из-за неудачных попыток авторизации
+	SuspendedAt *time.Time
+	// AuthorizationFailures неудачные попытки получить оценки подряд, см. AuthorizationFailure
+	AuthorizationFailures int
+}
+
+func (c *BarsCredentials) IsSuspended() bool {
+	return c.SuspendedAt != nil
 }
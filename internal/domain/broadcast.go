@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// BroadcastAudience кому адресована рассылка, удалённые пользователи её не получают
+type BroadcastAudience string
+
+const (
+	BroadcastAudienceAll           BroadcastAudience = "all"
+	BroadcastAudienceAuthorized    BroadcastAudience = "authorized"
+	BroadcastAudienceNotAuthorized BroadcastAudience = "not_authorized"
+	BroadcastAudienceSuspended     BroadcastAudience = "suspended"
+)
+
+var BroadcastAudiences = []BroadcastAudience{
+	BroadcastAudienceAll,
+	BroadcastAudienceAuthorized,
+	BroadcastAudienceNotAuthorized,
+	BroadcastAudienceSuspended,
+}
+
+type BroadcastStatus string
+
+const (
+	BroadcastStatusActive    BroadcastStatus = "active"
+	BroadcastStatusPaused    BroadcastStatus = "paused"
+	BroadcastStatusCancelled BroadcastStatus = "cancelled"
+	BroadcastStatusCompleted BroadcastStatus = "completed"
+)
+
+func (s BroadcastStatus) IsFinished() bool {
+	return s == BroadcastStatusCancelled || s == BroadcastStatusCompleted
+}
+
+type BroadcastRecipientStatus string
+
+const (
+	BroadcastRecipientStatusPending BroadcastRecipientStatus = "pending"
+	BroadcastRecipientStatusSent    BroadcastRecipientStatus = "sent"
+	BroadcastRecipientStatusFailed  BroadcastRecipientStatus = "failed"
+)
+
+// BroadcastFilter отбор получателей рассылки
+type BroadcastFilter struct {
+	Audience BroadcastAudience
+	// Language пустой - пользователи с любым языком
+	Language Language
+}
+
+// ParseBroadcastFilter разбирает фильтр вида "аудитория" или "аудитория:язык", например "authorized:en"
+func ParseBroadcastFilter(s string) (BroadcastFilter, bool) {
+	audience, language, _ := strings.Cut(strings.ToLower(s), ":")
+
+	filter := BroadcastFilter{
+		Audience: BroadcastAudience(audience),
+		Language: Language(language),
+	}
+
+	isValidAudience := false
+	for _, a := range BroadcastAudiences {
+		if a == filter.Audience {
+			isValidAudience = true
+			break
+		}
+	}
+	if !isValidAudience {
+		return BroadcastFilter{}, false
+	}
+
+	if filter.Language == "" {
+		return filter, true
+	}
+	for _, l := range Languages {
+		if l == filter.Language {
+			return filter, true
+		}
+	}
+
+	return BroadcastFilter{}, false
+}
+
+func (f BroadcastFilter) String() string {
+	if f.Language == "" {
+		return string(f.Audience)
+	}
+
+	return string(f.Audience) + ":" + string(f.Language)
+}
+
+// BroadcastProgress число получателей рассылки по статусам
+type BroadcastProgress struct {
+	Total   int
+	Pending int
+	Sent    int
+	Failed  int
+}
+
+// Broadcast рассылка администратора. Получатели отбираются при создании, поэтому пользователи,
+// пришедшие позже, рассылку не получат
+type Broadcast struct {
+	ID        int64
+	AdminID   int64
+	Text      string
+	Filter    BroadcastFilter
+	Status    BroadcastStatus
+	Progress  BroadcastProgress
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	// FinishedAt время отмены или завершения рассылки
+	FinishedAt *time.Time
+}
+
+type BroadcastRecipient struct {
+	BroadcastID int64
+	UserID      int64
+	Status      BroadcastRecipientStatus
+	// Error текст ошибки отправки для BroadcastRecipientStatusFailed
+	Error       string
+	ProcessedAt time.Time
+}
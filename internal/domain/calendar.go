@@ -0,0 +1,78 @@
+package domain
+
+import "time"
+
+// SemesterStartLayout формат даты начала семестра в настройках: ММ-ДД
+const SemesterStartLayout = "01-02"
+
+// PlannedControlEvent контрольное мероприятие с известным сроком. Срок длится от Start до End, End не включается
+type PlannedControlEvent struct {
+	Discipline   string
+	ControlEvent ControlEvent
+	Start        time.Time
+	End          time.Time
+}
+
+// HasExactDate в БАРС указана дата мероприятия, а не только неделя
+func (e *PlannedControlEvent) HasExactDate() bool {
+	return !e.ControlEvent.DeadlineDate.IsZero()
+}
+
+// PlannedControlEvents мероприятия таблицы успеваемости с датой или неделей. Если известна только неделя,
+// срок занимает её целиком. Итоговые строки и мероприятия без срока пропускаются
+func PlannedControlEvents(progressTable *ProgressTable, semesterStart time.Time) []PlannedControlEvent {
+	events := make([]PlannedControlEvent, 0)
+	for _, discipline := range progressTable.Disciplines {
+		for _, ce := range discipline.ControlEvents {
+			if ce.IsSummary() {
+				continue
+			}
+
+			var start, end time.Time
+			switch {
+			case !ce.DeadlineDate.IsZero():
+				start = ce.DeadlineDate
+				end = start.AddDate(0, 0, 1)
+			case ce.DeadlineWeek > 0:
+				start = SemesterWeekStart(semesterStart, ce.DeadlineWeek)
+				end = start.AddDate(0, 0, 7)
+			default:
+				continue
+			}
+
+			events = append(events, PlannedControlEvent{
+				Discipline:   discipline.Name,
+				ControlEvent: ce,
+				Start:        start,
+				End:          end,
+			})
+		}
+	}
+
+	return events
+}
+
+// SemesterStart дата начала семестра, в который попадает moment. Осенний семестр начинается
+// в autumnStart, весенний - в springStart, обе даты берутся в году moment
+func SemesterStart(moment time.Time, autumnStart, springStart time.Time) time.Time {
+	year := moment.Year()
+	autumn := time.Date(year, autumnStart.Month(), autumnStart.Day(), 0, 0, 0, 0, moment.Location())
+	spring := time.Date(year, springStart.Month(), springStart.Day(), 0, 0, 0, 0, moment.Location())
+
+	switch {
+	case !moment.Before(autumn):
+		return autumn
+	case !moment.Before(spring):
+		return spring
+	default:
+		return autumn.AddDate(-1, 0, 0)
+	}
+}
+
+// SemesterWeekStart понедельник недели семестра week. Первая неделя та, на которую приходится начало семестра
+func SemesterWeekStart(semesterStart time.Time, week int) time.Time {
+	daysSinceMonday := (int(semesterStart.Weekday()) + 6) % 7
+	firstMonday := semesterStart.AddDate(0, 0, -daysSinceMonday)
+
+	return firstMonday.AddDate(0, 0, 7*(week-1))
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// Chat групповой чат, куда бота добавили. Пользователи и чаты независимы: пользователь может состоять
+// в нескольких чатах, а в чат пишут только о тех, кто сам вызвал /join
+type Chat struct {
+	// ID идентификатор чата в телеграме, у групп он отрицательный
+	ID        int64
+	Title     string
+	Language  Language
+	CreatedAt time.Time
+}
+
+// ChatAnnouncement отметка о сообщении в чат: об одном мероприятии чат узнаёт один раз,
+// сколько бы участников ни получили за него оценку
+type ChatAnnouncement struct {
+	ChatID      int64
+	Key         string
+	AnnouncedAt time.Time
+}
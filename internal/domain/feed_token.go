@@ -0,0 +1,21 @@
+package domain
+
+import "time"
+
+// FeedKind лента, которую открывает токен
+type FeedKind string
+
+const (
+	FeedKindCalendar FeedKind = "calendar"
+	FeedKindGrades   FeedKind = "grades"
+)
+
+// FeedToken секретный токен из адреса ленты iCalendar или Atom. Поиск идёт по хэшу токена,
+// а зашифрованный токен нужен, чтобы показать адрес повторно
+type FeedToken struct {
+	UserID    int64
+	Kind      FeedKind
+	TokenHash []byte
+	Token     []byte
+	CreatedAt time.Time
+}
@@ -0,0 +1,146 @@
+package domain
+
+// DefaultExamWeight доля промежуточной аттестации в итоговой оценке, если БАРС не показывает вес
+const DefaultExamWeight = 0.5
+
+// FinalGradeTargets итоговые оценки, для которых считается прогноз
+var FinalGradeTargets = []int{5, 4, 3}
+
+type RequirementStatus int
+
+const (
+	RequirementStatusNeeded RequirementStatus = iota
+	// RequirementStatusAchieved оценка обеспечена при любых оставшихся оценках
+	RequirementStatusAchieved
+	// RequirementStatusUnreachable оценка недостижима даже при пятёрках за всё оставшееся
+	RequirementStatusUnreachable
+)
+
+type GradeRequirement struct {
+	Target int
+	Status RequirementStatus
+	// MinAverage минимальная средняя оценка за оставшиеся мероприятия, заполнена для RequirementStatusNeeded
+	MinAverage float64
+}
+
+type DisciplineForecast struct {
+	// CurrentScore взвешенный балл по уже оценённым мероприятиям
+	CurrentScore       float64
+	GradedEventsCount  int
+	ScoredEventsCount  int
+	HasCurrentScore    bool
+	HasRemainingEvents bool
+	Requirements       []GradeRequirement
+}
+
+const (
+	minGradeScore = 2
+	maxGradeScore = 5
+)
+
+// Forecast считает итоговую оценку как взвешенное среднее оценок за КМ и промежуточную аттестацию.
+// Веса КМ нормируются к доле текущего контроля, без весов КМ считаются равнозначными.
+// Итоговая оценка округляется математически, поэтому для оценки N достаточно N-0.5
+func (d *Discipline) Forecast() *DisciplineForecast {
+	controlEvents := make([]ControlEvent, 0, len(d.ControlEvents))
+	var attestation *ControlEvent
+	for i, ce := range d.ControlEvents {
+		if ce.IsIntermediateAttestation() {
+			attestation = &d.ControlEvents[i]
+			continue
+		}
+		// зачёт/незачёт и нераспознанные оценки в баллы не переводятся
+		if ce.IsSummary() || (ce.Grade.IsGraded() && !ce.Grade.HasScore() && ce.Grade.Kind != GradeKindAbsent) {
+			continue
+		}
+		controlEvents = append(controlEvents, ce)
+	}
+
+	currentControlWeight := 1.0
+	examWeight := 0.0
+	if attestation != nil {
+		examWeight = DefaultExamWeight
+		// БАРС показывает вес долей или процентами
+		weight := attestation.Weight
+		if weight > 1 {
+			weight /= 100
+		}
+		if weight > 0 && weight < 1 {
+			examWeight = weight
+		}
+		currentControlWeight = 1 - examWeight
+	}
+
+	totalControlEventsWeight := 0.0
+	for _, ce := range controlEvents {
+		totalControlEventsWeight += ce.Weight
+	}
+
+	forecast := &DisciplineForecast{ScoredEventsCount: len(controlEvents)}
+	var gradedSum, gradedWeight, remainingWeight float64
+	addEvent := func(grade Grade, weight float64) {
+		switch {
+		case grade.HasScore():
+			gradedSum += grade.Score * weight
+			gradedWeight += weight
+		case grade.Kind == GradeKindAbsent:
+			// неявка учитывается как ноль
+			gradedWeight += weight
+		default:
+			remainingWeight += weight
+		}
+	}
+
+	for _, ce := range controlEvents {
+		weight := currentControlWeight / float64(len(controlEvents))
+		if totalControlEventsWeight > 0 {
+			weight = currentControlWeight * ce.Weight / totalControlEventsWeight
+		}
+		if ce.Grade.IsGraded() {
+			forecast.GradedEventsCount++
+		}
+		addEvent(ce.Grade, weight)
+	}
+	if attestation != nil {
+		addEvent(attestation.Grade, examWeight)
+	}
+
+	if gradedWeight > 0 {
+		forecast.HasCurrentScore = true
+		forecast.CurrentScore = gradedSum / gradedWeight
+	}
+	forecast.HasRemainingEvents = remainingWeight > 0
+
+	totalWeight := gradedWeight + remainingWeight
+	if totalWeight == 0 {
+		return forecast
+	}
+
+	for _, target := range FinalGradeTargets {
+		threshold := float64(target) - 0.5
+		requirement := GradeRequirement{Target: target}
+
+		if remainingWeight == 0 {
+			requirement.Status = RequirementStatusUnreachable
+			if gradedSum/totalWeight >= threshold {
+				requirement.Status = RequirementStatusAchieved
+			}
+			forecast.Requirements = append(forecast.Requirements, requirement)
+			continue
+		}
+
+		minAverage := (threshold*totalWeight - gradedSum) / remainingWeight
+		switch {
+		case minAverage > maxGradeScore:
+			requirement.Status = RequirementStatusUnreachable
+		case minAverage <= minGradeScore:
+			requirement.Status = RequirementStatusAchieved
+		default:
+			requirement.Status = RequirementStatusNeeded
+			requirement.MinAverage = minAverage
+		}
+		forecast.Requirements = append(forecast.Requirements, requirement)
+	}
+
+	return forecast
+}
@@ -0,0 +1,159 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func controlEvent(name, grade string) ControlEvent {
+	return ControlEvent{Name: name, Grade: ParseGrade(grade)}
+}
+
+func TestDisciplineForecast(t *testing.T) {
+	weightedEvent := func(name, grade string, weight float64) ControlEvent {
+		ce := controlEvent(name, grade)
+		ce.Weight = weight
+		return ce
+	}
+
+	// КМ 5 и 3 с весами 0,2 и 0,3, экзамен с весом 0,4 впереди:
+	// веса КМ нормируются к 0,6, текущий балл (5*0,24 + 3*0,36) / 0,6 = 3,8
+	weightedWant := &DisciplineForecast{
+		CurrentScore:       3.8,
+		GradedEventsCount:  2,
+		ScoredEventsCount:  2,
+		HasCurrentScore:    true,
+		HasRemainingEvents: true,
+		Requirements: []GradeRequirement{
+			{Target: 5, Status: RequirementStatusUnreachable},
+			{Target: 4, Status: RequirementStatusNeeded, MinAverage: 3.05},
+			{Target: 3, Status: RequirementStatusAchieved},
+		},
+	}
+
+	tests := []struct {
+		name       string
+		discipline Discipline
+		want       *DisciplineForecast
+	}{
+		{
+			name: "unweighted",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "5"),
+					controlEvent("КМ-2", "4"),
+					controlEvent("КМ-3", ""),
+					// зачёт в баллы не переводится
+					controlEvent("КМ-4", "зачтено"),
+					controlEvent("Балл текущего контроля", "4,5"),
+				},
+			},
+			want: &DisciplineForecast{
+				CurrentScore:       4.5,
+				GradedEventsCount:  2,
+				ScoredEventsCount:  3,
+				HasCurrentScore:    true,
+				HasRemainingEvents: true,
+				Requirements: []GradeRequirement{
+					{Target: 5, Status: RequirementStatusNeeded, MinAverage: 4.5},
+					{Target: 4, Status: RequirementStatusAchieved},
+					{Target: 3, Status: RequirementStatusAchieved},
+				},
+			},
+		},
+		{
+			name: "weighted",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					weightedEvent("КМ-1", "5", 0.2),
+					weightedEvent("КМ-2", "3", 0.3),
+					weightedEvent("Промежуточная аттестация", "", 0.4),
+				},
+			},
+			want: weightedWant,
+		},
+		{
+			name: "percentage weights",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					weightedEvent("КМ-1", "5", 20),
+					weightedEvent("КМ-2", "3", 30),
+					weightedEvent("Промежуточная аттестация", "", 40),
+				},
+			},
+			want: weightedWant,
+		},
+		{
+			name: "attestation without weight",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "4"),
+					controlEvent("Промежуточная аттестация", ""),
+				},
+			},
+			// экзамен по умолчанию весит половину: для пятёрки нужно (4,5 - 2) / 0,5
+			want: &DisciplineForecast{
+				CurrentScore:       4,
+				GradedEventsCount:  1,
+				ScoredEventsCount:  1,
+				HasCurrentScore:    true,
+				HasRemainingEvents: true,
+				Requirements: []GradeRequirement{
+					{Target: 5, Status: RequirementStatusNeeded, MinAverage: 5},
+					{Target: 4, Status: RequirementStatusNeeded, MinAverage: 3},
+					{Target: 3, Status: RequirementStatusAchieved},
+				},
+			},
+		},
+		{
+			name: "absence counts as zero",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "5"),
+					controlEvent("КМ-2", "н/я"),
+				},
+			},
+			want: &DisciplineForecast{
+				CurrentScore:      2.5,
+				GradedEventsCount: 2,
+				ScoredEventsCount: 2,
+				HasCurrentScore:   true,
+				Requirements: []GradeRequirement{
+					{Target: 5, Status: RequirementStatusUnreachable},
+					{Target: 4, Status: RequirementStatusUnreachable},
+					{Target: 3, Status: RequirementStatusAchieved},
+				},
+			},
+		},
+		{
+			name:       "no events",
+			discipline: Discipline{},
+			want:       &DisciplineForecast{},
+		},
+	}
+
+	for _, tt := range tests {
+		got := tt.discipline.Forecast()
+		if math.Abs(got.CurrentScore-tt.want.CurrentScore) > 1e-9 ||
+			got.GradedEventsCount != tt.want.GradedEventsCount ||
+			got.ScoredEventsCount != tt.want.ScoredEventsCount ||
+			got.HasCurrentScore != tt.want.HasCurrentScore ||
+			got.HasRemainingEvents != tt.want.HasRemainingEvents {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+			continue
+		}
+
+		if len(got.Requirements) != len(tt.want.Requirements) {
+			t.Errorf("%s: got %d requirements, want %d", tt.name, len(got.Requirements), len(tt.want.Requirements))
+			continue
+		}
+		for i, requirement := range got.Requirements {
+			want := tt.want.Requirements[i]
+			if requirement.Target != want.Target ||
+				requirement.Status != want.Status ||
+				math.Abs(requirement.MinAverage-want.MinAverage) > 1e-9 {
+				t.Errorf("%s: requirement %d: got %+v, want %+v", tt.name, i, requirement, want)
+			}
+		}
+	}
+}
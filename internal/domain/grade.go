@@ -0,0 +1,100 @@
+package domain
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type GradeKind int
+
+const (
+	// GradeKindNotGraded оценка ещё не выставлена
+	GradeKindNotGraded GradeKind = iota
+	// GradeKindAbsent студент не явился на контрольное мероприятие
+	GradeKindAbsent
+	GradeKindScore
+	GradeKindLetter
+	GradeKindPass
+	GradeKindFail
+	// GradeKindUnknown текст оценки не удалось разобрать, доступен только Raw
+	GradeKindUnknown
+)
+
+// notGradedRaw так парсер записывал пустую оценку до появления типизированной модели
+const notGradedRaw = "отсутствует"
+
+var (
+	gradeScoreRegexp  = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)`)
+	gradeLetterRegexp = regexp.MustCompile(`^([A-FА-Е])([+-]?)$`)
+)
+
+var gradeWordScores = map[string]float64{
+	"отлично":             5,
+	"хорошо":              4,
+	"удовлетворительно":   3,
+	"неудовлетворительно": 2,
+}
+
+// Grade оценка за контрольное мероприятие. Raw хранит текст из БАРС для отображения
+type Grade struct {
+	Raw    string
+	Kind   GradeKind
+	Score  float64
+	Letter string
+}
+
+func ParseGrade(raw string) Grade {
+	raw = strings.TrimSpace(raw)
+	if raw == "" || raw == notGradedRaw {
+		return Grade{Kind: GradeKindNotGraded}
+	}
+
+	grade := Grade{Raw: raw, Kind: GradeKindUnknown}
+	lower := strings.ToLower(raw)
+
+	switch {
+	case lower == "н/я" || strings.HasPrefix(lower, "неявка") || strings.HasPrefix(lower, "не явился"):
+		grade.Kind = GradeKindAbsent
+	case strings.HasPrefix(lower, "не зач") || strings.HasPrefix(lower, "незач"):
+		grade.Kind = GradeKindFail
+	case strings.HasPrefix(lower, "зач"):
+		grade.Kind = GradeKindPass
+	case gradeScoreRegexp.MatchString(lower):
+		// "4 (хорошо)", "4,5"
+		score, err := strconv.ParseFloat(
+			strings.Replace(gradeScoreRegexp.FindStringSubmatch(lower)[1], ",", ".", 1),
+			64,
+		)
+		if err == nil {
+			grade.Kind = GradeKindScore
+			grade.Score = score
+		}
+	case gradeLetterRegexp.MatchString(raw):
+		grade.Kind = GradeKindLetter
+		grade.Letter = raw
+	default:
+		if score, ok := gradeWordScores[lower]; ok {
+			grade.Kind = GradeKindScore
+			grade.Score = score
+		}
+	}
+
+	return grade
+}
+
+func (g Grade) IsGraded() bool {
+	return g.Kind != GradeKindNotGraded
+}
+
+func (g Grade) HasScore() bool {
+	return g.Kind == GradeKindScore
+}
+
+func (g Grade) String() string {
+	if g.Kind == GradeKindNotGraded {
+		return notGradedRaw
+	}
+
+	return g.Raw
+}
@@ -1,6 +1,6 @@
 package domain
 
-import "fmt"
+import "time"
 
 type GradeChange struct {
 	ID           int64
@@ -9,10 +9,14 @@ type GradeChange struct {
 	ControlEvent string
 	OldGrade     string
 	NewGrade     string
+	CreatedAt    time.Time
+	// Semester семестр, к которому относится изменение. Пустой у изменений, сохранённых раньше
+	Semester string
+	// RecordBook изменение найдено в зачётной книжке, тогда ControlEvent - форма контроля
+	RecordBook bool
 }
 
-// TODO это явно не логика для домеина, нужно переделать
-func (c *GradeChange) String() string {
-	return fmt.Sprintf("*Получено изменение:*\n\n*Название дисциплины:*\n%s\n\n*Контрольное мероприятие:*\n%s\n\n*Старая оценка:*\n%s\n\n"+
-		"*Новая оценка:*\n%s", c.Discipline, c.ControlEvent, c.OldGrade, c.NewGrade)
+// IsPublication оценку выставили впервые, а не исправили уже выставленную
+func (c *GradeChange) IsPublication() bool {
+	return !ParseGrade(c.OldGrade).IsGraded() && ParseGrade(c.NewGrade).IsGraded()
 }
@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// GradeDistributionStep шаг, до которого округляются квартили: по точным квартилям небольшой выборки
+// восстанавливаются баллы отдельных участников
+const GradeDistributionStep = 0.5
+
+// GradeDistribution обезличенное распределение баллов за контрольное мероприятие среди пользователей,
+// согласившихся делиться оценками. Хранятся только округлённые квартили и участники, без самих оценок
+type GradeDistribution struct {
+	Semester     string
+	Discipline   string
+	ControlEvent string
+	Contributors int
+	Q1           float64
+	Median       float64
+	Q3           float64
+	UpdatedAt    time.Time
+	// ContributorIDs не показываются, по ним решается, достаточно ли сменился состав для новой публикации
+	ContributorIDs []int64
+}
+
+// NewGradeDistribution scores баллы участников по идентификаторам пользователей
+func NewGradeDistribution(
+	semester string,
+	discipline string,
+	controlEvent string,
+	scores map[int64]float64,
+	updatedAt time.Time,
+) *GradeDistribution {
+	contributorIDs := make([]int64, 0, len(scores))
+	sorted := make([]float64, 0, len(scores))
+	for userID, score := range scores {
+		contributorIDs = append(contributorIDs, userID)
+		sorted = append(sorted, score)
+	}
+	sort.Slice(contributorIDs, func(i, j int) bool {
+		return contributorIDs[i] < contributorIDs[j]
+	})
+	sort.Float64s(sorted)
+
+	return &GradeDistribution{
+		Semester:       semester,
+		Discipline:     discipline,
+		ControlEvent:   controlEvent,
+		Contributors:   len(sorted),
+		Q1:             roundToStep(quantile(sorted, 0.25)),
+		Median:         roundToStep(quantile(sorted, 0.5)),
+		Q3:             roundToStep(quantile(sorted, 0.75)),
+		UpdatedAt:      updatedAt,
+		ContributorIDs: contributorIDs,
+	}
+}
+
+// ContributorsChanged сколько участников добавилось и выбыло по сравнению с распределением previous
+func (d *GradeDistribution) ContributorsChanged(previous *GradeDistribution) int {
+	previousIDs := make(map[int64]struct{}, len(previous.ContributorIDs))
+	for _, userID := range previous.ContributorIDs {
+		previousIDs[userID] = struct{}{}
+	}
+
+	changed := 0
+	for _, userID := range d.ContributorIDs {
+		if _, ok := previousIDs[userID]; ok {
+			delete(previousIDs, userID)
+			continue
+		}
+		changed++
+	}
+
+	return changed + len(previousIDs)
+}
+
+// KeepsContributors все участники распределения previous остаются в текущем
+func (d *GradeDistribution) KeepsContributors(previous *GradeDistribution) bool {
+	contributorIDs := make(map[int64]struct{}, len(d.ContributorIDs))
+	for _, userID := range d.ContributorIDs {
+		contributorIDs[userID] = struct{}{}
+	}
+
+	for _, userID := range previous.ContributorIDs {
+		if _, ok := contributorIDs[userID]; !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// quantile линейная интерполяция между соседними значениями отсортированной выборки
+func quantile(sorted []float64, q float64) float64 {
+	if len(sorted) == 0 {
+		return 0
+	}
+
+	position := q * float64(len(sorted)-1)
+	lower := int(position)
+	if lower+1 >= len(sorted) {
+		return sorted[lower]
+	}
+
+	return sorted[lower] + (position-float64(lower))*(sorted[lower+1]-sorted[lower])
+}
+
+func roundToStep(value float64) float64 {
+	return math.Round(value/GradeDistributionStep) * GradeDistributionStep
+}
@@ -0,0 +1,99 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuantile(t *testing.T) {
+	tests := []struct {
+		sorted []float64
+		q      float64
+		want   float64
+	}{
+		{sorted: nil, q: 0.5, want: 0},
+		{sorted: []float64{4}, q: 0.25, want: 4},
+		{sorted: []float64{1, 2, 3, 4, 5}, q: 0.5, want: 3},
+		{sorted: []float64{1, 2, 3, 4, 5}, q: 0.25, want: 2},
+		{sorted: []float64{1, 2, 3, 4}, q: 0.5, want: 2.5},
+		{sorted: []float64{1, 2, 3, 4}, q: 0.25, want: 1.75},
+		{sorted: []float64{1, 2, 3, 4}, q: 0.75, want: 3.25},
+		{sorted: []float64{1, 2, 3, 4}, q: 1, want: 4},
+	}
+
+	for _, tt := range tests {
+		if got := quantile(tt.sorted, tt.q); got != tt.want {
+			t.Errorf("quantile(%v, %v) = %v, want %v", tt.sorted, tt.q, got, tt.want)
+		}
+	}
+}
+
+func TestNewGradeDistributionRoundsQuartiles(t *testing.T) {
+	scores := map[int64]float64{1: 3.2, 2: 3.6, 3: 4.1, 4: 4.3, 5: 4.9}
+	updatedAt := time.Date(2024, time.October, 16, 12, 0, 0, 0, time.UTC)
+
+	distribution := NewGradeDistribution("Осень 2024", "Физика", "КМ-1", scores, updatedAt)
+
+	// точные квартили 3.6, 4.1 и 4.3
+	want := &GradeDistribution{
+		Semester:       "Осень 2024",
+		Discipline:     "Физика",
+		ControlEvent:   "КМ-1",
+		Contributors:   5,
+		Q1:             3.5,
+		Median:         4,
+		Q3:             4.5,
+		UpdatedAt:      updatedAt,
+		ContributorIDs: []int64{1, 2, 3, 4, 5},
+	}
+	if !reflect.DeepEqual(distribution, want) {
+		t.Errorf("NewGradeDistribution() = %+v, want %+v", distribution, want)
+	}
+}
+
+func TestGradeDistributionContributorsChanged(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []int64
+		current  []int64
+		want     int
+	}{
+		{name: "same", previous: []int64{1, 2, 3}, current: []int64{1, 2, 3}, want: 0},
+		{name: "joined", previous: []int64{1, 2, 3}, current: []int64{1, 2, 3, 4}, want: 1},
+		{name: "left", previous: []int64{1, 2, 3}, current: []int64{1, 3}, want: 1},
+		// выбывший и добавившийся считаются оба, хотя число участников не изменилось
+		{name: "replaced", previous: []int64{1, 2, 3}, current: []int64{1, 2, 4}, want: 2},
+		{name: "disjoint", previous: []int64{1, 2}, current: []int64{3, 4, 5}, want: 5},
+	}
+
+	for _, tt := range tests {
+		current := &GradeDistribution{ContributorIDs: tt.current}
+		previous := &GradeDistribution{ContributorIDs: tt.previous}
+		if got := current.ContributorsChanged(previous); got != tt.want {
+			t.Errorf("%s: ContributorsChanged() = %d, want %d", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestGradeDistributionKeepsContributors(t *testing.T) {
+	tests := []struct {
+		name     string
+		previous []int64
+		current  []int64
+		want     bool
+	}{
+		{name: "same", previous: []int64{1, 2, 3}, current: []int64{1, 2, 3}, want: true},
+		{name: "joined", previous: []int64{1, 2, 3}, current: []int64{1, 2, 3, 4}, want: true},
+		{name: "left", previous: []int64{1, 2, 3}, current: []int64{1, 3}, want: false},
+		{name: "replaced", previous: []int64{1, 2, 3}, current: []int64{1, 2, 4}, want: false},
+	}
+
+	for _, tt := range tests {
+		current := &GradeDistribution{ContributorIDs: tt.current}
+		previous := &GradeDistribution{ContributorIDs: tt.previous}
+		if got := current.KeepsContributors(previous); got != tt.want {
+			t.Errorf("%s: KeepsContributors() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "testing"
+
+func TestParseGrade(t *testing.T) {
+	tests := []struct {
+		raw  string
+		want Grade
+	}{
+		{raw: "5", want: Grade{Raw: "5", Kind: GradeKindScore, Score: 5}},
+		{raw: "4 (хорошо)", want: Grade{Raw: "4 (хорошо)", Kind: GradeKindScore, Score: 4}},
+		{raw: "4,5", want: Grade{Raw: "4,5", Kind: GradeKindScore, Score: 4.5}},
+		{raw: "отлично", want: Grade{Raw: "отлично", Kind: GradeKindScore, Score: 5}},
+		{raw: "A-", want: Grade{Raw: "A-", Kind: GradeKindLetter, Letter: "A-"}},
+		{raw: "зачтено", want: Grade{Raw: "зачтено", Kind: GradeKindPass}},
+		{raw: "не зачтено", want: Grade{Raw: "не зачтено", Kind: GradeKindFail}},
+		{raw: "н/я", want: Grade{Raw: "н/я", Kind: GradeKindAbsent}},
+		// так парсер записывал пустую оценку до появления типизированной модели
+		{raw: "отсутствует", want: Grade{Kind: GradeKindNotGraded}},
+		{raw: "", want: Grade{Kind: GradeKindNotGraded}},
+		{raw: " 3 ", want: Grade{Raw: "3", Kind: GradeKindScore, Score: 3}},
+		{raw: "допуск к экзамену", want: Grade{Raw: "допуск к экзамену", Kind: GradeKindUnknown}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseGrade(tt.raw); got != tt.want {
+			t.Errorf("ParseGrade(%q) = %+v, want %+v", tt.raw, got, tt.want)
+		}
+	}
+}
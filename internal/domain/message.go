@@ -0,0 +1,24 @@
+package domain
+
+// MessageFormat разметка текста сообщения, по ней канал доставки выбирает способ отправки
+type MessageFormat string
+
+const (
+	MessageFormatPlain      MessageFormat = "plain"
+	MessageFormatMarkdownV2 MessageFormat = "markdownv2"
+	MessageFormatHTML       MessageFormat = "html"
+)
+
+var MessageFormats = []MessageFormat{MessageFormatPlain, MessageFormatMarkdownV2, MessageFormatHTML}
+
+type Message struct {
+	Format MessageFormat
+	Text   string
+}
+
+func NewPlainMessage(text string) *Message {
+	return &Message{
+		Format: MessageFormatPlain,
+		Text:   text,
+	}
+}
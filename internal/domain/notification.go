@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// NotificationChannel канал доставки уведомлений об изменениях оценок
+type NotificationChannel string
+
+const (
+	NotificationChannelTelegram NotificationChannel = "telegram"
+	NotificationChannelEmail    NotificationChannel = "email"
+	// NotificationChannelWebhook не выбирается в настройках: изменения уходят на все вебхуки пользователя
+	NotificationChannelWebhook NotificationChannel = "webhook"
+	// NotificationChannelGroupChat не выбирается в настройках: в групповые чаты, куда пользователь вступил
+	// через /join, уходит только обезличенное сообщение о том, что оценка выставлена
+	NotificationChannelGroupChat NotificationChannel = "group_chat"
+)
+
+var (
+	NotificationChannels        = []NotificationChannel{NotificationChannelTelegram, NotificationChannelEmail}
+	DefaultNotificationChannels = []NotificationChannel{NotificationChannelTelegram}
+)
+
+func ParseNotificationChannel(channel string) (NotificationChannel, bool) {
+	for _, c := range NotificationChannels {
+		if string(c) == strings.ToLower(strings.TrimSpace(channel)) {
+			return c, true
+		}
+	}
+
+	return "", false
+}
+
+// NotificationDelivery состояние доставки изменения оценки по одному каналу
+type NotificationDelivery struct {
+	GradeChangeID int64
+	Channel       NotificationChannel
+	// Endpoint адресат внутри канала, например id вебхука. Для Telegram и почты пустой
+	Endpoint string
+	// DeliveredAt nil, пока изменение не доставлено
+	DeliveredAt   *time.Time
+	FailuresCount int
+	LastError     string
+	UpdatedAt     time.Time
+	// NextAttemptAt раньше этого времени адресату не пишут, нулевое - попытка сразу
+	NextAttemptAt time.Time
+}
+
+func (d *NotificationDelivery) IsDelivered() bool {
+	return d.DeliveredAt != nil
+}
+
+// RetryDelay после каждой неудачи пауза перед следующей попыткой удваивается, но не превышает maxBackoff
+func (d *NotificationDelivery) RetryDelay(backoff, maxBackoff time.Duration) time.Duration {
+	if d.FailuresCount == 0 {
+		return 0
+	}
+
+	delay := backoff
+	for i := 1; i < d.FailuresCount && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay
+}
+
+// EmailVerificationCodesPeriod период, за который ограничивается число отправленных кодов и попыток ввода
+const EmailVerificationCodesPeriod = 24 * time.Hour
+
+// EmailVerification ожидающее подтверждения изменение адреса. Хранится только хэш кода
+type EmailVerification struct {
+	UserID   int64
+	Email    string
+	CodeHash []byte
+	// Attempts не сбрасываются при запросе нового кода до конца периода EmailVerificationCodesPeriod
+	Attempts  int
+	ExpiresAt time.Time
+	// CreatedAt время отправки последнего кода
+	CreatedAt time.Time
+	// CodesSent сколько кодов отправлено с CodesSentSince
+	CodesSent      int
+	CodesSentSince time.Time
+}
+
+func (v *EmailVerification) IsExpired(now time.Time) bool {
+	return !now.Before(v.ExpiresAt)
+}
+
+// IsCodesPeriodOver счётчики кодов и попыток можно начинать заново
+func (v *EmailVerification) IsCodesPeriodOver(now time.Time) bool {
+	return !now.Before(v.CodesSentSince.Add(EmailVerificationCodesPeriod))
+}
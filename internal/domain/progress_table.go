@@ -1,17 +1,19 @@
 package domain
 
-import "fmt"
+import (
+	"strings"
+	"time"
+)
 
 type ProgressTable struct {
+	// Semester пустой у таблиц, сохранённых до поддержки семестров
+	Semester    Semester
 	Disciplines []Discipline
 }
 
-func (pt *ProgressTable) String() string {
-	str := ""
-	for _, discipline := range pt.Disciplines {
-		str += fmt.Sprintf("%s\n", discipline.String())
-	}
-	return str
+type Semester struct {
+	ID   string
+	Name string
 }
 
 type Discipline struct {
@@ -19,20 +21,30 @@ type Discipline struct {
 	ControlEvents []ControlEvent
 }
 
-// TODO это явно не логика для домеина, нужно переделать
-func (d *Discipline) String() string {
-	str := fmt.Sprintf("*Название дисциплины:*\n%s\n\n", d.Name)
-	for _, ce := range d.ControlEvents {
-		str += fmt.Sprintf("%s\n", ce.String())
-	}
-	return str
-}
-
 type ControlEvent struct {
 	Name  string
-	Grade string
+	Grade Grade
+	// Weight вес в баллах текущего контроля, 0 если БАРС его не показывает
+	Weight float64
+	// DeadlineWeek неделя семестра, на которую запланировано мероприятие, 0 если неизвестна
+	DeadlineWeek int
+	// DeadlineDate запланированная дата, если БАРС её показывает. Нулевая, если неизвестна
+	DeadlineDate time.Time
+}
+
+func (ce *ControlEvent) IsCurrentControlScore() bool {
+	return strings.HasPrefix(ce.Name, "Балл текущего контроля")
+}
+
+func (ce *ControlEvent) IsFinalGrade() bool {
+	return strings.HasPrefix(ce.Name, "Итоговая оценка:")
+}
+
+func (ce *ControlEvent) IsIntermediateAttestation() bool {
+	return strings.HasPrefix(ce.Name, "Промежуточная аттестация")
 }
 
-func (ce *ControlEvent) String() string {
-	return fmt.Sprintf("%s\n*Оценка:* %s\n", ce.Name, ce.Grade)
+// IsSummary итоговые строки дисциплины, которые не являются контрольными мероприятиями
+func (ce *ControlEvent) IsSummary() bool {
+	return ce.IsCurrentControlScore() || ce.IsFinalGrade() || ce.IsIntermediateAttestation()
 }
@@ -0,0 +1,41 @@
+package domain
+
+// RecordBook зачётная книжка, итоговые оценки по всем семестрам
+type RecordBook struct {
+	Entries []RecordBookEntry
+}
+
+type RecordBookEntry struct {
+	Discipline  string
+	Semester    string
+	ControlForm string
+	Mark        string
+	Date        string
+	Teacher     string
+}
+
+// Semesters возвращает семестры в порядке их появления в зачётной книжке
+func (rb *RecordBook) Semesters() []string {
+	semesters := make([]string, 0)
+	seen := make(map[string]struct{})
+	for _, entry := range rb.Entries {
+		if _, ok := seen[entry.Semester]; ok {
+			continue
+		}
+		seen[entry.Semester] = struct{}{}
+		semesters = append(semesters, entry.Semester)
+	}
+
+	return semesters
+}
+
+func (rb *RecordBook) SemesterEntries(semester string) []RecordBookEntry {
+	entries := make([]RecordBookEntry, 0)
+	for _, entry := range rb.Entries {
+		if entry.Semester == semester {
+			entries = append(entries, entry)
+		}
+	}
+
+	return entries
+}
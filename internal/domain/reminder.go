@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// ReminderKind вид напоминания о контрольных мероприятиях
+type ReminderKind string
+
+const (
+	// ReminderKindWeeklySummary сводка о мероприятиях текущей недели, ключ - ISO-неделя вида 2024-W42
+	ReminderKindWeeklySummary ReminderKind = "weekly_summary"
+	// ReminderKindControlEvent напоминание за UserSettings.ReminderDaysBefore дней до мероприятия
+	ReminderKindControlEvent ReminderKind = "control_event"
+)
+
+const (
+	DefaultReminderDaysBefore = 2
+	MaxReminderDaysBefore     = 14
+)
+
+// SentReminder отметка об отправленном напоминании, по Key одно и то же напоминание не отправляется дважды
+type SentReminder struct {
+	UserID int64
+	Kind   ReminderKind
+	Key    string
+	SentAt time.Time
+}
+
+// ReminderRecipient пользователь с таблицей успеваемости и настройками напоминаний
+type ReminderRecipient struct {
+	ProgressTable *ProgressTable
+	Settings      *UserSettings
+	// WeeklySummarySent сводка за текущую неделю уже отправлена
+	WeeklySummarySent bool
+}
+
+// WeeklySummaryKey ключ сводки за ISO-неделю, в которую входит t
+func WeeklySummaryKey(t time.Time) string {
+	year, week := t.ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
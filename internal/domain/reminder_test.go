@@ -0,0 +1,24 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeeklySummaryKey(t *testing.T) {
+	tests := []struct {
+		day  time.Time
+		want string
+	}{
+		{day: time.Date(2024, time.October, 14, 0, 0, 0, 0, time.UTC), want: "2024-W42"},
+		{day: time.Date(2024, time.October, 20, 0, 0, 0, 0, time.UTC), want: "2024-W42"},
+		// 30 декабря 2024 года относится к первой неделе 2025 года
+		{day: time.Date(2024, time.December, 30, 0, 0, 0, 0, time.UTC), want: "2025-W01"},
+	}
+
+	for _, tt := range tests {
+		if got := WeeklySummaryKey(tt.day); got != tt.want {
+			t.Errorf("WeeklySummaryKey(%s) = %q, want %q", tt.day.Format(time.DateOnly), got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package domain
+
+import "time"
+
+type Schedule struct {
+	Lessons []Lesson
+	// UpdatedAt время получения расписания из БАРС
+	UpdatedAt time.Time
+}
+
+type Lesson struct {
+	// Day дата занятия, время суток не учитывается
+	Day        time.Time
+	TimeSlot   string
+	Discipline string
+	Room       string
+	Teacher    string
+	LessonType string
+}
+
+// LessonsBetween возвращает занятия с from включительно по to не включительно
+func (s *Schedule) LessonsBetween(from, to time.Time) []Lesson {
+	lessons := make([]Lesson, 0)
+	for _, lesson := range s.Lessons {
+		if !lesson.Day.Before(from) && lesson.Day.Before(to) {
+			lessons = append(lessons, lesson)
+		}
+	}
+
+	return lessons
+}
+
+// SchedulePeriod период, за который показывается расписание
+type SchedulePeriod string
+
+const (
+	SchedulePeriodToday    SchedulePeriod = "today"
+	SchedulePeriodTomorrow SchedulePeriod = "tomorrow"
+	SchedulePeriodWeek     SchedulePeriod = "week"
+)
@@ -0,0 +1,85 @@
+package domain
+
+type SemesterStatistics struct {
+	Semester    Semester
+	Disciplines []DisciplineStatistics
+	// Average среднее по дисциплинам, у которых есть хотя бы одна оценка в баллах
+	Average      float64
+	HasAverage   bool
+	GradedCount  int
+	PendingCount int
+}
+
+type DisciplineStatistics struct {
+	Name         string
+	Average      float64
+	HasAverage   bool
+	GradedCount  int
+	PendingCount int
+	// IsAtRisk есть двойка или неявка хотя бы по одному мероприятию
+	IsAtRisk bool
+}
+
+func (pt *ProgressTable) Statistics() *SemesterStatistics {
+	statistics := &SemesterStatistics{
+		Semester:    pt.Semester,
+		Disciplines: make([]DisciplineStatistics, 0, len(pt.Disciplines)),
+	}
+
+	var averagesSum float64
+	averagesCount := 0
+	for _, discipline := range pt.Disciplines {
+		disciplineStatistics := discipline.Statistics()
+		statistics.Disciplines = append(statistics.Disciplines, disciplineStatistics)
+		statistics.GradedCount += disciplineStatistics.GradedCount
+		statistics.PendingCount += disciplineStatistics.PendingCount
+
+		if disciplineStatistics.HasAverage {
+			averagesSum += disciplineStatistics.Average
+			averagesCount++
+		}
+	}
+
+	if averagesCount != 0 {
+		statistics.HasAverage = true
+		statistics.Average = averagesSum / float64(averagesCount)
+	}
+
+	return statistics
+}
+
+func (d *Discipline) Statistics() DisciplineStatistics {
+	statistics := DisciplineStatistics{Name: d.Name}
+
+	var scoresSum float64
+	scoresCount := 0
+	for _, ce := range d.ControlEvents {
+		if ce.IsCurrentControlScore() || ce.IsFinalGrade() {
+			continue
+		}
+
+		if !ce.Grade.IsGraded() {
+			statistics.PendingCount++
+			continue
+		}
+		statistics.GradedCount++
+
+		switch {
+		case ce.Grade.Kind == GradeKindAbsent || ce.Grade.Kind == GradeKindFail:
+			statistics.IsAtRisk = true
+		case ce.Grade.HasScore():
+			scoresSum += ce.Grade.Score
+			scoresCount++
+			if ce.Grade.Score < 3 {
+				statistics.IsAtRisk = true
+			}
+		}
+	}
+
+	if scoresCount != 0 {
+		statistics.HasAverage = true
+		statistics.Average = scoresSum / float64(scoresCount)
+	}
+
+	return statistics
+}
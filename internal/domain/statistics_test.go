@@ -0,0 +1,110 @@
+package domain
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDisciplineStatistics(t *testing.T) {
+	tests := []struct {
+		name       string
+		discipline Discipline
+		want       DisciplineStatistics
+	}{
+		{
+			name: "scores and pending",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "5 (отлично)"),
+					controlEvent("КМ-2", "4"),
+					controlEvent("КМ-3", ""),
+					// итоговые строки не считаются мероприятиями
+					controlEvent("Балл текущего контроля", "4,5"),
+					controlEvent("Итоговая оценка: ", "отлично"),
+				},
+			},
+			want: DisciplineStatistics{Average: 4.5, HasAverage: true, GradedCount: 2, PendingCount: 1},
+		},
+		{
+			name: "two is at risk",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "2"),
+					controlEvent("КМ-2", "неудовлетворительно"),
+				},
+			},
+			want: DisciplineStatistics{Average: 2, HasAverage: true, GradedCount: 2, IsAtRisk: true},
+		},
+		{
+			name: "absence without scores",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "н/я"),
+					controlEvent("КМ-2", "зачтено"),
+				},
+			},
+			want: DisciplineStatistics{GradedCount: 2, IsAtRisk: true},
+		},
+		{
+			name: "nothing graded",
+			discipline: Discipline{
+				ControlEvents: []ControlEvent{
+					controlEvent("КМ-1", "отсутствует"),
+				},
+			},
+			want: DisciplineStatistics{PendingCount: 1},
+		},
+	}
+
+	for _, tt := range tests {
+		got := tt.discipline.Statistics()
+		if got.HasAverage != tt.want.HasAverage ||
+			math.Abs(got.Average-tt.want.Average) > 1e-9 ||
+			got.GradedCount != tt.want.GradedCount ||
+			got.PendingCount != tt.want.PendingCount ||
+			got.IsAtRisk != tt.want.IsAtRisk {
+			t.Errorf("%s: got %+v, want %+v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestProgressTableStatistics(t *testing.T) {
+	progressTable := &ProgressTable{
+		Semester: Semester{ID: "28", Name: "2023-2024, весенний семестр"},
+		Disciplines: []Discipline{
+			{
+				Name:          "Математический анализ",
+				ControlEvents: []ControlEvent{controlEvent("КМ-1", "5"), controlEvent("КМ-2", "4")},
+			},
+			{
+				Name:          "Физика",
+				ControlEvents: []ControlEvent{controlEvent("КМ-1", "3"), controlEvent("КМ-2", "")},
+			},
+			// дисциплина без оценок в баллах не влияет на средний балл семестра
+			{
+				Name:          "Физическая культура",
+				ControlEvents: []ControlEvent{controlEvent("КМ-1", "зачтено")},
+			},
+		},
+	}
+
+	statistics := progressTable.Statistics()
+	if statistics.Semester != progressTable.Semester {
+		t.Errorf("semester %+v, want %+v", statistics.Semester, progressTable.Semester)
+	}
+	if len(statistics.Disciplines) != len(progressTable.Disciplines) {
+		t.Fatalf("got %d disciplines, want %d", len(statistics.Disciplines), len(progressTable.Disciplines))
+	}
+	// (4.5 + 3) / 2
+	if !statistics.HasAverage || math.Abs(statistics.Average-3.75) > 1e-9 {
+		t.Errorf("average %v (has %v), want 3.75", statistics.Average, statistics.HasAverage)
+	}
+	if statistics.GradedCount != 4 || statistics.PendingCount != 1 {
+		t.Errorf("graded %d, pending %d; want 4 and 1", statistics.GradedCount, statistics.PendingCount)
+	}
+
+	empty := (&ProgressTable{}).Statistics()
+	if empty.HasAverage || empty.Average != 0 {
+		t.Errorf("empty table: average %v (has %v), want none", empty.Average, empty.HasAverage)
+	}
+}
@@ -0,0 +1,73 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+type TrendPoint struct {
+	At    time.Time
+	Score float64
+}
+
+// DisciplineTrend взвешенный балл дисциплины (см. Forecast) после каждого изменения оценки
+type DisciplineTrend struct {
+	Discipline string
+	Points     []TrendPoint
+}
+
+// Trends восстанавливает динамику баллов по истории изменений: начиная с текущей таблицы,
+// изменения откатываются от последнего к первому. Изменения, не относящиеся к таблице, пропускаются
+func (pt *ProgressTable) Trends(history []*GradeChange) []DisciplineTrend {
+	trends := make([]DisciplineTrend, 0, len(pt.Disciplines))
+	for _, discipline := range pt.Disciplines {
+		trends = append(trends, discipline.Trend(pt.Semester.Name, history))
+	}
+
+	return trends
+}
+
+// Trend semester название семестра таблицы: одноимённые дисциплины других семестров не учитываются.
+// Изменения без семестра, сохранённые до его появления, относятся к любому
+func (d *Discipline) Trend(semester string, history []*GradeChange) DisciplineTrend {
+	trend := DisciplineTrend{Discipline: d.Name}
+
+	controlEventIndexes := make(map[string]int, len(d.ControlEvents))
+	for i, ce := range d.ControlEvents {
+		controlEventIndexes[ce.Name] = i
+	}
+
+	changes := make([]*GradeChange, 0)
+	for _, change := range history {
+		if change.Discipline != d.Name || change.RecordBook {
+			continue
+		}
+		if change.Semester != "" && change.Semester != semester {
+			continue
+		}
+		if _, ok := controlEventIndexes[change.ControlEvent]; ok {
+			changes = append(changes, change)
+		}
+	}
+	sort.SliceStable(changes, func(i, j int) bool {
+		return changes[i].CreatedAt.Before(changes[j].CreatedAt)
+	})
+
+	state := Discipline{
+		Name:          d.Name,
+		ControlEvents: append([]ControlEvent(nil), d.ControlEvents...),
+	}
+	points := make([]TrendPoint, 0, len(changes))
+	for i := len(changes) - 1; i >= 0; i-- {
+		if forecast := state.Forecast(); forecast.HasCurrentScore {
+			points = append(points, TrendPoint{At: changes[i].CreatedAt, Score: forecast.CurrentScore})
+		}
+		state.ControlEvents[controlEventIndexes[changes[i].ControlEvent]].Grade = ParseGrade(changes[i].OldGrade)
+	}
+
+	for i := len(points) - 1; i >= 0; i-- {
+		trend.Points = append(trend.Points, points[i])
+	}
+
+	return trend
+}
@@ -0,0 +1,53 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestProgressTableTrendsSkipOtherSemesters(t *testing.T) {
+	at := func(day int) time.Time {
+		return time.Date(2024, time.March, day, 0, 0, 0, 0, time.UTC)
+	}
+
+	progressTable := &ProgressTable{
+		Semester: Semester{ID: "2", Name: "Весна 2024"},
+		Disciplines: []Discipline{{
+			Name:          "Математика",
+			ControlEvents: []ControlEvent{controlEvent("КМ-1", "5")},
+		}},
+	}
+	history := []*GradeChange{
+		// сохранено до появления семестров в истории
+		{Discipline: "Математика", ControlEvent: "КМ-1", OldGrade: "", NewGrade: "4", CreatedAt: at(1)},
+		// одноимённая дисциплина прошлого семестра
+		{
+			Discipline:   "Математика",
+			ControlEvent: "КМ-1",
+			OldGrade:     "",
+			NewGrade:     "3",
+			CreatedAt:    at(2),
+			Semester:     "Осень 2023",
+		},
+		{
+			Discipline:   "Математика",
+			ControlEvent: "КМ-1",
+			OldGrade:     "4",
+			NewGrade:     "5",
+			CreatedAt:    at(3),
+			Semester:     "Весна 2024",
+		},
+	}
+
+	want := []DisciplineTrend{{
+		Discipline: "Математика",
+		Points: []TrendPoint{
+			{At: at(1), Score: 4},
+			{At: at(3), Score: 5},
+		},
+	}}
+	if got := progressTable.Trends(history); !reflect.DeepEqual(got, want) {
+		t.Errorf("Trends() = %+v, want %+v", got, want)
+	}
+}
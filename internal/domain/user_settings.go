@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+type Language string
+
+const (
+	LanguageRussian Language = "ru"
+	LanguageEnglish Language = "en"
+	// DefaultLanguage язык пользователей, которые его не выбирали и не передали language_code
+	DefaultLanguage = LanguageRussian
+)
+
+var Languages = []Language{LanguageRussian, LanguageEnglish}
+
+// russianSpeakingCodes language_code, для которых по умолчанию выбирается русский язык
+var russianSpeakingCodes = map[string]struct{}{
+	"ru": {},
+	"be": {},
+	"uk": {},
+	"kk": {},
+	"ky": {},
+	"uz": {},
+	"tg": {},
+	"hy": {},
+	"az": {},
+}
+
+type UserSettings struct {
+	UserID   int64
+	Language Language
+	Email    string
+	// EmailVerifiedAt nil, пока адрес не подтверждён кодом
+	EmailVerifiedAt      *time.Time
+	NotificationChannels []NotificationChannel
+	// WeeklySummary присылать по понедельникам сводку о мероприятиях недели без оценки
+	WeeklySummary bool
+	// ReminderDaysBefore за сколько дней напоминать о мероприятии без оценки, 0 - не напоминать
+	ReminderDaysBefore int
+	// ShareGrades пользователь согласился, что его баллы попадут в обезличенные распределения GradeDistribution
+	ShareGrades bool
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewUserSettings настройки по умолчанию
+func NewUserSettings(userID int64, language Language) *UserSettings {
+	return &UserSettings{
+		UserID:               userID,
+		Language:             language,
+		NotificationChannels: DefaultNotificationChannels,
+		WeeklySummary:        true,
+		ReminderDaysBefore:   DefaultReminderDaysBefore,
+	}
+}
+
+func (s *UserSettings) IsEmailVerified() bool {
+	return s.Email != "" && s.EmailVerifiedAt != nil
+}
+
+func (s *UserSettings) HasNotificationChannel(channel NotificationChannel) bool {
+	for _, c := range s.NotificationChannels {
+		if c == channel {
+			return true
+		}
+	}
+
+	return false
+}
+
+func ParseLanguage(language string) (Language, bool) {
+	for _, l := range Languages {
+		if string(l) == strings.ToLower(strings.TrimSpace(language)) {
+			return l, true
+		}
+	}
+
+	return "", false
+}
+
+// LanguageFromCode язык по language_code из Telegram (IETF-тег, например "en-US")
+func LanguageFromCode(code string) Language {
+	if code == "" {
+		return DefaultLanguage
+	}
+
+	base, _, _ := strings.Cut(strings.ToLower(code), "-")
+	if _, ok := russianSpeakingCodes[base]; ok {
+		return LanguageRussian
+	}
+
+	return LanguageEnglish
+}
@@ -0,0 +1,41 @@
+package domain
+
+import "time"
+
+// UserState состояние пользователя с точки зрения отслеживания оценок
+type UserState string
+
+const (
+	UserStateNotAuthorized UserState = "not_authorized"
+	UserStateAuthorized    UserState = "authorized"
+	// UserStateSuspended оценки не проверяются, пока пользователь не авторизуется заново
+	UserStateSuspended UserState = "suspended"
+	UserStateDeleted   UserState = "deleted"
+)
+
+// UserStatus сводка о пользователе для администратора
+type UserStatus struct {
+	UserID int64
+	State  UserState
+	// Username пустой, если пользователь ни разу не авторизовался
+	Username  string
+	CreatedAt time.Time
+	// CheckedAt последняя проверка, при которой удалось получить оценки из БАРС, nil если такой не было
+	CheckedAt *time.Time
+	// CheckRequestedAt администратор запросил внеочередную проверку, nil если запроса нет
+	CheckRequestedAt *time.Time
+	SuspendedAt      *time.Time
+	// FailuresCount неудачные попытки получить оценки подряд, см. AuthorizationFailure
+	FailuresCount int
+	LastError     string
+	// OutboxBacklog изменения оценок, которые ещё не доставлены во все каналы
+	OutboxBacklog int
+}
+
+// UsersCounts число пользователей по состояниям. Registered включает всех, кроме удалённых
+type UsersCounts struct {
+	Registered int
+	Authorized int
+	Suspended  int
+	Deleted    int
+}
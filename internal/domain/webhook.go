@@ -0,0 +1,12 @@
+package domain
+
+import "time"
+
+type Webhook struct {
+	ID     int64
+	UserID int64
+	URL    string
+	// Secret зашифрован так же, как пароль от БАРС
+	Secret    []byte
+	CreatedAt time.Time
+}
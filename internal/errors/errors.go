@@ -1,9 +1,51 @@
 package errors
 
-import "errors"
+import (
+	"errors"
+	"time"
+)
 
 var (
 	ErrAlreadyAuth                = errors.New("user is already authorized")
+	ErrNotAuth                    = errors.New("user is not authorized")
+	ErrCredentialsSuspended       = errors.New("bars credentials are suspended")
+	ErrSemesterNotFound           = errors.New("semester not found")
 	ErrProgressTableStructChanged = errors.New("progress table structure has been changed")
 	ErrWrongGradesPage            = errors.New("wrong grades page")
+	ErrWrongRecordBookPage        = errors.New("wrong record book page")
+	ErrWrongSchedulePage          = errors.New("wrong schedule page")
+	ErrInvalidEmail               = errors.New("invalid email")
+	ErrEmailNotVerified           = errors.New("email is not verified")
+	ErrVerificationNotFound       = errors.New("email verification not found")
+	ErrVerificationExpired        = errors.New("email verification expired")
+	ErrWrongVerificationCode      = errors.New("wrong verification code")
+	ErrVerificationAttemptsEnded  = errors.New("verification attempts ended")
+	ErrVerificationResendTooSoon  = errors.New("verification code was sent recently")
+	ErrVerificationCodesLimit     = errors.New("verification codes limit reached")
+	ErrInvalidWebhookURL          = errors.New("invalid webhook url")
+	ErrWebhooksLimitReached       = errors.New("webhooks limit reached")
+	ErrWebhookNotFound            = errors.New("webhook not found")
+	ErrAccessTokensLimitReached   = errors.New("access tokens limit reached")
+	ErrAccessTokenNotFound        = errors.New("access token not found")
+	ErrInvalidAccessToken         = errors.New("invalid access token")
+	ErrFeedNotFound               = errors.New("feed not found")
+	ErrInvalidReminderDays        = errors.New("invalid reminder days")
+	ErrNotChatMember              = errors.New("user is not a chat member")
+	ErrBroadcastNotFound          = errors.New("broadcast not found")
+	ErrBroadcastStatusConflict    = errors.New("broadcast status does not allow the action")
+	ErrMessageRejected            = errors.New("telegram rejected the message")
 )
+
+// RetryAfterError телеграм ограничил частоту отправки, повторить можно не раньше чем через RetryAfter
+type RetryAfterError struct {
+	RetryAfter time.Duration
+	Err        error
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
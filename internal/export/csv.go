@@ -0,0 +1,92 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+func progressTableHeader(language domain.Language) []string {
+	return []string{
+		answers.Text(language, answers.ExportSemesterColumn),
+		answers.Text(language, answers.ExportDisciplineColumn),
+		answers.Text(language, answers.ExportControlEventColumn),
+		answers.Text(language, answers.ExportGradeColumn),
+		answers.Text(language, answers.ExportScoreColumn),
+		answers.Text(language, answers.ExportWeightColumn),
+		answers.Text(language, answers.ExportWeekColumn),
+	}
+}
+
+func historyHeader(language domain.Language) []string {
+	return []string{
+		answers.Text(language, answers.ExportDateColumn),
+		answers.Text(language, answers.ExportDisciplineColumn),
+		answers.Text(language, answers.ExportControlEventColumn),
+		answers.Text(language, answers.ExportOldGradeColumn),
+		answers.Text(language, answers.ExportNewGradeColumn),
+	}
+}
+
+// exportCSV история изменений записывается второй таблицей после пустой строки
+func exportCSV(data *Data) ([]byte, error) {
+	var buf bytes.Buffer
+	// BOM нужен, чтобы Excel открыл кириллицу в UTF-8
+	buf.WriteString("\ufeff")
+
+	w := csv.NewWriter(&buf)
+	records := [][]string{progressTableHeader(data.Language)}
+	for _, discipline := range data.ProgressTable.Disciplines {
+		for _, ce := range discipline.ControlEvents {
+			records = append(records, []string{
+				data.ProgressTable.Semester.Name,
+				discipline.Name,
+				ce.Name,
+				answers.Grade(data.Language, ce.Grade),
+				gradeScore(ce.Grade),
+				formatOptionalFloat(ce.Weight),
+				formatOptionalInt(ce.DeadlineWeek),
+			})
+		}
+	}
+
+	if data.History != nil {
+		records = append(records, []string{}, historyHeader(data.Language))
+		for _, change := range data.History {
+			records = append(records, []string{
+				change.CreatedAt.Format(time.DateTime),
+				change.Discipline,
+				answers.ControlEvent(data.Language, change),
+				answers.Grade(data.Language, domain.ParseGrade(change.OldGrade)),
+				answers.Grade(data.Language, domain.ParseGrade(change.NewGrade)),
+			})
+		}
+	}
+
+	if err := w.WriteAll(records); err != nil {
+		return nil, fmt.Errorf("csv.WriteAll: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func formatOptionalFloat(value float64) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
+
+func formatOptionalInt(value int) string {
+	if value == 0 {
+		return ""
+	}
+
+	return strconv.Itoa(value)
+}
@@ -0,0 +1,84 @@
+// Package export сериализует успеваемость пользователя в файлы, не завися от способа доставки
+package export
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatXLSX Format = "xlsx"
+)
+
+var ErrUnknownFormat = errors.New("unknown export format")
+
+// Data History может быть nil, тогда история изменений в файл не попадает.
+// Language определяет язык заголовков таблиц, в JSON ключи не переводятся
+type Data struct {
+	ProgressTable *domain.ProgressTable
+	History       []*domain.GradeChange
+	GeneratedAt   time.Time
+	Language      domain.Language
+}
+
+type File struct {
+	Name     string
+	MIMEType string
+	Content  []byte
+}
+
+func ParseFormat(format string) (Format, error) {
+	switch Format(format) {
+	case FormatCSV, FormatJSON, FormatXLSX:
+		return Format(format), nil
+	default:
+		return "", ErrUnknownFormat
+	}
+}
+
+func Export(format Format, data *Data) (*File, error) {
+	var (
+		content  []byte
+		mimeType string
+		err      error
+	)
+
+	switch format {
+	case FormatCSV:
+		content, err = exportCSV(data)
+		mimeType = "text/csv"
+	case FormatJSON:
+		content, err = exportJSON(data)
+		mimeType = "application/json"
+	case FormatXLSX:
+		content, err = exportXLSX(data)
+		mimeType = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	default:
+		return nil, ErrUnknownFormat
+	}
+	if err != nil {
+		return nil, fmt.Errorf("export %s: %w", format, err)
+	}
+
+	return &File{
+		Name:     fmt.Sprintf("grades_%s.%s", data.GeneratedAt.Format("2006-01-02"), format),
+		MIMEType: mimeType,
+		Content:  content,
+	}, nil
+}
+
+// gradeScore числовое значение оценки для таблиц, пустая строка если оценка не в баллах
+func gradeScore(grade domain.Grade) string {
+	if !grade.HasScore() {
+		return ""
+	}
+
+	return fmt.Sprintf("%g", grade.Score)
+}
@@ -0,0 +1,96 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+type progressTableJSON struct {
+	GeneratedAt time.Time         `json:"generated_at"`
+	Semester    *semesterJSON     `json:"semester,omitempty"`
+	Disciplines []disciplineJSON  `json:"disciplines"`
+	History     []gradeChangeJSON `json:"history,omitempty"`
+}
+
+type semesterJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type disciplineJSON struct {
+	Name          string             `json:"name"`
+	ControlEvents []controlEventJSON `json:"control_events"`
+}
+
+type controlEventJSON struct {
+	Name         string   `json:"name"`
+	Grade        string   `json:"grade"`
+	Score        *float64 `json:"score,omitempty"`
+	Weight       float64  `json:"weight,omitempty"`
+	DeadlineWeek int      `json:"deadline_week,omitempty"`
+}
+
+type gradeChangeJSON struct {
+	Discipline   string    `json:"discipline"`
+	ControlEvent string    `json:"control_event"`
+	OldGrade     string    `json:"old_grade"`
+	NewGrade     string    `json:"new_grade"`
+	CreatedAt    time.Time `json:"created_at"`
+	Semester     string    `json:"semester,omitempty"`
+	RecordBook   bool      `json:"record_book,omitempty"`
+}
+
+func exportJSON(data *Data) ([]byte, error) {
+	result := progressTableJSON{
+		GeneratedAt: data.GeneratedAt,
+		Disciplines: make([]disciplineJSON, 0, len(data.ProgressTable.Disciplines)),
+	}
+	if data.ProgressTable.Semester.ID != "" {
+		result.Semester = &semesterJSON{
+			ID:   data.ProgressTable.Semester.ID,
+			Name: data.ProgressTable.Semester.Name,
+		}
+	}
+
+	for _, discipline := range data.ProgressTable.Disciplines {
+		controlEvents := make([]controlEventJSON, 0, len(discipline.ControlEvents))
+		for _, ce := range discipline.ControlEvents {
+			controlEvent := controlEventJSON{
+				Name:         ce.Name,
+				Grade:        ce.Grade.String(),
+				Weight:       ce.Weight,
+				DeadlineWeek: ce.DeadlineWeek,
+			}
+			if ce.Grade.HasScore() {
+				score := ce.Grade.Score
+				controlEvent.Score = &score
+			}
+			controlEvents = append(controlEvents, controlEvent)
+		}
+
+		result.Disciplines = append(result.Disciplines, disciplineJSON{
+			Name:          discipline.Name,
+			ControlEvents: controlEvents,
+		})
+	}
+
+	for _, change := range data.History {
+		result.History = append(result.History, gradeChangeJSON{
+			Discipline:   change.Discipline,
+			ControlEvent: change.ControlEvent,
+			OldGrade:     change.OldGrade,
+			NewGrade:     change.NewGrade,
+			CreatedAt:    change.CreatedAt,
+			Semester:     change.Semester,
+			RecordBook:   change.RecordBook,
+		})
+	}
+
+	content, err := json.MarshalIndent(result, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("json.MarshalIndent: %w", err)
+	}
+
+	return content, nil
+}
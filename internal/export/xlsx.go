@@ -0,0 +1,251 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+const maxSheetNameLength = 31
+
+// cell пустой number означает строковую ячейку
+type cell struct {
+	text   string
+	number string
+}
+
+type sheet struct {
+	name string
+	rows [][]cell
+}
+
+// exportXLSX собирает минимальную книгу OOXML: лист на каждую дисциплину и, если есть, лист с историей
+func exportXLSX(data *Data) ([]byte, error) {
+	sheets := make([]sheet, 0, len(data.ProgressTable.Disciplines)+1)
+	usedNames := make(map[string]struct{})
+	defaultSheetName := answers.Text(data.Language, answers.ExportDefaultSheet)
+	header := progressTableHeader(data.Language)[2:]
+
+	for _, discipline := range data.ProgressTable.Disciplines {
+		rows := [][]cell{textRow(header...)}
+		for _, ce := range discipline.ControlEvents {
+			rows = append(rows, []cell{
+				{text: ce.Name},
+				{text: answers.Grade(data.Language, ce.Grade)},
+				{number: gradeScore(ce.Grade)},
+				{number: formatOptionalFloat(ce.Weight)},
+				{number: formatOptionalInt(ce.DeadlineWeek)},
+			})
+		}
+
+		sheets = append(sheets, sheet{
+			name: uniqueSheetName(discipline.Name, defaultSheetName, usedNames),
+			rows: rows,
+		})
+	}
+
+	if data.History != nil {
+		rows := [][]cell{textRow(historyHeader(data.Language)...)}
+		for _, change := range data.History {
+			rows = append(rows, textRow(
+				change.CreatedAt.Format(time.DateTime),
+				change.Discipline,
+				answers.ControlEvent(data.Language, change),
+				answers.Grade(data.Language, domain.ParseGrade(change.OldGrade)),
+				answers.Grade(data.Language, domain.ParseGrade(change.NewGrade)),
+			))
+		}
+
+		sheets = append(sheets, sheet{
+			name: uniqueSheetName(answers.Text(data.Language, answers.ExportHistorySheet), defaultSheetName, usedNames),
+			rows: rows,
+		})
+	}
+
+	// книга без листов не открывается
+	if len(sheets) == 0 {
+		sheets = append(sheets, sheet{
+			name: uniqueSheetName(data.ProgressTable.Semester.Name, defaultSheetName, usedNames),
+			rows: [][]cell{textRow(header...)},
+		})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	files := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML(len(sheets))},
+		{"_rels/.rels", rootRelsXML},
+		{"xl/workbook.xml", workbookXML(sheets)},
+		{"xl/_rels/workbook.xml.rels", workbookRelsXML(len(sheets))},
+	}
+	for i, s := range sheets {
+		files = append(files, struct {
+			name    string
+			content string
+		}{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), worksheetXML(s.rows)})
+	}
+
+	for _, file := range files {
+		w, err := zw.Create(file.name)
+		if err != nil {
+			return nil, fmt.Errorf("zw.Create: %w", err)
+		}
+		if _, err = w.Write([]byte(file.content)); err != nil {
+			return nil, fmt.Errorf("w.Write: %w", err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("zw.Close: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func textRow(values ...string) []cell {
+	row := make([]cell, 0, len(values))
+	for _, value := range values {
+		row = append(row, cell{text: value})
+	}
+
+	return row
+}
+
+// uniqueSheetName Excel запрещает в названиях листов символы []:*?/\ и ограничивает длину 31 символом
+func uniqueSheetName(name string, defaultName string, used map[string]struct{}) string {
+	name = strings.Map(func(r rune) rune {
+		if strings.ContainsRune(`[]:*?/\`, r) {
+			return ' '
+		}
+		return r
+	}, strings.TrimSpace(name))
+	if name == "" {
+		name = defaultName
+	}
+	name = truncateRunes(name, maxSheetNameLength)
+
+	candidate := name
+	for i := 2; ; i++ {
+		if _, ok := used[strings.ToLower(candidate)]; !ok {
+			break
+		}
+		suffix := fmt.Sprintf(" (%d)", i)
+		candidate = truncateRunes(name, maxSheetNameLength-utf8.RuneCountInString(suffix)) + suffix
+	}
+	used[strings.ToLower(candidate)] = struct{}{}
+
+	return candidate
+}
+
+func truncateRunes(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
+
+func escapeXML(s string) string {
+	var b strings.Builder
+	_ = xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// columnName переводит индекс колонки с нуля в буквенное обозначение: 0 -> A, 26 -> AA
+func columnName(index int) string {
+	name := ""
+	for index >= 0 {
+		name = string(rune('A'+index%26)) + name
+		index = index/26 - 1
+	}
+
+	return name
+}
+
+func worksheetXML(rows [][]cell) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main"><sheetData>`)
+	for i, row := range rows {
+		rowNumber := strconv.Itoa(i + 1)
+		b.WriteString(`<row r="` + rowNumber + `">`)
+		for j, c := range row {
+			ref := columnName(j) + rowNumber
+			switch {
+			case c.number != "":
+				b.WriteString(`<c r="` + ref + `"><v>` + c.number + `</v></c>`)
+			case c.text != "":
+				b.WriteString(`<c r="` + ref + `" t="inlineStr"><is><t xml:space="preserve">` +
+					escapeXML(c.text) + `</t></is></c>`)
+			}
+		}
+		b.WriteString(`</row>`)
+	}
+	b.WriteString(`</sheetData></worksheet>`)
+
+	return b.String()
+}
+
+func contentTypesXML(sheetsCount int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">`)
+	b.WriteString(`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>`)
+	b.WriteString(`<Default Extension="xml" ContentType="application/xml"/>`)
+	b.WriteString(`<Override PartName="/xl/workbook.xml" ` +
+		`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>`)
+	for i := 1; i <= sheetsCount; i++ {
+		b.WriteString(fmt.Sprintf(`<Override PartName="/xl/worksheets/sheet%d.xml" `+
+			`ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i))
+	}
+	b.WriteString(`</Types>`)
+
+	return b.String()
+}
+
+const rootRelsXML = xml.Header +
+	`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" ` +
+	`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" ` +
+	`Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+func workbookXML(sheets []sheet) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><sheets>`)
+	for i, s := range sheets {
+		b.WriteString(fmt.Sprintf(`<sheet name="%s" sheetId="%d" r:id="rId%d"/>`,
+			escapeXML(s.name), i+1, i+1))
+	}
+	b.WriteString(`</sheets></workbook>`)
+
+	return b.String()
+}
+
+func workbookRelsXML(sheetsCount int) string {
+	var b strings.Builder
+	b.WriteString(xml.Header)
+	b.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i := 1; i <= sheetsCount; i++ {
+		b.WriteString(fmt.Sprintf(`<Relationship Id="rId%d" `+
+			`Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" `+
+			`Target="worksheets/sheet%d.xml"/>`, i, i))
+	}
+	b.WriteString(`</Relationships>`)
+
+	return b.String()
+}
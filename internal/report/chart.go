@@ -0,0 +1,224 @@
+package report
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"math"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"golang.org/x/image/font"
+)
+
+const (
+	chartWidth          = 960
+	chartPlotHeight     = 420
+	chartMarginLeft     = 56
+	chartMarginRight    = 32
+	chartMarginTop      = 64
+	chartMarginBottom   = 48
+	chartXTicksCount    = 5
+	chartLineThickness  = 3
+	chartMarkerRadius   = 5
+	chartLegendRowWidth = chartWidth - chartMarginLeft - chartMarginRight
+	chartLegendMarker   = 14
+	// chartMinScoreRange верхняя граница оси Y не ниже максимальной оценки
+	chartMinScoreRange = 5
+)
+
+// ErrEmptyTrend в истории нет ни одного изменения, по которому можно построить график
+var ErrEmptyTrend = errors.New("trend has no points")
+
+var (
+	chartAxisColor = color.RGBA{R: 0x60, G: 0x60, B: 0x60, A: 0xff}
+	chartPalette   = []color.RGBA{
+		{R: 0x1f, G: 0x77, B: 0xb4, A: 0xff},
+		{R: 0xff, G: 0x7f, B: 0x0e, A: 0xff},
+		{R: 0x2c, G: 0xa0, B: 0x2c, A: 0xff},
+		{R: 0xd6, G: 0x27, B: 0x28, A: 0xff},
+		{R: 0x94, G: 0x67, B: 0xbd, A: 0xff},
+		{R: 0x8c, G: 0x56, B: 0x4b, A: 0xff},
+		{R: 0xe3, G: 0x77, B: 0xc2, A: 0xff},
+		{R: 0x7f, G: 0x7f, B: 0x7f, A: 0xff},
+		{R: 0xbc, G: 0xbd, B: 0x22, A: 0xff},
+		{R: 0x17, G: 0xbe, B: 0xcf, A: 0xff},
+	}
+)
+
+// DisciplineTrendChart рисует линейный график балла одной дисциплины
+func DisciplineTrendChart(trend domain.DisciplineTrend) ([]byte, error) {
+	return renderTrendChart(trend.Discipline, []domain.DisciplineTrend{trend}, false)
+}
+
+// TrendsChart рисует графики всех дисциплин на одних осях с легендой под графиком
+func TrendsChart(title string, trends []domain.DisciplineTrend) ([]byte, error) {
+	return renderTrendChart(title, trends, true)
+}
+
+func renderTrendChart(title string, trends []domain.DisciplineTrend, withLegend bool) ([]byte, error) {
+	nonEmpty := make([]domain.DisciplineTrend, 0, len(trends))
+	for _, trend := range trends {
+		if len(trend.Points) > 0 {
+			nonEmpty = append(nonEmpty, trend)
+		}
+	}
+	if len(nonEmpty) == 0 {
+		return nil, ErrEmptyTrend
+	}
+
+	if err := loadFonts(); err != nil {
+		return nil, fmt.Errorf("loadFonts: %w", err)
+	}
+	regularFace, err := newFace(regularFont, 13)
+	if err != nil {
+		return nil, err
+	}
+	defer regularFace.Close()
+	titleFace, err := newFace(boldFont, 18)
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+
+	lineHeight := regularFace.Metrics().Height.Ceil()
+	legendHeight := 0
+	if withLegend {
+		legendHeight = len(nonEmpty)*(lineHeight+4) + imagePadding
+	}
+	height := chartMarginTop + chartPlotHeight + chartMarginBottom + legendHeight
+
+	img := image.NewRGBA(image.Rect(0, 0, chartWidth, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	titleWidth := chartWidth - 2*imagePadding
+	drawText(img, titleFace, truncateText(titleFace, title, titleWidth), imagePadding,
+		imagePadding+titleFace.Metrics().Ascent.Ceil())
+
+	minTime, maxTime := nonEmpty[0].Points[0].At, nonEmpty[0].Points[0].At
+	maxScore := float64(chartMinScoreRange)
+	for _, trend := range nonEmpty {
+		for _, point := range trend.Points {
+			if point.At.Before(minTime) {
+				minTime = point.At
+			}
+			if point.At.After(maxTime) {
+				maxTime = point.At
+			}
+			maxScore = math.Max(maxScore, math.Ceil(point.Score))
+		}
+	}
+	if !maxTime.After(minTime) {
+		minTime = minTime.Add(-12 * time.Hour)
+		maxTime = maxTime.Add(12 * time.Hour)
+	}
+
+	plot := image.Rect(chartMarginLeft, chartMarginTop, chartWidth-chartMarginRight, chartMarginTop+chartPlotHeight)
+	toX := func(t time.Time) int {
+		ratio := float64(t.Sub(minTime)) / float64(maxTime.Sub(minTime))
+		return plot.Min.X + int(math.Round(ratio*float64(plot.Dx())))
+	}
+	toY := func(score float64) int {
+		return plot.Max.Y - int(math.Round(score/maxScore*float64(plot.Dy())))
+	}
+
+	// горизонтальная сетка по целым баллам
+	for score := 0; score <= int(maxScore); score++ {
+		y := toY(float64(score))
+		drawHorizontalLine(img, plot.Min.X, plot.Max.X, y)
+		label := fmt.Sprintf("%d", score)
+		drawText(img, regularFace, label, plot.Min.X-8-font.MeasureString(regularFace, label).Ceil(),
+			y+regularFace.Metrics().Ascent.Ceil()/2)
+	}
+
+	for i := 0; i < chartXTicksCount; i++ {
+		t := minTime.Add(time.Duration(float64(maxTime.Sub(minTime)) * float64(i) / float64(chartXTicksCount-1)))
+		x := toX(t)
+		fillRect(img, image.Rect(x, plot.Max.Y, x+1, plot.Max.Y+6), chartAxisColor)
+		drawCenteredText(img, regularFace, t.Format("02.01"), x-40, 80, plot.Max.Y+8+regularFace.Metrics().Ascent.Ceil())
+	}
+
+	fillRect(img, image.Rect(plot.Min.X, plot.Min.Y, plot.Min.X+1, plot.Max.Y+1), chartAxisColor)
+	fillRect(img, image.Rect(plot.Min.X, plot.Max.Y, plot.Max.X+1, plot.Max.Y+1), chartAxisColor)
+
+	for i, trend := range nonEmpty {
+		c := chartPalette[i%len(chartPalette)]
+		for j, point := range trend.Points {
+			x, y := toX(point.At), toY(point.Score)
+			if j > 0 {
+				previous := trend.Points[j-1]
+				drawLine(img, toX(previous.At), toY(previous.Score), x, y, c)
+			}
+			drawMarker(img, x, y, c)
+		}
+	}
+
+	if withLegend {
+		y := plot.Max.Y + chartMarginBottom
+		for i, trend := range nonEmpty {
+			c := chartPalette[i%len(chartPalette)]
+			fillRect(img, image.Rect(chartMarginLeft, y+2, chartMarginLeft+chartLegendMarker, y+2+chartLegendMarker), c)
+			name := truncateText(regularFace, trend.Discipline, chartLegendRowWidth-chartLegendMarker-8)
+			drawText(img, regularFace, name, chartMarginLeft+chartLegendMarker+8, y+regularFace.Metrics().Ascent.Ceil())
+			y += lineHeight + 4
+		}
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("png.Encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// drawLine линия алгоритмом Брезенхэма, толщина задаётся квадратной кистью
+func drawLine(img draw.Image, x0, y0, x1, y1 int, c color.Color) {
+	dx, dy := abs(x1-x0), -abs(y1-y0)
+	sx, sy := 1, 1
+	if x0 > x1 {
+		sx = -1
+	}
+	if y0 > y1 {
+		sy = -1
+	}
+
+	brush := chartLineThickness / 2
+	for e := dx + dy; ; {
+		fillRect(img, image.Rect(x0-brush, y0-brush, x0+brush+1, y0+brush+1), c)
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+		e2 := 2 * e
+		if e2 >= dy {
+			e += dy
+			x0 += sx
+		}
+		if e2 <= dx {
+			e += dx
+			y0 += sy
+		}
+	}
+}
+
+func drawMarker(img *image.RGBA, x, y int, c color.Color) {
+	for dy := -chartMarkerRadius; dy <= chartMarkerRadius; dy++ {
+		for dx := -chartMarkerRadius; dx <= chartMarkerRadius; dx++ {
+			if dx*dx+dy*dy <= chartMarkerRadius*chartMarkerRadius {
+				img.Set(x+dx, y+dy, c)
+			}
+		}
+	}
+}
+
+func abs(x int) int {
+	if x < 0 {
+		return -x
+	}
+
+	return x
+}
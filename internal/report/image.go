@@ -0,0 +1,260 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strings"
+	"sync"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/math/fixed"
+)
+
+const (
+	imageFontSize        = 16
+	imageTitleFontSize   = 22
+	imagePadding         = 24
+	imageCellPadding     = 10
+	imageNameColumnWidth = 360
+	imageMinColumnWidth  = 64
+	// imageMaxNameLines длинные названия дисциплин обрезаются, чтобы строки таблицы оставались компактными
+	imageMaxNameLines = 3
+)
+
+var (
+	fontsOnce    sync.Once
+	regularFont  *opentype.Font
+	boldFont     *opentype.Font
+	fontsLoadErr error
+
+	gridColor   = color.RGBA{R: 0xc8, G: 0xc8, B: 0xc8, A: 0xff}
+	headerColor = color.RGBA{R: 0xe9, G: 0xec, B: 0xef, A: 0xff}
+)
+
+func loadFonts() error {
+	fontsOnce.Do(func() {
+		regularFont, fontsLoadErr = opentype.Parse(goregular.TTF)
+		if fontsLoadErr != nil {
+			fontsLoadErr = fmt.Errorf("opentype.Parse: %w", fontsLoadErr)
+			return
+		}
+		boldFont, fontsLoadErr = opentype.Parse(gobold.TTF)
+		if fontsLoadErr != nil {
+			fontsLoadErr = fmt.Errorf("opentype.Parse: %w", fontsLoadErr)
+		}
+	})
+
+	return fontsLoadErr
+}
+
+func newFace(f *opentype.Font, size float64) (font.Face, error) {
+	face, err := opentype.NewFace(f, &opentype.FaceOptions{
+		Size:    size,
+		DPI:     72,
+		Hinting: font.HintingFull,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("opentype.NewFace: %w", err)
+	}
+
+	return face, nil
+}
+
+// ProgressTableImage рисует таблицу успеваемости в PNG
+func ProgressTableImage(language domain.Language, progressTable *domain.ProgressTable) ([]byte, error) {
+	if err := loadFonts(); err != nil {
+		return nil, fmt.Errorf("loadFonts: %w", err)
+	}
+
+	regularFace, err := newFace(regularFont, imageFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer regularFace.Close()
+	boldFace, err := newFace(boldFont, imageFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer boldFace.Close()
+	titleFace, err := newFace(boldFont, imageTitleFontSize)
+	if err != nil {
+		return nil, err
+	}
+	defer titleFace.Close()
+
+	t := buildTable(language, progressTable)
+	lineHeight := regularFace.Metrics().Height.Ceil()
+	titleHeight := titleFace.Metrics().Height.Ceil()
+
+	columnWidths := make([]int, len(t.header))
+	for i, header := range t.header {
+		columnWidths[i] = max(imageMinColumnWidth, font.MeasureString(boldFace, header).Ceil()+2*imageCellPadding)
+	}
+	nameLines := make([][]string, len(t.rows))
+	rowHeights := make([]int, len(t.rows))
+	for i, row := range t.rows {
+		for j, c := range row.cells {
+			columnWidths[j] = max(columnWidths[j], font.MeasureString(regularFace, c.text).Ceil()+2*imageCellPadding)
+		}
+		nameLines[i] = wrapText(regularFace, row.name, imageNameColumnWidth-2*imageCellPadding, imageMaxNameLines)
+		rowHeights[i] = len(nameLines[i])*lineHeight + 2*imageCellPadding
+	}
+	headerHeight := lineHeight + 2*imageCellPadding
+
+	tableWidth := imageNameColumnWidth
+	for _, width := range columnWidths {
+		tableWidth += width
+	}
+	tableHeight := headerHeight
+	for _, height := range rowHeights {
+		tableHeight += height
+	}
+
+	width := tableWidth + 2*imagePadding
+	height := titleHeight + imagePadding + tableHeight + 2*imagePadding
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(img, img.Bounds(), image.White, image.Point{}, draw.Src)
+
+	drawText(img, titleFace, title(language, progressTable), imagePadding, imagePadding+titleFace.Metrics().Ascent.Ceil())
+
+	top := imagePadding + titleHeight + imagePadding
+	left := imagePadding
+
+	// шапка таблицы
+	fillRect(img, image.Rect(left, top, left+tableWidth, top+headerHeight), headerColor)
+	drawText(img, boldFace, answers.Text(language, answers.ReportDisciplineColumn),
+		left+imageCellPadding, top+imageCellPadding+boldFace.Metrics().Ascent.Ceil())
+	x := left + imageNameColumnWidth
+	for i, header := range t.header {
+		drawCenteredText(img, boldFace, header, x, columnWidths[i], top+imageCellPadding+boldFace.Metrics().Ascent.Ceil())
+		x += columnWidths[i]
+	}
+
+	y := top + headerHeight
+	for i, row := range t.rows {
+		for j, line := range nameLines[i] {
+			drawText(img, regularFace, line, left+imageCellPadding,
+				y+imageCellPadding+j*lineHeight+regularFace.Metrics().Ascent.Ceil())
+		}
+
+		x = left + imageNameColumnWidth
+		textY := y + (rowHeights[i]-lineHeight)/2 + regularFace.Metrics().Ascent.Ceil()
+		for j, c := range row.cells {
+			r, g, b := statusColor(c.status)
+			fillRect(img, image.Rect(x, y, x+columnWidths[j], y+rowHeights[i]), color.RGBA{R: r, G: g, B: b, A: 0xff})
+			drawCenteredText(img, regularFace, c.text, x, columnWidths[j], textY)
+			x += columnWidths[j]
+		}
+
+		y += rowHeights[i]
+	}
+
+	// сетка рисуется поверх заливки
+	y = top
+	drawHorizontalLine(img, left, left+tableWidth, y)
+	y += headerHeight
+	drawHorizontalLine(img, left, left+tableWidth, y)
+	for _, rowHeight := range rowHeights {
+		y += rowHeight
+		drawHorizontalLine(img, left, left+tableWidth, y)
+	}
+	x = left
+	drawVerticalLine(img, x, top, top+tableHeight)
+	x += imageNameColumnWidth
+	drawVerticalLine(img, x, top, top+tableHeight)
+	for _, columnWidth := range columnWidths {
+		x += columnWidth
+		drawVerticalLine(img, x, top, top+tableHeight)
+	}
+
+	var buf bytes.Buffer
+	if err = png.Encode(&buf, img); err != nil {
+		return nil, fmt.Errorf("png.Encode: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+// wrapText переносит текст по словам так, чтобы каждая строка помещалась в maxWidth
+func wrapText(face font.Face, text string, maxWidth int, maxLines int) []string {
+	words := strings.Fields(text)
+	if len(words) == 0 {
+		return []string{""}
+	}
+
+	lines := make([]string, 0, maxLines)
+	line := words[0]
+	for _, word := range words[1:] {
+		candidate := line + " " + word
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			line = candidate
+			continue
+		}
+		lines = append(lines, line)
+		line = word
+	}
+	lines = append(lines, line)
+
+	if len(lines) > maxLines {
+		lines = lines[:maxLines]
+		lines[maxLines-1] = truncateText(face, lines[maxLines-1]+"…", maxWidth)
+	}
+	for i, l := range lines {
+		lines[i] = truncateText(face, l, maxWidth)
+	}
+
+	return lines
+}
+
+func truncateText(face font.Face, text string, maxWidth int) string {
+	if font.MeasureString(face, text).Ceil() <= maxWidth {
+		return text
+	}
+
+	runes := []rune(strings.TrimSuffix(text, "…"))
+	for len(runes) > 0 {
+		runes = runes[:len(runes)-1]
+		candidate := string(runes) + "…"
+		if font.MeasureString(face, candidate).Ceil() <= maxWidth {
+			return candidate
+		}
+	}
+
+	return ""
+}
+
+func drawText(img draw.Image, face font.Face, text string, x, y int) {
+	d := &font.Drawer{
+		Dst:  img,
+		Src:  image.Black,
+		Face: face,
+		Dot:  fixed.P(x, y),
+	}
+	d.DrawString(text)
+}
+
+func drawCenteredText(img draw.Image, face font.Face, text string, x, width, y int) {
+	textWidth := font.MeasureString(face, text).Ceil()
+	drawText(img, face, text, x+(width-textWidth)/2, y)
+}
+
+func fillRect(img draw.Image, rect image.Rectangle, c color.Color) {
+	draw.Draw(img, rect, image.NewUniform(c), image.Point{}, draw.Src)
+}
+
+func drawHorizontalLine(img draw.Image, x1, x2, y int) {
+	fillRect(img, image.Rect(x1, y, x2+1, y+1), gridColor)
+}
+
+func drawVerticalLine(img draw.Image, x, y1, y2 int) {
+	fillRect(img, image.Rect(x, y1, x+1, y2+1), gridColor)
+}
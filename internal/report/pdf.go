@@ -0,0 +1,140 @@
+package report
+
+import (
+	"bytes"
+	"fmt"
+	"time"
+
+	"github.com/go-pdf/fpdf"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/gofont/goregular"
+)
+
+const (
+	pdfFontFamily       = "go"
+	pdfMargin           = 15.0
+	pdfFontSize         = 10.0
+	pdfLineHeight       = 5.0
+	pdfCellPadding      = 1.5
+	pdfGradeColumnWidth = 45.0
+)
+
+// ProgressTablePDF формирует отчёт за семестр: сводка из domain.SemesterStatistics и оценки по каждой дисциплине
+func ProgressTablePDF(
+	language domain.Language,
+	progressTable *domain.ProgressTable,
+	generatedAt time.Time,
+) ([]byte, error) {
+	pdf := fpdf.New("P", "mm", "A4", "")
+	pdf.SetMargins(pdfMargin, pdfMargin, pdfMargin)
+	pdf.SetAutoPageBreak(true, pdfMargin)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "", goregular.TTF)
+	pdf.AddUTF8FontFromBytes(pdfFontFamily, "B", gobold.TTF)
+	pdf.SetTitle(title(language, progressTable), true)
+	pdf.AliasNbPages("")
+	pdf.SetFooterFunc(func() {
+		pdf.SetY(-pdfMargin + pdfLineHeight)
+		pdf.SetFont(pdfFontFamily, "", 8)
+		pdf.CellFormat(0, pdfLineHeight, fmt.Sprintf("%d / {nb}", pdf.PageNo()), "", 0, "C", false, 0, "")
+	})
+	pdf.AddPage()
+
+	pageWidth, _ := pdf.GetPageSize()
+	contentWidth := pageWidth - 2*pdfMargin
+
+	pdf.SetFont(pdfFontFamily, "B", 16)
+	pdf.MultiCell(contentWidth, 8, title(language, progressTable), "", "L", false)
+	pdf.SetFont(pdfFontFamily, "", pdfFontSize)
+	pdf.CellFormat(contentWidth, pdfLineHeight,
+		answers.Textf(language, answers.ReportGeneratedAt, generatedAt.Format("02.01.2006 15:04")),
+		"", 1, "L", false, 0, "")
+	pdf.Ln(pdfLineHeight)
+
+	writeStatistics(pdf, language, progressTable.Statistics(), contentWidth)
+
+	for _, discipline := range progressTable.Disciplines {
+		pdf.Ln(pdfLineHeight)
+		writeDiscipline(pdf, discipline, contentWidth)
+	}
+
+	var buf bytes.Buffer
+	if err := pdf.Output(&buf); err != nil {
+		return nil, fmt.Errorf("pdf.Output: %w", err)
+	}
+
+	return buf.Bytes(), nil
+}
+
+func writeStatistics(
+	pdf *fpdf.Fpdf,
+	language domain.Language,
+	statistics *domain.SemesterStatistics,
+	width float64,
+) {
+	pdf.SetFont(pdfFontFamily, "B", 12)
+	pdf.CellFormat(width, 7, answers.Text(language, answers.ReportSummary), "", 1, "L", false, 0, "")
+	pdf.SetFont(pdfFontFamily, "", pdfFontSize)
+
+	average := emptyCellText
+	if statistics.HasAverage {
+		average = fmt.Sprintf("%.2f", statistics.Average)
+	}
+	lines := []string{
+		answers.Textf(language, answers.ReportAverage, average),
+		answers.Textf(language, answers.ReportEventsCount, statistics.GradedCount, statistics.PendingCount),
+	}
+
+	atRisk := make([]string, 0)
+	for _, discipline := range statistics.Disciplines {
+		if discipline.IsAtRisk {
+			atRisk = append(atRisk, discipline.Name)
+		}
+	}
+	if len(atRisk) > 0 {
+		lines = append(lines, answers.Text(language, answers.ReportAtRiskHeader))
+		for _, name := range atRisk {
+			lines = append(lines, "• "+name)
+		}
+	}
+
+	for _, line := range lines {
+		pdf.MultiCell(width, pdfLineHeight, line, "", "L", false)
+	}
+}
+
+func writeDiscipline(pdf *fpdf.Fpdf, discipline domain.Discipline, width float64) {
+	pdf.SetFont(pdfFontFamily, "B", 11)
+	pdf.MultiCell(width, 6, discipline.Name, "", "L", false)
+	pdf.SetFont(pdfFontFamily, "", pdfFontSize)
+
+	nameWidth := width - pdfGradeColumnWidth
+	x := pdf.GetX()
+	for _, ce := range discipline.ControlEvents {
+		lines := pdf.SplitText(ce.Name, nameWidth-2*pdfCellPadding)
+		rowHeight := float64(len(lines))*pdfLineHeight + 2*pdfCellPadding
+
+		_, pageHeight := pdf.GetPageSize()
+		if pdf.GetY()+rowHeight > pageHeight-pdfMargin {
+			pdf.AddPage()
+		}
+		y := pdf.GetY()
+
+		pdf.SetDrawColor(0xc8, 0xc8, 0xc8)
+		pdf.Rect(x, y, nameWidth, rowHeight, "D")
+		for i, line := range lines {
+			pdf.SetXY(x+pdfCellPadding, y+pdfCellPadding+float64(i)*pdfLineHeight)
+			pdf.CellFormat(nameWidth-2*pdfCellPadding, pdfLineHeight, line, "", 0, "L", false, 0, "")
+		}
+
+		c := newTableCell(ce.Grade)
+		r, g, b := statusColor(c.status)
+		pdf.SetFillColor(int(r), int(g), int(b))
+		pdf.Rect(x+nameWidth, y, pdfGradeColumnWidth, rowHeight, "FD")
+		pdf.SetXY(x+nameWidth, y)
+		pdf.CellFormat(pdfGradeColumnWidth, rowHeight, c.text, "", 0, "C", false, 0, "")
+
+		pdf.SetXY(x, y+rowHeight)
+	}
+}
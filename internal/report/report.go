@@ -0,0 +1,141 @@
+// Package report рисует таблицу успеваемости в PNG и PDF. Шрифты Go встроены в бинарник и содержат кириллицу
+package report
+
+import (
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type cellStatus int
+
+const (
+	cellStatusEmpty cellStatus = iota
+	cellStatusNeutral
+	cellStatusGood
+	cellStatusSatisfactory
+	cellStatusBad
+)
+
+const emptyCellText = "—"
+
+type tableCell struct {
+	text   string
+	status cellStatus
+}
+
+type tableRow struct {
+	name  string
+	cells []tableCell
+}
+
+// table сводная таблица: строки - дисциплины, столбцы - контрольные мероприятия и итоговые оценки
+type table struct {
+	header []string
+	rows   []tableRow
+}
+
+func buildTable(language domain.Language, progressTable *domain.ProgressTable) *table {
+	controlEventsCount := 0
+	for _, discipline := range progressTable.Disciplines {
+		count := 0
+		for _, ce := range discipline.ControlEvents {
+			if !ce.IsSummary() {
+				count++
+			}
+		}
+		controlEventsCount = max(controlEventsCount, count)
+	}
+
+	t := &table{
+		header: make([]string, 0, controlEventsCount+3),
+		rows:   make([]tableRow, 0, len(progressTable.Disciplines)),
+	}
+	for i := 1; i <= controlEventsCount; i++ {
+		t.header = append(t.header, answers.Textf(language, answers.ControlEventShortName, i))
+	}
+	t.header = append(t.header,
+		answers.Text(language, answers.ReportCurrentControlColumn),
+		answers.Text(language, answers.ReportIntermediateAttestationColumn),
+		answers.Text(language, answers.ReportFinalGradeColumn),
+	)
+
+	for _, discipline := range progressTable.Disciplines {
+		row := tableRow{
+			name:  discipline.Name,
+			cells: make([]tableCell, len(t.header)),
+		}
+		for i := range row.cells {
+			row.cells[i] = tableCell{text: emptyCellText}
+		}
+
+		controlEventIndex := 0
+		for _, ce := range discipline.ControlEvents {
+			index := controlEventIndex
+			switch {
+			case ce.IsCurrentControlScore():
+				index = controlEventsCount
+			case ce.IsIntermediateAttestation():
+				index = controlEventsCount + 1
+			case ce.IsFinalGrade():
+				index = controlEventsCount + 2
+			default:
+				controlEventIndex++
+			}
+			row.cells[index] = newTableCell(ce.Grade)
+		}
+
+		t.rows = append(t.rows, row)
+	}
+
+	return t
+}
+
+func newTableCell(grade domain.Grade) tableCell {
+	if !grade.IsGraded() {
+		return tableCell{text: emptyCellText}
+	}
+
+	return tableCell{text: grade.String(), status: gradeStatus(grade)}
+}
+
+func gradeStatus(grade domain.Grade) cellStatus {
+	switch grade.Kind {
+	case domain.GradeKindPass:
+		return cellStatusGood
+	case domain.GradeKindAbsent, domain.GradeKindFail:
+		return cellStatusBad
+	case domain.GradeKindScore:
+		switch {
+		case grade.Score >= 4:
+			return cellStatusGood
+		case grade.Score >= 3:
+			return cellStatusSatisfactory
+		default:
+			return cellStatusBad
+		}
+	default:
+		return cellStatusNeutral
+	}
+}
+
+// statusColor цвет заливки ячейки в RGB
+func statusColor(status cellStatus) (uint8, uint8, uint8) {
+	switch status {
+	case cellStatusGood:
+		return 0xd4, 0xed, 0xda
+	case cellStatusSatisfactory:
+		return 0xff, 0xf3, 0xcd
+	case cellStatusBad:
+		return 0xf8, 0xd7, 0xda
+	default:
+		return 0xff, 0xff, 0xff
+	}
+}
+
+func title(language domain.Language, progressTable *domain.ProgressTable) string {
+	if progressTable.Semester.Name == "" {
+		return answers.Text(language, answers.ReportTitle)
+	}
+
+	return answers.Textf(language, answers.ReportSemesterTitle, progressTable.Semester.Name)
+}
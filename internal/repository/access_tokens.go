@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type AccessTokens interface {
+	AccessTokens(ctx context.Context, userID int64) ([]*domain.AccessToken, error)
+	// AccessTokenByHash возвращает nil, если токена с таким хэшем нет
+	AccessTokenByHash(ctx context.Context, tokenHash []byte) (*domain.AccessToken, error)
+	// Create заполняет ID созданного токена
+	Create(ctx context.Context, token *domain.AccessToken) error
+	// Delete возвращает false, если у пользователя нет токена с таким id
+	Delete(ctx context.Context, userID int64, tokenID int64) (bool, error)
+	Touch(ctx context.Context, tokenID int64, usedAt time.Time) error
+}
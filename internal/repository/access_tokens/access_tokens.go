@@ -0,0 +1,148 @@
+package access_tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/access_tokens/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) AccessTokens(ctx context.Context, userID int64) ([]*domain.AccessToken, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			token_hash,
+			created_at,
+			last_used_at
+		FROM access_tokens
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	tokens := make([]*domain.AccessToken, 0)
+	for rows.Next() {
+		dboToken := &dbo.AccessToken{}
+		err = rows.Scan(
+			&dboToken.ID,
+			&dboToken.UserID,
+			&dboToken.TokenHash,
+			&dboToken.CreatedAt,
+			&dboToken.LastUsedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		tokens = append(tokens, dboToken.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (r *repo) AccessTokenByHash(ctx context.Context, tokenHash []byte) (*domain.AccessToken, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			token_hash,
+			created_at,
+			last_used_at
+		FROM access_tokens
+		WHERE token_hash = $1
+	`
+
+	dboToken := &dbo.AccessToken{}
+	err := r.db.QueryRow(ctx, query, tokenHash).Scan(
+		&dboToken.ID,
+		&dboToken.UserID,
+		&dboToken.TokenHash,
+		&dboToken.CreatedAt,
+		&dboToken.LastUsedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboToken.ToDomain(), nil
+}
+
+func (r *repo) Create(ctx context.Context, token *domain.AccessToken) error {
+	query := `
+		INSERT INTO access_tokens (
+			user_id,
+			token_hash,
+			created_at
+		)
+		VALUES ($1, $2, $3)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		token.UserID,    // $1
+		token.TokenHash, // $2
+		token.CreatedAt, // $3
+	).Scan(&token.ID)
+	if err != nil {
+		return fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, userID int64, tokenID int64) (bool, error) {
+	query := `
+		DELETE FROM access_tokens
+		WHERE user_id = $1 AND id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, tokenID)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (r *repo) Touch(ctx context.Context, tokenID int64, usedAt time.Time) error {
+	query := `
+		UPDATE access_tokens
+		SET last_used_at = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, tokenID, usedAt)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
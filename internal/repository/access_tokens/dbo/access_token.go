@@ -0,0 +1,25 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type AccessToken struct {
+	ID         int64
+	UserID     int64
+	TokenHash  []byte
+	CreatedAt  time.Time
+	LastUsedAt *time.Time
+}
+
+func (dbo *AccessToken) ToDomain() *domain.AccessToken {
+	return &domain.AccessToken{
+		ID:         dbo.ID,
+		UserID:     dbo.UserID,
+		TokenHash:  dbo.TokenHash,
+		CreatedAt:  dbo.CreatedAt,
+		LastUsedAt: dbo.LastUsedAt,
+	}
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type AuthorizationFailures interface {
+	// Increment увеличивает счётчик неудачных попыток. Если последняя неудача была раньше expiredBefore,
+	// счётчик начинается заново.
+	Increment(
+		ctx context.Context,
+		userID int64,
+		lastError string,
+		expiredBefore time.Time,
+	) (*domain.AuthorizationFailure, error)
+	AuthorizationFailures(ctx context.Context) ([]*domain.AuthorizationFailure, error)
+	Reset(ctx context.Context, userID int64) error
+}
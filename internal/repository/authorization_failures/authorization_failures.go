@@ -0,0 +1,132 @@
+package authorization_failures
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/authorization_failures/dbo"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) Increment(
+	ctx context.Context,
+	userID int64,
+	lastError string,
+	expiredBefore time.Time,
+) (*domain.AuthorizationFailure, error) {
+	query := `
+		INSERT INTO authorization_failures (
+			user_id,
+			count,
+			last_error,
+			first_failure_at,
+			last_failure_at
+		)
+		VALUES ($1, 1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			count = CASE
+				WHEN authorization_failures.last_failure_at < $4 THEN 1
+				ELSE authorization_failures.count + 1
+			END,
+			first_failure_at = CASE
+				WHEN authorization_failures.last_failure_at < $4 THEN $3
+				ELSE authorization_failures.first_failure_at
+			END,
+			last_error = $2,
+			last_failure_at = $3
+		RETURNING
+			user_id,
+			count,
+			last_error,
+			first_failure_at,
+			last_failure_at
+	`
+
+	dboFailure := &dbo.AuthorizationFailure{}
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		userID,        // $1
+		lastError,     // $2
+		time.Now(),    // $3
+		expiredBefore, // $4
+	).Scan(
+		&dboFailure.UserID,
+		&dboFailure.Count,
+		&dboFailure.LastError,
+		&dboFailure.FirstFailureAt,
+		&dboFailure.LastFailureAt,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboFailure.ToDomain(), nil
+}
+
+func (r *repo) AuthorizationFailures(ctx context.Context) ([]*domain.AuthorizationFailure, error) {
+	query := `
+		SELECT
+			user_id,
+			count,
+			last_error,
+			first_failure_at,
+			last_failure_at
+		FROM authorization_failures
+		ORDER BY last_failure_at DESC
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	failures := make([]*domain.AuthorizationFailure, 0)
+	for rows.Next() {
+		dboFailure := &dbo.AuthorizationFailure{}
+		err = rows.Scan(
+			&dboFailure.UserID,
+			&dboFailure.Count,
+			&dboFailure.LastError,
+			&dboFailure.FirstFailureAt,
+			&dboFailure.LastFailureAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		failures = append(failures, dboFailure.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return failures, nil
+}
+
+func (r *repo) Reset(ctx context.Context, userID int64) error {
+	query := `
+		DELETE FROM authorization_failures
+		WHERE user_id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
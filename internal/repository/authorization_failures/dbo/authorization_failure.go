@@ -0,0 +1,25 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type AuthorizationFailure struct {
+	UserID         int64
+	Count          int
+	LastError      string
+	FirstFailureAt time.Time
+	LastFailureAt  time.Time
+}
+
+func (dbo *AuthorizationFailure) ToDomain() *domain.AuthorizationFailure {
+	return &domain.AuthorizationFailure{
+		UserID:         dbo.UserID,
+		Count:          dbo.Count,
+		LastError:      dbo.LastError,
+		FirstFailureAt: dbo.FirstFailureAt,
+		LastFailureAt:  dbo.LastFailureAt,
+	}
+}
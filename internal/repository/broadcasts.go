@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Broadcasts interface {
+	// Create сохраняет рассылку вместе с получателями, отобранными по фильтру, и возвращает её идентификатор
+	Create(ctx context.Context, broadcast *domain.Broadcast) (int64, error)
+	// Broadcast возвращает nil, если рассылки нет
+	Broadcast(ctx context.Context, broadcastID int64) (*domain.Broadcast, error)
+	// Status возвращает пустой статус, если рассылки нет. В отличие от Broadcast не считает получателей
+	Status(ctx context.Context, broadcastID int64) (domain.BroadcastStatus, error)
+	// Broadcasts последние limit рассылок, новые первыми
+	Broadcasts(ctx context.Context, limit int) ([]*domain.Broadcast, error)
+	// BroadcastsByStatus рассылки в статусе status в порядке создания
+	BroadcastsByStatus(ctx context.Context, status domain.BroadcastStatus) ([]*domain.Broadcast, error)
+	// UpdateStatus меняет статус, только если текущий входит в from. Возвращает false, если статус не изменился
+	UpdateStatus(
+		ctx context.Context,
+		broadcastID int64,
+		status domain.BroadcastStatus,
+		from []domain.BroadcastStatus,
+		updatedAt time.Time,
+	) (bool, error)
+	// PendingRecipients до limit получателей, которым рассылка ещё не отправлялась
+	PendingRecipients(ctx context.Context, broadcastID int64, limit int) ([]int64, error)
+	SaveRecipientResult(ctx context.Context, recipient *domain.BroadcastRecipient) error
+}
@@ -0,0 +1,338 @@
+package broadcasts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/broadcasts/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+// audienceConditions условия отбора получателей, те же, что и при подсчёте пользователей по состояниям
+var audienceConditions = map[domain.BroadcastAudience]string{
+	domain.BroadcastAudienceAll: `TRUE`,
+	domain.BroadcastAudienceAuthorized: `
+		bc.user_id IS NOT NULL
+		AND bc.deleted_at IS NULL
+		AND bc.suspended_at IS NULL
+	`,
+	domain.BroadcastAudienceNotAuthorized: `
+		(bc.user_id IS NULL OR bc.deleted_at IS NOT NULL)
+	`,
+	domain.BroadcastAudienceSuspended: `
+		bc.user_id IS NOT NULL
+		AND bc.deleted_at IS NULL
+		AND bc.suspended_at IS NOT NULL
+	`,
+}
+
+const selectBroadcastsQuery = `
+	SELECT
+	  b.id,
+	  b.admin_id,
+	  b.text,
+	  b.audience,
+	  b.language,
+	  b.status,
+	  b.created_at,
+	  b.updated_at,
+	  b.finished_at,
+	  COUNT(br.user_id),
+	  COUNT(br.user_id) FILTER (WHERE br.status = 'pending'),
+	  COUNT(br.user_id) FILTER (WHERE br.status = 'sent'),
+	  COUNT(br.user_id) FILTER (WHERE br.status = 'failed')
+	FROM broadcasts AS b
+	LEFT JOIN broadcast_recipients AS br
+	  ON b.id = br.broadcast_id
+`
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) Create(ctx context.Context, broadcast *domain.Broadcast) (int64, error) {
+	condition, ok := audienceConditions[broadcast.Filter.Audience]
+	if !ok {
+		return 0, fmt.Errorf("unknown audience %q", broadcast.Filter.Audience)
+	}
+
+	insertBroadcastQuery := `
+		INSERT INTO broadcasts (
+			admin_id,
+			text,
+			audience,
+			language,
+			status,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $6)
+		RETURNING id
+	`
+
+	// пользователи без настроек получают сообщения на языке по умолчанию
+	insertRecipientsQuery := `
+		INSERT INTO broadcast_recipients (
+			broadcast_id,
+			user_id,
+			status
+		)
+		SELECT $1, u.id, 'pending'
+		FROM users AS u
+		LEFT JOIN bars_credentials AS bc
+		  ON u.id = bc.user_id
+		LEFT JOIN user_settings AS us
+		  ON u.id = us.user_id
+		WHERE u.deleted_at IS NULL
+		AND ($2 = '' OR COALESCE(us.language, $3) = $2)
+		AND ` + condition
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("db.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	var broadcastID int64
+	err = tx.QueryRow(
+		ctx,
+		insertBroadcastQuery,
+		broadcast.AdminID,                 // $1
+		broadcast.Text,                    // $2
+		string(broadcast.Filter.Audience), // $3
+		string(broadcast.Filter.Language), // $4
+		string(broadcast.Status),          // $5
+		broadcast.CreatedAt,               // $6
+	).Scan(&broadcastID)
+	if err != nil {
+		return 0, fmt.Errorf("tx.QueryRow.Scan insertBroadcastQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		insertRecipientsQuery,
+		broadcastID,                       // $1
+		string(broadcast.Filter.Language), // $2
+		string(domain.DefaultLanguage),    // $3
+	)
+	if err != nil {
+		return 0, fmt.Errorf("tx.Exec insertRecipientsQuery: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return 0, fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return broadcastID, nil
+}
+
+func (r *repo) Broadcast(ctx context.Context, broadcastID int64) (*domain.Broadcast, error) {
+	query := selectBroadcastsQuery + `
+	WHERE b.id = $1
+	GROUP BY b.id
+	`
+
+	broadcasts, err := r.broadcasts(ctx, query, broadcastID)
+	if err != nil {
+		return nil, err
+	}
+	if len(broadcasts) == 0 {
+		return nil, nil
+	}
+
+	return broadcasts[0], nil
+}
+
+func (r *repo) Status(ctx context.Context, broadcastID int64) (domain.BroadcastStatus, error) {
+	query := `
+		SELECT status
+		FROM broadcasts
+		WHERE id = $1
+	`
+
+	var status string
+	err := r.db.QueryRow(ctx, query, broadcastID).Scan(&status)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return domain.BroadcastStatus(status), nil
+}
+
+func (r *repo) Broadcasts(ctx context.Context, limit int) ([]*domain.Broadcast, error) {
+	query := selectBroadcastsQuery + `
+	GROUP BY b.id
+	ORDER BY b.id DESC
+	LIMIT $1
+	`
+
+	return r.broadcasts(ctx, query, limit)
+}
+
+func (r *repo) BroadcastsByStatus(ctx context.Context, status domain.BroadcastStatus) ([]*domain.Broadcast, error) {
+	query := selectBroadcastsQuery + `
+	WHERE b.status = $1
+	GROUP BY b.id
+	ORDER BY b.id
+	`
+
+	return r.broadcasts(ctx, query, string(status))
+}
+
+func (r *repo) broadcasts(ctx context.Context, query string, args ...interface{}) ([]*domain.Broadcast, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	broadcasts := make([]*domain.Broadcast, 0)
+	for rows.Next() {
+		dboBroadcast := &dbo.Broadcast{}
+		err = rows.Scan(
+			&dboBroadcast.ID,
+			&dboBroadcast.AdminID,
+			&dboBroadcast.Text,
+			&dboBroadcast.Audience,
+			&dboBroadcast.Language,
+			&dboBroadcast.Status,
+			&dboBroadcast.CreatedAt,
+			&dboBroadcast.UpdatedAt,
+			&dboBroadcast.FinishedAt,
+			&dboBroadcast.Total,
+			&dboBroadcast.Pending,
+			&dboBroadcast.Sent,
+			&dboBroadcast.Failed,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		broadcasts = append(broadcasts, dboBroadcast.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return broadcasts, nil
+}
+
+func (r *repo) UpdateStatus(
+	ctx context.Context,
+	broadcastID int64,
+	status domain.BroadcastStatus,
+	from []domain.BroadcastStatus,
+	updatedAt time.Time,
+) (bool, error) {
+	query := `
+		UPDATE broadcasts
+		SET
+			status = $2,
+			updated_at = $4,
+			finished_at = $5
+		WHERE id = $1
+		AND status = ANY($3)
+	`
+
+	fromStatuses := make([]string, 0, len(from))
+	for _, s := range from {
+		fromStatuses = append(fromStatuses, string(s))
+	}
+
+	var finishedAt *time.Time
+	if status.IsFinished() {
+		finishedAt = &updatedAt
+	}
+
+	tag, err := r.db.Exec(
+		ctx,
+		query,
+		broadcastID,    // $1
+		string(status), // $2
+		fromStatuses,   // $3
+		updatedAt,      // $4
+		finishedAt,     // $5
+	)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (r *repo) PendingRecipients(ctx context.Context, broadcastID int64, limit int) ([]int64, error) {
+	query := `
+		SELECT user_id
+		FROM broadcast_recipients
+		WHERE broadcast_id = $1
+		AND status = 'pending'
+		ORDER BY user_id
+		LIMIT $2
+	`
+
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		broadcastID, // $1
+		limit,       // $2
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := make([]int64, 0, limit)
+	for rows.Next() {
+		var userID int64
+		if err = rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+func (r *repo) SaveRecipientResult(ctx context.Context, recipient *domain.BroadcastRecipient) error {
+	query := `
+		UPDATE broadcast_recipients
+		SET
+			status = $3,
+			error = $4,
+			processed_at = $5
+		WHERE broadcast_id = $1
+		AND user_id = $2
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		recipient.BroadcastID,    // $1
+		recipient.UserID,         // $2
+		string(recipient.Status), // $3
+		recipient.Error,          // $4
+		recipient.ProcessedAt,    // $5
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,45 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Broadcast struct {
+	ID         int64
+	AdminID    int64
+	Text       string
+	Audience   string
+	Language   string
+	Status     string
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+	FinishedAt *time.Time
+	Total      int
+	Pending    int
+	Sent       int
+	Failed     int
+}
+
+func (dbo *Broadcast) ToDomain() *domain.Broadcast {
+	return &domain.Broadcast{
+		ID:      dbo.ID,
+		AdminID: dbo.AdminID,
+		Text:    dbo.Text,
+		Filter: domain.BroadcastFilter{
+			Audience: domain.BroadcastAudience(dbo.Audience),
+			Language: domain.Language(dbo.Language),
+		},
+		Status: domain.BroadcastStatus(dbo.Status),
+		Progress: domain.BroadcastProgress{
+			Total:   dbo.Total,
+			Pending: dbo.Pending,
+			Sent:    dbo.Sent,
+			Failed:  dbo.Failed,
+		},
+		CreatedAt:  dbo.CreatedAt,
+		UpdatedAt:  dbo.UpdatedAt,
+		FinishedAt: dbo.FinishedAt,
+	}
+}
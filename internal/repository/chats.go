@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Chats interface {
+	// Chat возвращает nil, если бота нет в чате
+	Chat(ctx context.Context, chatID int64) (*domain.Chat, error)
+	// Save обновляет название и язык чата, если он уже есть
+	Save(ctx context.Context, chat *domain.Chat) error
+	// Delete удаляет чат вместе с участниками и отметками о сообщениях
+	Delete(ctx context.Context, chatID int64) error
+	// Migrate переносит чат на новый идентификатор, когда группа становится супергруппой
+	Migrate(ctx context.Context, fromChatID int64, toChatID int64) error
+	AddMember(ctx context.Context, chatID int64, userID int64) error
+	// RemoveMember возвращает false, если пользователь не состоял в чате
+	RemoveMember(ctx context.Context, chatID int64, userID int64) (bool, error)
+	// MemberChatIDs чаты, в которые вступил пользователь
+	MemberChatIDs(ctx context.Context, userID int64) ([]int64, error)
+	// IsAnnounced было ли в чате сообщение с таким ключом не раньше since
+	IsAnnounced(ctx context.Context, chatID int64, key string, since time.Time) (bool, error)
+	SaveAnnouncement(ctx context.Context, announcement *domain.ChatAnnouncement) error
+	// DeleteAnnouncements удаляет отметки чата о сообщениях раньше before
+	DeleteAnnouncements(ctx context.Context, chatID int64, before time.Time) error
+}
@@ -0,0 +1,312 @@
+package chats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/chats/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) Chat(ctx context.Context, chatID int64) (*domain.Chat, error) {
+	query := `
+		SELECT
+			id,
+			title,
+			language,
+			created_at
+		FROM chats
+		WHERE id = $1
+	`
+
+	dboChat := &dbo.Chat{}
+	err := r.db.QueryRow(ctx, query, chatID).Scan(
+		&dboChat.ID,
+		&dboChat.Title,
+		&dboChat.Language,
+		&dboChat.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboChat.ToDomain(), nil
+}
+
+func (r *repo) Save(ctx context.Context, chat *domain.Chat) error {
+	query := `
+		INSERT INTO chats (
+			id,
+			title,
+			language,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (id) DO UPDATE
+		SET
+			title = $2,
+			language = $3
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		chat.ID,               // $1
+		chat.Title,            // $2
+		string(chat.Language), // $3
+		chat.CreatedAt,        // $4
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, chatID int64) error {
+	deleteAnnouncementsQuery := `
+		DELETE FROM chat_announcements
+		WHERE chat_id = $1
+	`
+
+	deleteMembersQuery := `
+		DELETE FROM chat_members
+		WHERE chat_id = $1
+	`
+
+	deleteChatQuery := `
+		DELETE FROM chats
+		WHERE id = $1
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, deleteAnnouncementsQuery, chatID)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteAnnouncementsQuery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, deleteMembersQuery, chatID)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteMembersQuery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, deleteChatQuery, chatID)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteChatQuery: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Migrate(ctx context.Context, fromChatID int64, toChatID int64) error {
+	migrateChatQuery := `
+		UPDATE chats
+		SET id = $2
+		WHERE id = $1
+	`
+
+	migrateMembersQuery := `
+		UPDATE chat_members
+		SET chat_id = $2
+		WHERE chat_id = $1
+	`
+
+	migrateAnnouncementsQuery := `
+		UPDATE chat_announcements
+		SET chat_id = $2
+		WHERE chat_id = $1
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, migrateChatQuery, fromChatID, toChatID)
+	if err != nil {
+		return fmt.Errorf("tx.Exec migrateChatQuery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, migrateMembersQuery, fromChatID, toChatID)
+	if err != nil {
+		return fmt.Errorf("tx.Exec migrateMembersQuery: %w", err)
+	}
+
+	_, err = tx.Exec(ctx, migrateAnnouncementsQuery, fromChatID, toChatID)
+	if err != nil {
+		return fmt.Errorf("tx.Exec migrateAnnouncementsQuery: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) AddMember(ctx context.Context, chatID int64, userID int64) error {
+	query := `
+		INSERT INTO chat_members (
+			chat_id,
+			user_id,
+			created_at
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, user_id) DO NOTHING
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		chatID,     // $1
+		userID,     // $2
+		time.Now(), // $3
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) RemoveMember(ctx context.Context, chatID int64, userID int64) (bool, error) {
+	query := `
+		DELETE FROM chat_members
+		WHERE chat_id = $1 AND user_id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, chatID, userID)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (r *repo) MemberChatIDs(ctx context.Context, userID int64) ([]int64, error) {
+	query := `
+		SELECT chat_id
+		FROM chat_members
+		WHERE user_id = $1
+		ORDER BY chat_id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	chatIDs := make([]int64, 0)
+	for rows.Next() {
+		var chatID int64
+		if err = rows.Scan(&chatID); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		chatIDs = append(chatIDs, chatID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return chatIDs, nil
+}
+
+func (r *repo) IsAnnounced(ctx context.Context, chatID int64, key string, since time.Time) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM chat_announcements
+			WHERE chat_id = $1
+			AND key = $2
+			AND announced_at >= $3
+		)
+	`
+
+	var isAnnounced bool
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		chatID, // $1
+		key,    // $2
+		since,  // $3
+	).Scan(&isAnnounced)
+	if err != nil {
+		return false, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return isAnnounced, nil
+}
+
+func (r *repo) SaveAnnouncement(ctx context.Context, announcement *domain.ChatAnnouncement) error {
+	query := `
+		INSERT INTO chat_announcements (
+			chat_id,
+			key,
+			announced_at
+		)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (chat_id, key) DO UPDATE
+		SET announced_at = $3
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		announcement.ChatID,      // $1
+		announcement.Key,         // $2
+		announcement.AnnouncedAt, // $3
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteAnnouncements(ctx context.Context, chatID int64, before time.Time) error {
+	query := `
+		DELETE FROM chat_announcements
+		WHERE chat_id = $1
+		AND announced_at < $2
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		chatID, // $1
+		before, // $2
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Chat struct {
+	ID        int64
+	Title     string
+	Language  string
+	CreatedAt time.Time
+}
+
+func (dbo *Chat) ToDomain() *domain.Chat {
+	return &domain.Chat{
+		ID:        dbo.ID,
+		Title:     dbo.Title,
+		Language:  domain.Language(dbo.Language),
+		CreatedAt: dbo.CreatedAt,
+	}
+}
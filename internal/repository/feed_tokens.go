@@ -0,0 +1,16 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type FeedTokens interface {
+	// FeedToken возвращает nil, если у пользователя ещё нет ленты
+	FeedToken(ctx context.Context, userID int64, kind domain.FeedKind) (*domain.FeedToken, error)
+	// FeedTokenByHash возвращает nil, если ленты с таким токеном нет
+	FeedTokenByHash(ctx context.Context, kind domain.FeedKind, tokenHash []byte) (*domain.FeedToken, error)
+	// Save заменяет токен ленты пользователя, если она уже есть
+	Save(ctx context.Context, feedToken *domain.FeedToken) error
+}
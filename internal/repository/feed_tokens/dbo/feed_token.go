@@ -0,0 +1,25 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type FeedToken struct {
+	UserID    int64
+	Kind      string
+	TokenHash []byte
+	Token     []byte
+	CreatedAt time.Time
+}
+
+func (dbo *FeedToken) ToDomain() *domain.FeedToken {
+	return &domain.FeedToken{
+		UserID:    dbo.UserID,
+		Kind:      domain.FeedKind(dbo.Kind),
+		TokenHash: dbo.TokenHash,
+		Token:     dbo.Token,
+		CreatedAt: dbo.CreatedAt,
+	}
+}
@@ -0,0 +1,106 @@
+package feed_tokens
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/feed_tokens/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) FeedToken(ctx context.Context, userID int64, kind domain.FeedKind) (*domain.FeedToken, error) {
+	query := `
+		SELECT
+			user_id,
+			kind,
+			token_hash,
+			token,
+			created_at
+		FROM feed_tokens
+		WHERE user_id = $1 AND kind = $2
+	`
+
+	return r.feedToken(ctx, query, userID, kind)
+}
+
+func (r *repo) FeedTokenByHash(
+	ctx context.Context,
+	kind domain.FeedKind,
+	tokenHash []byte,
+) (*domain.FeedToken, error) {
+	query := `
+		SELECT
+			user_id,
+			kind,
+			token_hash,
+			token,
+			created_at
+		FROM feed_tokens
+		WHERE token_hash = $1 AND kind = $2
+	`
+
+	return r.feedToken(ctx, query, tokenHash, kind)
+}
+
+func (r *repo) feedToken(ctx context.Context, query string, args ...interface{}) (*domain.FeedToken, error) {
+	dboFeedToken := &dbo.FeedToken{}
+	err := r.db.QueryRow(ctx, query, args...).Scan(
+		&dboFeedToken.UserID,
+		&dboFeedToken.Kind,
+		&dboFeedToken.TokenHash,
+		&dboFeedToken.Token,
+		&dboFeedToken.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboFeedToken.ToDomain(), nil
+}
+
+func (r *repo) Save(ctx context.Context, feedToken *domain.FeedToken) error {
+	query := `
+		INSERT INTO feed_tokens (
+			user_id,
+			kind,
+			token_hash,
+			token,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (user_id, kind) DO UPDATE
+		SET
+			token_hash = $3,
+			token = $4,
+			created_at = $5
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		feedToken.UserID,    // $1
+		feedToken.Kind,      // $2
+		feedToken.TokenHash, // $3
+		feedToken.Token,     // $4
+		feedToken.CreatedAt, // $5
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type GradeDistributions interface {
+	// Distributions распределения по мероприятиям дисциплины, в которых не меньше minContributors участников
+	Distributions(
+		ctx context.Context,
+		semester string,
+		discipline string,
+		minContributors int,
+	) ([]*domain.GradeDistribution, error)
+	// Search распределения, в названии дисциплины которых есть query
+	Search(ctx context.Context, query string, minContributors int, limit int) ([]*domain.GradeDistribution, error)
+	// Published все сохранённые распределения вместе с участниками
+	Published(ctx context.Context) ([]*domain.GradeDistribution, error)
+	// Replace заменяет все сохранённые распределения на distributions
+	Replace(ctx context.Context, distributions []*domain.GradeDistribution) error
+}
@@ -0,0 +1,34 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type GradeDistribution struct {
+	Semester     string
+	Discipline   string
+	ControlEvent string
+	Contributors int
+	Q1           float64
+	Median       float64
+	Q3           float64
+	UpdatedAt    time.Time
+	// ContributorIDs читается только для пересчёта распределений
+	ContributorIDs []int64
+}
+
+func (dbo *GradeDistribution) ToDomain() *domain.GradeDistribution {
+	return &domain.GradeDistribution{
+		Semester:       dbo.Semester,
+		Discipline:     dbo.Discipline,
+		ControlEvent:   dbo.ControlEvent,
+		Contributors:   dbo.Contributors,
+		Q1:             dbo.Q1,
+		Median:         dbo.Median,
+		Q3:             dbo.Q3,
+		UpdatedAt:      dbo.UpdatedAt,
+		ContributorIDs: dbo.ContributorIDs,
+	}
+}
@@ -0,0 +1,235 @@
+package grade_distributions
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/grade_distributions/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) Distributions(
+	ctx context.Context,
+	semester string,
+	discipline string,
+	minContributors int,
+) ([]*domain.GradeDistribution, error) {
+	query := `
+		SELECT
+			semester,
+			discipline,
+			control_event,
+			contributors,
+			q1,
+			median,
+			q3,
+			updated_at
+		FROM grade_distributions
+		WHERE semester = $1
+		AND discipline = $2
+		AND contributors >= $3
+	`
+
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		semester,        // $1
+		discipline,      // $2
+		minContributors, // $3
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+
+	return scanDistributions(rows)
+}
+
+func (r *repo) Search(
+	ctx context.Context,
+	query string,
+	minContributors int,
+	limit int,
+) ([]*domain.GradeDistribution, error) {
+	sqlQuery := `
+		SELECT
+			semester,
+			discipline,
+			control_event,
+			contributors,
+			q1,
+			median,
+			q3,
+			updated_at
+		FROM grade_distributions
+		WHERE discipline ILIKE '%' || $1 || '%'
+		AND contributors >= $2
+		ORDER BY contributors DESC, discipline, control_event
+		LIMIT $3
+	`
+
+	rows, err := r.db.Query(
+		ctx,
+		sqlQuery,
+		escapeLike(query), // $1
+		minContributors,   // $2
+		limit,             // $3
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+
+	return scanDistributions(rows)
+}
+
+func (r *repo) Published(ctx context.Context) ([]*domain.GradeDistribution, error) {
+	query := `
+		SELECT
+			semester,
+			discipline,
+			control_event,
+			contributors,
+			q1,
+			median,
+			q3,
+			updated_at,
+			contributor_ids
+		FROM grade_distributions
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	distributions := make([]*domain.GradeDistribution, 0)
+	for rows.Next() {
+		dboDistribution := &dbo.GradeDistribution{}
+		err = rows.Scan(
+			&dboDistribution.Semester,
+			&dboDistribution.Discipline,
+			&dboDistribution.ControlEvent,
+			&dboDistribution.Contributors,
+			&dboDistribution.Q1,
+			&dboDistribution.Median,
+			&dboDistribution.Q3,
+			&dboDistribution.UpdatedAt,
+			&dboDistribution.ContributorIDs,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		distributions = append(distributions, dboDistribution.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return distributions, nil
+}
+
+func scanDistributions(rows pgx.Rows) ([]*domain.GradeDistribution, error) {
+	defer rows.Close()
+
+	distributions := make([]*domain.GradeDistribution, 0)
+	for rows.Next() {
+		dboDistribution := &dbo.GradeDistribution{}
+		err := rows.Scan(
+			&dboDistribution.Semester,
+			&dboDistribution.Discipline,
+			&dboDistribution.ControlEvent,
+			&dboDistribution.Contributors,
+			&dboDistribution.Q1,
+			&dboDistribution.Median,
+			&dboDistribution.Q3,
+			&dboDistribution.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		distributions = append(distributions, dboDistribution.ToDomain())
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return distributions, nil
+}
+
+func (r *repo) Replace(ctx context.Context, distributions []*domain.GradeDistribution) error {
+	deleteQuery := `
+		DELETE FROM grade_distributions
+	`
+
+	insertQuery := `
+		INSERT INTO grade_distributions (
+			semester,
+			discipline,
+			control_event,
+			contributors,
+			q1,
+			median,
+			q3,
+			updated_at,
+			contributor_ids
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	`
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(ctx, deleteQuery)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteQuery: %w", err)
+	}
+
+	for _, distribution := range distributions {
+		_, err = tx.Exec(
+			ctx,
+			insertQuery,
+			distribution.Semester,       // $1
+			distribution.Discipline,     // $2
+			distribution.ControlEvent,   // $3
+			distribution.Contributors,   // $4
+			distribution.Q1,             // $5
+			distribution.Median,         // $6
+			distribution.Q3,             // $7
+			distribution.UpdatedAt,      // $8
+			distribution.ContributorIDs, // $9
+		)
+		if err != nil {
+			return fmt.Errorf("tx.Exec insertQuery: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
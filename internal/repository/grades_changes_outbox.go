@@ -2,11 +2,19 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 )
 
 type GradesChangesOutbox interface {
-	GradesChanges(ctx context.Context, limit int64) ([]*domain.GradeChange, error)
+	// GradesChanges изменения, для которых подошло время следующей попытки доставки, в порядке добавления
+	GradesChanges(ctx context.Context, now time.Time, limit int64) ([]*domain.GradeChange, error)
+	// Postpone откладывает следующую попытку доставить изменение до nextAttemptAt
+	Postpone(ctx context.Context, id int64, nextAttemptAt time.Time) error
+	// Deliveries состояние доставки по каналам для изменений с переданными id
+	Deliveries(ctx context.Context, ids []int64) ([]*domain.NotificationDelivery, error)
+	SaveDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error
+	// Delete удаляет изменения вместе с состоянием их доставки
 	Delete(ctx context.Context, ids []int64) error
 }
@@ -28,6 +28,9 @@ func (dbo *GradeChange) ToDomain() (*domain.GradeChange, error) {
 		ControlEvent: data.ControlEvent,
 		OldGrade:     data.OldGrade,
 		NewGrade:     data.NewGrade,
+		CreatedAt:    dbo.CreatedAt,
+		Semester:     data.Semester,
+		RecordBook:   data.RecordBook,
 	}, nil
 }
 
@@ -36,6 +39,8 @@ type gradeChangeData struct {
 	ControlEvent string `json:"control_event"`
 	OldGrade     string `json:"old_grade"`
 	NewGrade     string `json:"new_grade"`
+	Semester     string `json:"semester,omitempty"`
+	RecordBook   bool   `json:"record_book,omitempty"`
 }
 
 func GradeChangeDataFromDomain(gradeChange *domain.GradeChange) ([]byte, error) {
@@ -44,6 +49,8 @@ func GradeChangeDataFromDomain(gradeChange *domain.GradeChange) ([]byte, error)
 		ControlEvent: gradeChange.ControlEvent,
 		OldGrade:     gradeChange.OldGrade,
 		NewGrade:     gradeChange.NewGrade,
+		Semester:     gradeChange.Semester,
+		RecordBook:   gradeChange.RecordBook,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("json.Marshal: %w", err)
@@ -0,0 +1,31 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type NotificationDelivery struct {
+	GradeChangeID int64
+	Channel       string
+	Endpoint      string
+	DeliveredAt   *time.Time
+	FailuresCount int
+	LastError     string
+	UpdatedAt     time.Time
+	NextAttemptAt time.Time
+}
+
+func (dbo *NotificationDelivery) ToDomain() *domain.NotificationDelivery {
+	return &domain.NotificationDelivery{
+		GradeChangeID: dbo.GradeChangeID,
+		Channel:       domain.NotificationChannel(dbo.Channel),
+		Endpoint:      dbo.Endpoint,
+		DeliveredAt:   dbo.DeliveredAt,
+		FailuresCount: dbo.FailuresCount,
+		LastError:     dbo.LastError,
+		UpdatedAt:     dbo.UpdatedAt,
+		NextAttemptAt: dbo.NextAttemptAt,
+	}
+}
@@ -3,6 +3,7 @@ package grades_changes_outbox
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/database"
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
@@ -17,7 +18,7 @@ func NewRepository(db database.PG) *repo {
 	return &repo{db: db}
 }
 
-func (r *repo) GradesChanges(ctx context.Context, limit int64) ([]*domain.GradeChange, error) {
+func (r *repo) GradesChanges(ctx context.Context, now time.Time, limit int64) ([]*domain.GradeChange, error) {
 	query := `
 		SELECT 
 			id,
@@ -25,10 +26,17 @@ func (r *repo) GradesChanges(ctx context.Context, limit int64) ([]*domain.GradeC
 			grades_change,
 			created_at
 		FROM grades_changes_outbox
-		LIMIT $1
+		WHERE next_attempt_at <= $1
+		ORDER BY id
+		LIMIT $2
 	`
 
-	rows, err := r.db.Query(ctx, query, limit)
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		now,   // $1
+		limit, // $2
+	)
 	if err != nil {
 		return nil, fmt.Errorf("db.Query: %w", err)
 	}
@@ -62,8 +70,121 @@ func (r *repo) GradesChanges(ctx context.Context, limit int64) ([]*domain.GradeC
 	return gradesChanges, nil
 }
 
+func (r *repo) Postpone(ctx context.Context, id int64, nextAttemptAt time.Time) error {
+	query := `
+		UPDATE grades_changes_outbox
+		SET next_attempt_at = $2
+		WHERE id = $1
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		id,            // $1
+		nextAttemptAt, // $2
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Deliveries(ctx context.Context, ids []int64) ([]*domain.NotificationDelivery, error) {
+	query := `
+		SELECT
+			grades_change_id,
+			channel,
+			endpoint,
+			delivered_at,
+			failures_count,
+			last_error,
+			updated_at,
+			next_attempt_at
+		FROM grades_changes_deliveries
+		WHERE grades_change_id = ANY($1::BIGINT[])
+	`
+
+	rows, err := r.db.Query(ctx, query, ids)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries := make([]*domain.NotificationDelivery, 0)
+	for rows.Next() {
+		dboDelivery := &dbo.NotificationDelivery{}
+		err = rows.Scan(
+			&dboDelivery.GradeChangeID,
+			&dboDelivery.Channel,
+			&dboDelivery.Endpoint,
+			&dboDelivery.DeliveredAt,
+			&dboDelivery.FailuresCount,
+			&dboDelivery.LastError,
+			&dboDelivery.UpdatedAt,
+			&dboDelivery.NextAttemptAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		deliveries = append(deliveries, dboDelivery.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *repo) SaveDelivery(ctx context.Context, delivery *domain.NotificationDelivery) error {
+	query := `
+		INSERT INTO grades_changes_deliveries (
+			grades_change_id,
+			channel,
+			endpoint,
+			delivered_at,
+			failures_count,
+			last_error,
+			updated_at,
+			next_attempt_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (grades_change_id, channel, endpoint) DO UPDATE
+		SET
+			delivered_at = $4,
+			failures_count = $5,
+			last_error = $6,
+			updated_at = $7,
+			next_attempt_at = $8
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		delivery.GradeChangeID,   // $1
+		string(delivery.Channel), // $2
+		delivery.Endpoint,        // $3
+		delivery.DeliveredAt,     // $4
+		delivery.FailuresCount,   // $5
+		delivery.LastError,       // $6
+		time.Now(),               // $7
+		delivery.NextAttemptAt,   // $8
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
 func (r *repo) Delete(ctx context.Context, ids []int64) error {
 	query := `
+		WITH deleted_deliveries AS (
+			DELETE FROM grades_changes_deliveries
+			WHERE grades_change_id = ANY($1::BIGINT[])
+		)
 		DELETE FROM grades_changes_outbox
 		WHERE id = ANY($1::BIGINT[])
 	`
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Reminders interface {
+	// Recipients авторизованные пользователи с таблицей успеваемости, у которых включено хоть одно напоминание.
+	// WeeklySummarySent отмечается по сводке с ключом weeklySummaryKey
+	Recipients(ctx context.Context, weeklySummaryKey string) ([]*domain.ReminderRecipient, error)
+	IsSent(ctx context.Context, userID int64, kind domain.ReminderKind, key string) (bool, error)
+	SaveSent(ctx context.Context, reminder *domain.SentReminder) error
+	// DeleteSentBefore удаляет отметки об отправке старше sentBefore
+	DeleteSentBefore(ctx context.Context, sentBefore time.Time) error
+}
@@ -0,0 +1,175 @@
+package reminders
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	dboUsers "github.com/ilyadubrovsky/tracking-bars/internal/repository/users/dbo"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+// Recipients пользователи без сохранённых настроек получают напоминания с настройками по умолчанию
+func (r *repo) Recipients(ctx context.Context, weeklySummaryKey string) ([]*domain.ReminderRecipient, error) {
+	query := `
+	SELECT
+	  u.id,
+	  pt.progress_table,
+	  COALESCE(us.language, $2),
+	  COALESCE(us.weekly_summary, TRUE),
+	  COALESCE(us.reminder_days_before, $3),
+	  sr.user_id IS NOT NULL
+	FROM users AS u
+	JOIN bars_credentials AS bc
+	  ON u.id = bc.user_id
+	JOIN progress_tables AS pt
+	  ON u.id = pt.user_id
+	LEFT JOIN user_settings AS us
+	  ON u.id = us.user_id
+	LEFT JOIN sent_reminders AS sr
+	  ON u.id = sr.user_id
+	  AND sr.kind = $4
+	  AND sr.key = $1
+	WHERE u.deleted_at IS NULL
+	AND bc.deleted_at IS NULL
+	AND bc.suspended_at IS NULL
+	AND (COALESCE(us.weekly_summary, TRUE) OR COALESCE(us.reminder_days_before, $3) > 0)
+	`
+
+	rows, err := r.db.Query(
+		ctx,
+		query,
+		weeklySummaryKey,                         // $1
+		string(domain.DefaultLanguage),           // $2
+		domain.DefaultReminderDaysBefore,         // $3
+		string(domain.ReminderKindWeeklySummary), // $4
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	recipients := make([]*domain.ReminderRecipient, 0)
+	for rows.Next() {
+		var (
+			userID             int64
+			progressTable      []byte
+			language           string
+			weeklySummary      bool
+			reminderDaysBefore int
+			weeklySummarySent  bool
+		)
+		err = rows.Scan(
+			&userID,
+			&progressTable,
+			&language,
+			&weeklySummary,
+			&reminderDaysBefore,
+			&weeklySummarySent,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		recipient := &domain.ReminderRecipient{
+			WeeklySummarySent: weeklySummarySent,
+		}
+		recipient.ProgressTable, err = dboUsers.ProgressTableToDomain(progressTable)
+		if err != nil {
+			return nil, fmt.Errorf("dboUsers.ProgressTableToDomain: %w", err)
+		}
+
+		parsedLanguage, ok := domain.ParseLanguage(language)
+		if !ok {
+			parsedLanguage = domain.DefaultLanguage
+		}
+		recipient.Settings = domain.NewUserSettings(userID, parsedLanguage)
+		recipient.Settings.WeeklySummary = weeklySummary
+		recipient.Settings.ReminderDaysBefore = reminderDaysBefore
+
+		recipients = append(recipients, recipient)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return recipients, nil
+}
+
+func (r *repo) IsSent(ctx context.Context, userID int64, kind domain.ReminderKind, key string) (bool, error) {
+	query := `
+		SELECT EXISTS (
+			SELECT 1
+			FROM sent_reminders
+			WHERE user_id = $1
+			AND kind = $2
+			AND key = $3
+		)
+	`
+
+	var isSent bool
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		userID,       // $1
+		string(kind), // $2
+		key,          // $3
+	).Scan(&isSent)
+	if err != nil {
+		return false, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return isSent, nil
+}
+
+func (r *repo) SaveSent(ctx context.Context, reminder *domain.SentReminder) error {
+	query := `
+		INSERT INTO sent_reminders (
+			user_id,
+			kind,
+			key,
+			sent_at
+		)
+		VALUES ($1, $2, $3, $4)
+		ON CONFLICT (user_id, kind, key) DO UPDATE
+		SET sent_at = $4
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		reminder.UserID,       // $1
+		string(reminder.Kind), // $2
+		reminder.Key,          // $3
+		reminder.SentAt,       // $4
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteSentBefore(ctx context.Context, sentBefore time.Time) error {
+	query := `
+		DELETE FROM sent_reminders
+		WHERE sent_at < $1
+	`
+
+	_, err := r.db.Exec(ctx, query, sentBefore)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
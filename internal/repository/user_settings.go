@@ -0,0 +1,15 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type UserSettings interface {
+	UserSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+	Save(ctx context.Context, settings *domain.UserSettings) error
+	EmailVerification(ctx context.Context, userID int64) (*domain.EmailVerification, error)
+	SaveEmailVerification(ctx context.Context, verification *domain.EmailVerification) error
+	DeleteEmailVerification(ctx context.Context, userID int64) error
+}
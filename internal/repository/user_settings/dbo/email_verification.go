@@ -0,0 +1,31 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type EmailVerification struct {
+	UserID         int64
+	Email          string
+	CodeHash       []byte
+	Attempts       int
+	ExpiresAt      time.Time
+	CreatedAt      time.Time
+	CodesSent      int
+	CodesSentSince time.Time
+}
+
+func (dbo *EmailVerification) ToDomain() *domain.EmailVerification {
+	return &domain.EmailVerification{
+		UserID:         dbo.UserID,
+		Email:          dbo.Email,
+		CodeHash:       dbo.CodeHash,
+		Attempts:       dbo.Attempts,
+		ExpiresAt:      dbo.ExpiresAt,
+		CreatedAt:      dbo.CreatedAt,
+		CodesSent:      dbo.CodesSent,
+		CodesSentSince: dbo.CodesSentSince,
+	}
+}
@@ -0,0 +1,77 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type UserSettings struct {
+	UserID               int64
+	Language             string
+	Email                *string
+	EmailVerifiedAt      *time.Time
+	NotificationChannels []string
+	WeeklySummary        bool
+	ReminderDaysBefore   int
+	ShareGrades          bool
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+func (dbo *UserSettings) ToDomain() *domain.UserSettings {
+	language, ok := domain.ParseLanguage(dbo.Language)
+	if !ok {
+		language = domain.DefaultLanguage
+	}
+
+	channels := make([]domain.NotificationChannel, 0, len(dbo.NotificationChannels))
+	for _, c := range dbo.NotificationChannels {
+		if channel, ok := domain.ParseNotificationChannel(c); ok {
+			channels = append(channels, channel)
+		}
+	}
+	if len(channels) == 0 {
+		channels = domain.DefaultNotificationChannels
+	}
+
+	email := ""
+	if dbo.Email != nil {
+		email = *dbo.Email
+	}
+
+	return &domain.UserSettings{
+		UserID:               dbo.UserID,
+		Language:             language,
+		Email:                email,
+		EmailVerifiedAt:      dbo.EmailVerifiedAt,
+		NotificationChannels: channels,
+		WeeklySummary:        dbo.WeeklySummary,
+		ReminderDaysBefore:   dbo.ReminderDaysBefore,
+		ShareGrades:          dbo.ShareGrades,
+		CreatedAt:            dbo.CreatedAt,
+		UpdatedAt:            dbo.UpdatedAt,
+	}
+}
+
+// EmailFromDomain пустой адрес хранится как NULL
+func EmailFromDomain(email string) *string {
+	if email == "" {
+		return nil
+	}
+
+	return &email
+}
+
+func NotificationChannelsFromDomain(channels []domain.NotificationChannel) []string {
+	if len(channels) == 0 {
+		channels = domain.DefaultNotificationChannels
+	}
+
+	dboChannels := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		dboChannels = append(dboChannels, string(channel))
+	}
+
+	return dboChannels
+}
@@ -0,0 +1,201 @@
+package user_settings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/user_settings/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) UserSettings(ctx context.Context, userID int64) (*domain.UserSettings, error) {
+	query := `
+		SELECT
+			user_id,
+			language,
+			email,
+			email_verified_at,
+			notification_channels,
+			weekly_summary,
+			reminder_days_before,
+			share_grades,
+			created_at,
+			updated_at
+		FROM user_settings
+		WHERE user_id = $1
+	`
+
+	dboSettings := &dbo.UserSettings{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&dboSettings.UserID,
+		&dboSettings.Language,
+		&dboSettings.Email,
+		&dboSettings.EmailVerifiedAt,
+		&dboSettings.NotificationChannels,
+		&dboSettings.WeeklySummary,
+		&dboSettings.ReminderDaysBefore,
+		&dboSettings.ShareGrades,
+		&dboSettings.CreatedAt,
+		&dboSettings.UpdatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboSettings.ToDomain(), nil
+}
+
+func (r *repo) Save(ctx context.Context, settings *domain.UserSettings) error {
+	query := `
+		INSERT INTO user_settings (
+			user_id,
+			language,
+			email,
+			email_verified_at,
+			notification_channels,
+			weekly_summary,
+			reminder_days_before,
+			share_grades,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $9)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			language = $2,
+			email = $3,
+			email_verified_at = $4,
+			notification_channels = $5,
+			weekly_summary = $6,
+			reminder_days_before = $7,
+			share_grades = $8,
+			updated_at = $9
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		settings.UserID,                     // $1
+		string(settings.Language),           // $2
+		dbo.EmailFromDomain(settings.Email), // $3
+		settings.EmailVerifiedAt,            // $4
+		dbo.NotificationChannelsFromDomain(settings.NotificationChannels), // $5
+		settings.WeeklySummary,      // $6
+		settings.ReminderDaysBefore, // $7
+		settings.ShareGrades,        // $8
+		time.Now(),                  // $9
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) EmailVerification(ctx context.Context, userID int64) (*domain.EmailVerification, error) {
+	query := `
+		SELECT
+			user_id,
+			email,
+			code_hash,
+			attempts,
+			expires_at,
+			created_at,
+			codes_sent,
+			codes_sent_since
+		FROM email_verifications
+		WHERE user_id = $1
+	`
+
+	dboVerification := &dbo.EmailVerification{}
+	err := r.db.QueryRow(ctx, query, userID).Scan(
+		&dboVerification.UserID,
+		&dboVerification.Email,
+		&dboVerification.CodeHash,
+		&dboVerification.Attempts,
+		&dboVerification.ExpiresAt,
+		&dboVerification.CreatedAt,
+		&dboVerification.CodesSent,
+		&dboVerification.CodesSentSince,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboVerification.ToDomain(), nil
+}
+
+func (r *repo) SaveEmailVerification(ctx context.Context, verification *domain.EmailVerification) error {
+	query := `
+		INSERT INTO email_verifications (
+			user_id,
+			email,
+			code_hash,
+			attempts,
+			expires_at,
+			created_at,
+			codes_sent,
+			codes_sent_since
+		)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			email = $2,
+			code_hash = $3,
+			attempts = $4,
+			expires_at = $5,
+			created_at = $6,
+			codes_sent = $7,
+			codes_sent_since = $8
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		verification.UserID,         // $1
+		verification.Email,          // $2
+		verification.CodeHash,       // $3
+		verification.Attempts,       // $4
+		verification.ExpiresAt,      // $5
+		verification.CreatedAt,      // $6
+		verification.CodesSent,      // $7
+		verification.CodesSentSince, // $8
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) DeleteEmailVerification(ctx context.Context, userID int64) error {
+	query := `
+		DELETE FROM email_verifications
+		WHERE user_id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
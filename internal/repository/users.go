@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 )
@@ -10,11 +11,44 @@ type Users interface {
 	Save(ctx context.Context, user *domain.User) error
 	User(ctx context.Context, userID int64) (*domain.User, error)
 	Users(ctx context.Context) ([]*domain.User, error)
+	Suspend(ctx context.Context, userID int64) error
+	SuspendedUserIDs(ctx context.Context, suspendedBefore time.Time) ([]int64, error)
 	Delete(ctx context.Context, userID int64) error
+	// UserStatus возвращает nil, если пользователь ни разу не писал боту. Удалённые пользователи тоже находятся
+	UserStatus(ctx context.Context, userID int64) (*domain.UserStatus, error)
+	// UserStatusesByUsername поиск по логину БАРС без учёта регистра, логин могли ввести несколько пользователей
+	UserStatusesByUsername(ctx context.Context, username string) ([]*domain.UserStatus, error)
+	Counts(ctx context.Context) (*domain.UsersCounts, error)
+	// SaveCheckedAt время последней проверки, при которой оценки удалось получить из БАРС
+	SaveCheckedAt(ctx context.Context, userID int64, checkedAt time.Time) error
+	// RequestCheck возвращает false, если оценки пользователя сейчас не отслеживаются
+	RequestCheck(ctx context.Context, userID int64) (bool, error)
+	// CheckRequestedUsers пользователи с запросом внеочередной проверки, в порядке запросов
+	CheckRequestedUsers(ctx context.Context) ([]*domain.User, error)
+	ClearCheckRequest(ctx context.Context, userID int64) error
 	UpdateProgressTable(
 		ctx context.Context,
 		userID int64,
 		progressTable *domain.ProgressTable,
 		gradesChanges []*domain.GradeChange,
 	) error
+	RolloverProgressTable(
+		ctx context.Context,
+		userID int64,
+		oldProgressTable *domain.ProgressTable,
+		newProgressTable *domain.ProgressTable,
+	) error
+	ArchiveProgressTable(ctx context.Context, userID int64, progressTable *domain.ProgressTable) error
+	ArchivedProgressTables(ctx context.Context, userID int64) ([]*domain.ProgressTable, error)
+	RecordBook(ctx context.Context, userID int64) (*domain.RecordBook, error)
+	UpdateRecordBook(
+		ctx context.Context,
+		userID int64,
+		recordBook *domain.RecordBook,
+		gradesChanges []*domain.GradeChange,
+	) error
+	Schedule(ctx context.Context, userID int64) (*domain.Schedule, error)
+	SaveSchedule(ctx context.Context, userID int64, schedule *domain.Schedule) error
+	// GradesHistory изменения, сохранённые позже since. С нулевым since возвращается вся история
+	GradesHistory(ctx context.Context, userID int64, since time.Time) ([]*domain.GradeChange, error)
 }
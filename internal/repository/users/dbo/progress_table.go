@@ -4,34 +4,59 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 )
 
+const deadlineDateLayout = "2006-01-02"
+
 type progressTableData struct {
+	Semester        *semesterData    `json:"semester,omitempty"`
 	DisciplinesData []disciplineData `json:"progress_table"`
 }
 
+type semesterData struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
 type disciplineData struct {
 	Name          string             `json:"name"`
 	ControlEvents []controlEventData `json:"control_events"`
 }
 
 type controlEventData struct {
-	Name  string `json:"name"`
-	Grade string `json:"grade"`
+	Name         string  `json:"name"`
+	Grade        string  `json:"grade"`
+	Weight       float64 `json:"weight,omitempty"`
+	DeadlineWeek int     `json:"deadline_week,omitempty"`
+	// DeadlineDate в формате 2006-01-02, пустая если дата неизвестна
+	DeadlineDate string `json:"deadline_date,omitempty"`
 }
 
 func ProgressTableFromDomain(progressTable *domain.ProgressTable) ([]byte, error) {
 	data := progressTableData{
 		DisciplinesData: make([]disciplineData, len(progressTable.Disciplines)),
 	}
+	if progressTable.Semester.ID != "" {
+		data.Semester = &semesterData{
+			ID:   progressTable.Semester.ID,
+			Name: progressTable.Semester.Name,
+		}
+	}
 	for i, discipline := range progressTable.Disciplines {
 		data.DisciplinesData[i].Name = discipline.Name
 		controlEventsData := make([]controlEventData, len(discipline.ControlEvents))
 		for j, controlEvent := range progressTable.Disciplines[i].ControlEvents {
 			controlEventsData[j].Name = controlEvent.Name
-			controlEventsData[j].Grade = controlEvent.Grade
+			controlEventsData[j].Grade = controlEvent.Grade.Raw
+			controlEventsData[j].Weight = controlEvent.Weight
+			controlEventsData[j].DeadlineWeek = controlEvent.DeadlineWeek
+			if !controlEvent.DeadlineDate.IsZero() {
+				controlEventsData[j].DeadlineDate = controlEvent.DeadlineDate.Format(deadlineDateLayout)
+			}
 		}
 		data.DisciplinesData[i].ControlEvents = controlEventsData
 	}
@@ -57,13 +82,29 @@ func ProgressTableToDomain(progressTableBytes []byte) (*domain.ProgressTable, er
 	progressTable := &domain.ProgressTable{
 		Disciplines: make([]domain.Discipline, 0, len(data.DisciplinesData)),
 	}
+	if data.Semester != nil {
+		progressTable.Semester = domain.Semester{
+			ID:   data.Semester.ID,
+			Name: data.Semester.Name,
+		}
+	}
 	for _, dboDiscipline := range data.DisciplinesData {
 		controlEvents := make([]domain.ControlEvent, 0, len(dboDiscipline.ControlEvents))
 		for _, dboControlEvent := range dboDiscipline.ControlEvents {
-			controlEvents = append(controlEvents, domain.ControlEvent{
-				Name:  dboControlEvent.Name,
-				Grade: dboControlEvent.Grade,
-			})
+			controlEvent := domain.ControlEvent{
+				Name:         dboControlEvent.Name,
+				Grade:        domain.ParseGrade(dboControlEvent.Grade),
+				Weight:       dboControlEvent.Weight,
+				DeadlineWeek: dboControlEvent.DeadlineWeek,
+			}
+			if dboControlEvent.DeadlineDate != "" {
+				deadlineDate, err := time.ParseInLocation(deadlineDateLayout, dboControlEvent.DeadlineDate, config.BARSLocation)
+				if err != nil {
+					return nil, fmt.Errorf("time.ParseInLocation: %w", err)
+				}
+				controlEvent.DeadlineDate = deadlineDate
+			}
+			controlEvents = append(controlEvents, controlEvent)
 		}
 
 		progressTable.Disciplines = append(progressTable.Disciplines, domain.Discipline{
@@ -0,0 +1,49 @@
+package dbo
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+// TestProgressTableOldFormat таблицы, сохранённые до типизированных оценок и семестров, хранят только
+// текст оценки и должны читаться без миграции данных
+func TestProgressTableOldFormat(t *testing.T) {
+	oldFormat := []byte(`{"progress_table":[{"name":"Математика","control_events":[` +
+		`{"name":"КМ-1","grade":"4 (хорошо)"},` +
+		`{"name":"КМ-2","grade":"отсутствует"},` +
+		`{"name":"Зачёт","grade":"зачтено"}` +
+		`]}]}`)
+
+	want := &domain.ProgressTable{
+		Disciplines: []domain.Discipline{{
+			Name: "Математика",
+			ControlEvents: []domain.ControlEvent{
+				{Name: "КМ-1", Grade: domain.Grade{Raw: "4 (хорошо)", Kind: domain.GradeKindScore, Score: 4}},
+				{Name: "КМ-2", Grade: domain.Grade{Kind: domain.GradeKindNotGraded}},
+				{Name: "Зачёт", Grade: domain.Grade{Raw: "зачтено", Kind: domain.GradeKindPass}},
+			},
+		}},
+	}
+
+	progressTable, err := ProgressTableToDomain(oldFormat)
+	if err != nil {
+		t.Fatalf("ProgressTableToDomain: %v", err)
+	}
+	if !reflect.DeepEqual(progressTable, want) {
+		t.Fatalf("ProgressTableToDomain() = %+v, want %+v", progressTable, want)
+	}
+
+	progressTableBytes, err := ProgressTableFromDomain(progressTable)
+	if err != nil {
+		t.Fatalf("ProgressTableFromDomain: %v", err)
+	}
+	roundTrip, err := ProgressTableToDomain(progressTableBytes)
+	if err != nil {
+		t.Fatalf("ProgressTableToDomain after round trip: %v", err)
+	}
+	if !reflect.DeepEqual(roundTrip, want) {
+		t.Errorf("round trip = %+v, want %+v", roundTrip, want)
+	}
+}
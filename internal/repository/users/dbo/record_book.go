@@ -0,0 +1,72 @@
+package dbo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type recordBookData struct {
+	Entries []recordBookEntryData `json:"entries"`
+}
+
+type recordBookEntryData struct {
+	Discipline  string `json:"discipline"`
+	Semester    string `json:"semester"`
+	ControlForm string `json:"control_form"`
+	Mark        string `json:"mark"`
+	Date        string `json:"date"`
+	Teacher     string `json:"teacher"`
+}
+
+func RecordBookFromDomain(recordBook *domain.RecordBook) ([]byte, error) {
+	data := recordBookData{
+		Entries: make([]recordBookEntryData, 0, len(recordBook.Entries)),
+	}
+	for _, entry := range recordBook.Entries {
+		data.Entries = append(data.Entries, recordBookEntryData{
+			Discipline:  entry.Discipline,
+			Semester:    entry.Semester,
+			ControlForm: entry.ControlForm,
+			Mark:        entry.Mark,
+			Date:        entry.Date,
+			Teacher:     entry.Teacher,
+		})
+	}
+
+	recordBookBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return recordBookBytes, nil
+}
+
+func RecordBookToDomain(recordBookBytes []byte) (*domain.RecordBook, error) {
+	if len(recordBookBytes) == 0 {
+		return nil, errors.New("record book bytes is empty")
+	}
+
+	data := recordBookData{}
+	if err := json.Unmarshal(recordBookBytes, &data); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	recordBook := &domain.RecordBook{
+		Entries: make([]domain.RecordBookEntry, 0, len(data.Entries)),
+	}
+	for _, entry := range data.Entries {
+		recordBook.Entries = append(recordBook.Entries, domain.RecordBookEntry{
+			Discipline:  entry.Discipline,
+			Semester:    entry.Semester,
+			ControlForm: entry.ControlForm,
+			Mark:        entry.Mark,
+			Date:        entry.Date,
+			Teacher:     entry.Teacher,
+		})
+	}
+
+	return recordBook, nil
+}
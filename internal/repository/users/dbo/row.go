@@ -2,15 +2,18 @@ package dbo
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 )
 
 type UserGetRow struct {
-	ID            int64
-	Username      *string
-	Password      []byte
-	ProgressTable []byte
+	ID                    int64
+	Username              *string
+	Password              []byte
+	SuspendedAt           *time.Time
+	AuthorizationFailures int
+	ProgressTable         []byte
 }
 
 func (d *UserGetRow) ToDomain() (*domain.User, error) {
@@ -28,8 +31,10 @@ func (d *UserGetRow) ToDomain() (*domain.User, error) {
 
 	if d.Username != nil && len(d.Password) != 0 {
 		user.BarsCredentials = &domain.BarsCredentials{
-			Username: *d.Username,
-			Password: d.Password,
+			Username:              *d.Username,
+			Password:              d.Password,
+			SuspendedAt:           d.SuspendedAt,
+			AuthorizationFailures: d.AuthorizationFailures,
 		}
 	}
 
@@ -0,0 +1,79 @@
+package dbo
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type scheduleData struct {
+	Lessons []lessonData `json:"lessons"`
+}
+
+type lessonData struct {
+	Day        string `json:"day"`
+	TimeSlot   string `json:"time_slot"`
+	Discipline string `json:"discipline"`
+	Room       string `json:"room"`
+	Teacher    string `json:"teacher"`
+	LessonType string `json:"lesson_type"`
+}
+
+func ScheduleFromDomain(schedule *domain.Schedule) ([]byte, error) {
+	data := scheduleData{
+		Lessons: make([]lessonData, 0, len(schedule.Lessons)),
+	}
+	for _, lesson := range schedule.Lessons {
+		data.Lessons = append(data.Lessons, lessonData{
+			Day:        lesson.Day.Format(time.RFC3339),
+			TimeSlot:   lesson.TimeSlot,
+			Discipline: lesson.Discipline,
+			Room:       lesson.Room,
+			Teacher:    lesson.Teacher,
+			LessonType: lesson.LessonType,
+		})
+	}
+
+	scheduleBytes, err := json.Marshal(data)
+	if err != nil {
+		return nil, fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	return scheduleBytes, nil
+}
+
+func ScheduleToDomain(scheduleBytes []byte, updatedAt time.Time) (*domain.Schedule, error) {
+	if len(scheduleBytes) == 0 {
+		return nil, errors.New("schedule bytes is empty")
+	}
+
+	data := scheduleData{}
+	if err := json.Unmarshal(scheduleBytes, &data); err != nil {
+		return nil, fmt.Errorf("json.Unmarshal: %w", err)
+	}
+
+	schedule := &domain.Schedule{
+		Lessons:   make([]domain.Lesson, 0, len(data.Lessons)),
+		UpdatedAt: updatedAt,
+	}
+	for _, lesson := range data.Lessons {
+		day, err := time.Parse(time.RFC3339, lesson.Day)
+		if err != nil {
+			return nil, fmt.Errorf("time.Parse: %w", err)
+		}
+
+		schedule.Lessons = append(schedule.Lessons, domain.Lesson{
+			Day:        day,
+			TimeSlot:   lesson.TimeSlot,
+			Discipline: lesson.Discipline,
+			Room:       lesson.Room,
+			Teacher:    lesson.Teacher,
+			LessonType: lesson.LessonType,
+		})
+	}
+
+	return schedule, nil
+}
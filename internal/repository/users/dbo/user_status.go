@@ -0,0 +1,50 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type UserStatusRow struct {
+	ID                   int64
+	CreatedAt            time.Time
+	DeletedAt            *time.Time
+	Username             *string
+	CredentialsDeletedAt *time.Time
+	SuspendedAt          *time.Time
+	CheckedAt            *time.Time
+	CheckRequestedAt     *time.Time
+	FailuresCount        int
+	LastError            string
+	OutboxBacklog        int
+}
+
+func (d *UserStatusRow) ToDomain() *domain.UserStatus {
+	status := &domain.UserStatus{
+		UserID:           d.ID,
+		CreatedAt:        d.CreatedAt,
+		CheckedAt:        d.CheckedAt,
+		CheckRequestedAt: d.CheckRequestedAt,
+		SuspendedAt:      d.SuspendedAt,
+		FailuresCount:    d.FailuresCount,
+		LastError:        d.LastError,
+		OutboxBacklog:    d.OutboxBacklog,
+	}
+	if d.Username != nil {
+		status.Username = *d.Username
+	}
+
+	switch {
+	case d.DeletedAt != nil:
+		status.State = domain.UserStateDeleted
+	case d.Username == nil || d.CredentialsDeletedAt != nil:
+		status.State = domain.UserStateNotAuthorized
+	case d.SuspendedAt != nil:
+		status.State = domain.UserStateSuspended
+	default:
+		status.State = domain.UserStateAuthorized
+	}
+
+	return status
+}
@@ -50,7 +50,8 @@ func (r *repo) Save(ctx context.Context, user *domain.User) error {
 		    username = $2,
 		    password = $3,
 		    updated_at = $5,
-			deleted_at = $6
+			deleted_at = $6,
+			suspended_at = NULL
 	`
 
 	insertProgressTableQuery := `
@@ -131,10 +132,14 @@ func (r *repo) User(ctx context.Context, userID int64) (*domain.User, error) {
 	  u.id,
 	  bc.username,
 	  bc.password,
+	  bc.suspended_at,
+	  COALESCE(af.count, 0),
 	  pt.progress_table
 	FROM users AS u
 	LEFT JOIN bars_credentials AS bc
 	  ON u.id = bc.user_id
+	LEFT JOIN authorization_failures AS af
+	  ON u.id = af.user_id
 	LEFT JOIN progress_tables AS pt
 	  ON u.id = pt.user_id
 	WHERE id = $1
@@ -146,6 +151,8 @@ func (r *repo) User(ctx context.Context, userID int64) (*domain.User, error) {
 		&row.ID,
 		&row.Username,
 		&row.Password,
+		&row.SuspendedAt,
+		&row.AuthorizationFailures,
 		&row.ProgressTable,
 	)
 	if errors.Is(err, pgx.ErrNoRows) {
@@ -170,15 +177,20 @@ func (r *repo) Users(ctx context.Context) ([]*domain.User, error) {
 	  u.id,
 	  bc.username,
 	  bc.password,
+	  bc.suspended_at,
+	  COALESCE(af.count, 0),
 	  pt.progress_table
 	FROM users AS u
 	LEFT JOIN bars_credentials AS bc
 	  ON u.id = bc.user_id
+	LEFT JOIN authorization_failures AS af
+	  ON u.id = af.user_id
 	LEFT JOIN progress_tables AS pt
 	  ON u.id = pt.user_id
 	WHERE u.deleted_at IS NULL
 	AND bc.user_id IS NOT NULL
-	AND bc.deleted_at IS NULL;
+	AND bc.deleted_at IS NULL
+	AND bc.suspended_at IS NULL;
 	`
 
 	rows, err := r.db.Query(ctx, query)
@@ -196,6 +208,8 @@ func (r *repo) Users(ctx context.Context) ([]*domain.User, error) {
 			&row.ID,
 			&row.Username,
 			&row.Password,
+			&row.SuspendedAt,
+			&row.AuthorizationFailures,
 			&row.ProgressTable,
 		)
 		if err != nil {
@@ -217,12 +231,320 @@ func (r *repo) Users(ctx context.Context) ([]*domain.User, error) {
 	return users, nil
 }
 
+func (r *repo) Suspend(ctx context.Context, userID int64) error {
+	query := `
+		UPDATE bars_credentials
+		SET suspended_at = $2
+		WHERE user_id = $1
+		AND deleted_at IS NULL
+		AND suspended_at IS NULL
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		userID,     // $1
+		time.Now(), // $2
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) SuspendedUserIDs(ctx context.Context, suspendedBefore time.Time) ([]int64, error) {
+	query := `
+		SELECT user_id
+		FROM bars_credentials
+		WHERE deleted_at IS NULL
+		AND suspended_at < $1
+	`
+
+	rows, err := r.db.Query(ctx, query, suspendedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	userIDs := make([]int64, 0)
+	for rows.Next() {
+		var userID int64
+		if err = rows.Scan(&userID); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		userIDs = append(userIDs, userID)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+const selectUserStatusQuery = `
+	SELECT
+	  u.id,
+	  u.created_at,
+	  u.deleted_at,
+	  bc.username,
+	  bc.deleted_at,
+	  bc.suspended_at,
+	  bc.checked_at,
+	  bc.check_requested_at,
+	  COALESCE(af.count, 0),
+	  COALESCE(af.last_error, ''),
+	  (
+	    SELECT COUNT(*)
+	    FROM grades_changes_outbox AS gco
+	    WHERE gco.user_id = u.id
+	  )
+	FROM users AS u
+	LEFT JOIN bars_credentials AS bc
+	  ON u.id = bc.user_id
+	LEFT JOIN authorization_failures AS af
+	  ON u.id = af.user_id
+`
+
+func (r *repo) UserStatus(ctx context.Context, userID int64) (*domain.UserStatus, error) {
+	query := selectUserStatusQuery + `
+	WHERE u.id = $1
+	`
+
+	rows, err := r.userStatuses(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	return rows[0], nil
+}
+
+func (r *repo) UserStatusesByUsername(ctx context.Context, username string) ([]*domain.UserStatus, error) {
+	query := selectUserStatusQuery + `
+	WHERE LOWER(bc.username) = LOWER($1)
+	ORDER BY u.id
+	`
+
+	return r.userStatuses(ctx, query, username)
+}
+
+func (r *repo) userStatuses(ctx context.Context, query string, args ...interface{}) ([]*domain.UserStatus, error) {
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	statuses := make([]*domain.UserStatus, 0)
+	for rows.Next() {
+		row := &dbo.UserStatusRow{}
+		err = rows.Scan(
+			&row.ID,
+			&row.CreatedAt,
+			&row.DeletedAt,
+			&row.Username,
+			&row.CredentialsDeletedAt,
+			&row.SuspendedAt,
+			&row.CheckedAt,
+			&row.CheckRequestedAt,
+			&row.FailuresCount,
+			&row.LastError,
+			&row.OutboxBacklog,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		statuses = append(statuses, row.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return statuses, nil
+}
+
+func (r *repo) Counts(ctx context.Context) (*domain.UsersCounts, error) {
+	query := `
+	SELECT
+	  COUNT(*) FILTER (WHERE u.deleted_at IS NULL),
+	  COUNT(*) FILTER (
+	    WHERE u.deleted_at IS NULL
+	    AND bc.user_id IS NOT NULL
+	    AND bc.deleted_at IS NULL
+	    AND bc.suspended_at IS NULL
+	  ),
+	  COUNT(*) FILTER (
+	    WHERE u.deleted_at IS NULL
+	    AND bc.deleted_at IS NULL
+	    AND bc.suspended_at IS NOT NULL
+	  ),
+	  COUNT(*) FILTER (WHERE u.deleted_at IS NOT NULL)
+	FROM users AS u
+	LEFT JOIN bars_credentials AS bc
+	  ON u.id = bc.user_id
+	`
+
+	counts := &domain.UsersCounts{}
+	err := r.db.QueryRow(ctx, query).Scan(
+		&counts.Registered,
+		&counts.Authorized,
+		&counts.Suspended,
+		&counts.Deleted,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (r *repo) SaveCheckedAt(ctx context.Context, userID int64, checkedAt time.Time) error {
+	query := `
+		UPDATE bars_credentials
+		SET checked_at = $2
+		WHERE user_id = $1
+	`
+
+	_, err := r.db.Exec(
+		ctx,
+		query,
+		userID,    // $1
+		checkedAt, // $2
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) RequestCheck(ctx context.Context, userID int64) (bool, error) {
+	query := `
+		UPDATE bars_credentials AS bc
+		SET check_requested_at = $2
+		FROM users AS u
+		WHERE bc.user_id = $1
+		AND u.id = bc.user_id
+		AND u.deleted_at IS NULL
+		AND bc.deleted_at IS NULL
+		AND bc.suspended_at IS NULL
+	`
+
+	tag, err := r.db.Exec(
+		ctx,
+		query,
+		userID,     // $1
+		time.Now(), // $2
+	)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
+
+func (r *repo) CheckRequestedUsers(ctx context.Context) ([]*domain.User, error) {
+	query := `
+	SELECT
+	  u.id,
+	  bc.username,
+	  bc.password,
+	  bc.suspended_at,
+	  COALESCE(af.count, 0),
+	  pt.progress_table
+	FROM users AS u
+	JOIN bars_credentials AS bc
+	  ON u.id = bc.user_id
+	LEFT JOIN authorization_failures AS af
+	  ON u.id = af.user_id
+	LEFT JOIN progress_tables AS pt
+	  ON u.id = pt.user_id
+	WHERE u.deleted_at IS NULL
+	AND bc.deleted_at IS NULL
+	AND bc.suspended_at IS NULL
+	AND bc.check_requested_at IS NOT NULL
+	ORDER BY bc.check_requested_at
+	`
+
+	rows, err := r.db.Query(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*domain.User, 0)
+	for rows.Next() {
+		row := &dbo.UserGetRow{}
+		err = rows.Scan(
+			&row.ID,
+			&row.Username,
+			&row.Password,
+			&row.SuspendedAt,
+			&row.AuthorizationFailures,
+			&row.ProgressTable,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		user, err := row.ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("row.ToDomain: %w", err)
+		}
+
+		users = append(users, user)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return users, nil
+}
+
+func (r *repo) ClearCheckRequest(ctx context.Context, userID int64) error {
+	query := `
+		UPDATE bars_credentials
+		SET check_requested_at = NULL
+		WHERE user_id = $1
+	`
+
+	_, err := r.db.Exec(ctx, query, userID)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
 func (r *repo) Delete(ctx context.Context, userID int64) error {
 	deleteProgressTableQuery := `
 		DELETE FROM progress_tables 
 		WHERE user_id = $1
 	`
 
+	deleteArchivedProgressTablesQuery := `
+		DELETE FROM archived_progress_tables
+		WHERE user_id = $1
+	`
+
+	deleteRecordBookQuery := `
+		DELETE FROM record_books
+		WHERE user_id = $1
+	`
+
+	deleteScheduleQuery := `
+		DELETE FROM schedules
+		WHERE user_id = $1
+	`
+
 	deleteBarsCredentialsQuery := `
 		UPDATE bars_credentials
 		SET deleted_at = $2
@@ -230,7 +552,62 @@ func (r *repo) Delete(ctx context.Context, userID int64) error {
 	`
 
 	deleteGradesChangesOutboxQuery := `
-		DELETE FROM grades_changes_outbox
+		WITH deleted_grades_changes AS (
+			DELETE FROM grades_changes_outbox
+			WHERE user_id = $1
+			RETURNING id
+		)
+		DELETE FROM grades_changes_deliveries
+		WHERE grades_change_id IN (SELECT id FROM deleted_grades_changes)
+	`
+
+	deleteWebhooksQuery := `
+		DELETE FROM webhooks
+		WHERE user_id = $1
+	`
+
+	deleteAccessTokensQuery := `
+		DELETE FROM access_tokens
+		WHERE user_id = $1
+	`
+
+	deleteFeedTokensQuery := `
+		DELETE FROM feed_tokens
+		WHERE user_id = $1
+	`
+
+	// без адреса канал email не нужен, остальные настройки пользователь сохранит при повторной авторизации
+	deleteEmailQuery := `
+		UPDATE user_settings
+		SET
+			email = NULL,
+			email_verified_at = NULL,
+			notification_channels = array_remove(notification_channels, 'email')
+		WHERE user_id = $1
+	`
+
+	deleteEmailVerificationQuery := `
+		DELETE FROM email_verifications
+		WHERE user_id = $1
+	`
+
+	deleteSentRemindersQuery := `
+		DELETE FROM sent_reminders
+		WHERE user_id = $1
+	`
+
+	deleteChatMembersQuery := `
+		DELETE FROM chat_members
+		WHERE user_id = $1
+	`
+
+	deleteAuthorizationFailuresQuery := `
+		DELETE FROM authorization_failures
+		WHERE user_id = $1
+	`
+
+	deleteGradesHistoryQuery := `
+		DELETE FROM grades_history
 		WHERE user_id = $1
 	`
 
@@ -256,6 +633,33 @@ func (r *repo) Delete(ctx context.Context, userID int64) error {
 		return fmt.Errorf("tx.Exec deleteProgressTableQuery: %w", err)
 	}
 
+	_, err = tx.Exec(
+		ctx,
+		deleteArchivedProgressTablesQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteArchivedProgressTablesQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteRecordBookQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteRecordBookQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteScheduleQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteScheduleQuery: %w", err)
+	}
+
 	_, err = tx.Exec(
 		ctx,
 		deleteBarsCredentialsQuery,
@@ -275,6 +679,87 @@ func (r *repo) Delete(ctx context.Context, userID int64) error {
 		return fmt.Errorf("tx.Exec deleteGradesChangesOutboxQuery: %w", err)
 	}
 
+	_, err = tx.Exec(
+		ctx,
+		deleteGradesHistoryQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteGradesHistoryQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteAuthorizationFailuresQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteAuthorizationFailuresQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteWebhooksQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteWebhooksQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteAccessTokensQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteAccessTokensQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteFeedTokensQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteFeedTokensQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteEmailQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteEmailQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteEmailVerificationQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteEmailVerificationQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteSentRemindersQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteSentRemindersQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		deleteChatMembersQuery,
+		userID, // $1
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec deleteChatMembersQuery: %w", err)
+	}
+
 	_, err = tx.Exec(
 		ctx,
 		deleteUserQuery,
@@ -351,14 +836,356 @@ func (r *repo) UpdateProgressTable(
 	return nil
 }
 
+func (r *repo) RolloverProgressTable(
+	ctx context.Context,
+	userID int64,
+	oldProgressTable *domain.ProgressTable,
+	newProgressTable *domain.ProgressTable,
+) error {
+	updateProgressTableQuery := `
+		UPDATE progress_tables
+		SET progress_table = $2, updated_at = $3
+		WHERE user_id = $1
+	`
+
+	oldProgressTableDBO, err := dbo.ProgressTableFromDomain(oldProgressTable)
+	if err != nil {
+		return fmt.Errorf("dbo.ProgressTableFromDomain (old): %w", err)
+	}
+	newProgressTableDBO, err := dbo.ProgressTableFromDomain(newProgressTable)
+	if err != nil {
+		return fmt.Errorf("dbo.ProgressTableFromDomain (new): %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	timeNow := time.Now()
+	_, err = tx.Exec(
+		ctx,
+		insertArchivedProgressTableQuery,
+		userID,                       // $1
+		oldProgressTable.Semester.ID, // $2
+		oldProgressTableDBO,          // $3
+		timeNow,                      // $4
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec insertArchivedProgressTableQuery: %w", err)
+	}
+
+	_, err = tx.Exec(
+		ctx,
+		updateProgressTableQuery,
+		userID,              // $1
+		newProgressTableDBO, // $2
+		timeNow,             // $3
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec updateProgressTableQuery: %w", err)
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+const insertArchivedProgressTableQuery = `
+	INSERT INTO archived_progress_tables (
+		user_id,
+		semester_id,
+		progress_table,
+		created_at,
+		updated_at
+	)
+	VALUES ($1, $2, $3, $4, $4)
+	ON CONFLICT (user_id, semester_id) DO UPDATE
+	SET
+		progress_table = $3,
+		updated_at = $4
+`
+
+func (r *repo) ArchiveProgressTable(
+	ctx context.Context,
+	userID int64,
+	progressTable *domain.ProgressTable,
+) error {
+	progressTableDBO, err := dbo.ProgressTableFromDomain(progressTable)
+	if err != nil {
+		return fmt.Errorf("dbo.ProgressTableFromDomain: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		insertArchivedProgressTableQuery,
+		userID,                    // $1
+		progressTable.Semester.ID, // $2
+		progressTableDBO,          // $3
+		time.Now(),                // $4
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) ArchivedProgressTables(ctx context.Context, userID int64) ([]*domain.ProgressTable, error) {
+	// идентификаторы семестров в БАРС числовые, при сравнении текстом "10" оказался бы раньше "9"
+	query := `
+		SELECT progress_table
+		FROM archived_progress_tables
+		WHERE user_id = $1
+		ORDER BY CASE WHEN semester_id ~ '^[0-9]+$' THEN semester_id::NUMERIC END, semester_id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	progressTables := make([]*domain.ProgressTable, 0)
+	for rows.Next() {
+		var progressTableDBO []byte
+		if err = rows.Scan(&progressTableDBO); err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		progressTable, err := dbo.ProgressTableToDomain(progressTableDBO)
+		if err != nil {
+			return nil, fmt.Errorf("dbo.ProgressTableToDomain: %w", err)
+		}
+
+		progressTables = append(progressTables, progressTable)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return progressTables, nil
+}
+
+func (r *repo) RecordBook(ctx context.Context, userID int64) (*domain.RecordBook, error) {
+	query := `
+		SELECT record_book
+		FROM record_books
+		WHERE user_id = $1
+	`
+
+	var recordBookDBO []byte
+	err := r.db.QueryRow(ctx, query, userID).Scan(&recordBookDBO)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	recordBook, err := dbo.RecordBookToDomain(recordBookDBO)
+	if err != nil {
+		return nil, fmt.Errorf("dbo.RecordBookToDomain: %w", err)
+	}
+
+	return recordBook, nil
+}
+
+func (r *repo) UpdateRecordBook(
+	ctx context.Context,
+	userID int64,
+	recordBook *domain.RecordBook,
+	gradesChanges []*domain.GradeChange,
+) error {
+	upsertRecordBookQuery := `
+		INSERT INTO record_books (
+			user_id,
+			record_book,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			record_book = $2,
+			updated_at = $3
+	`
+
+	recordBookDBO, err := dbo.RecordBookFromDomain(recordBook)
+	if err != nil {
+		return fmt.Errorf("dbo.RecordBookFromDomain: %w", err)
+	}
+
+	timeNow := time.Now()
+	outboxQuery, outboxValues, err := buildInsertGradesChangesOutboxQuery(gradesChanges, timeNow)
+	if err != nil {
+		return fmt.Errorf("buildInsertGradesChangesOutboxQuery: %w", err)
+	}
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("db.Begin: %w", err)
+	}
+	defer tx.Rollback(ctx)
+
+	_, err = tx.Exec(
+		ctx,
+		upsertRecordBookQuery,
+		userID,        // $1
+		recordBookDBO, // $2
+		timeNow,       // $3
+	)
+	if err != nil {
+		return fmt.Errorf("tx.Exec upsertRecordBookQuery: %w", err)
+	}
+
+	if outboxQuery != "" && len(outboxValues) != 0 {
+		_, err = tx.Exec(
+			ctx,
+			outboxQuery,
+			outboxValues[0],
+			outboxValues[1],
+			outboxValues[2],
+		)
+		if err != nil {
+			return fmt.Errorf("tx.Exec outboxQuery: %w", err)
+		}
+	}
+
+	if err = tx.Commit(ctx); err != nil {
+		return fmt.Errorf("tx.Commit: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Schedule(ctx context.Context, userID int64) (*domain.Schedule, error) {
+	query := `
+		SELECT schedule, updated_at
+		FROM schedules
+		WHERE user_id = $1
+	`
+
+	var (
+		scheduleDBO []byte
+		updatedAt   time.Time
+	)
+	err := r.db.QueryRow(ctx, query, userID).Scan(&scheduleDBO, &updatedAt)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	schedule, err := dbo.ScheduleToDomain(scheduleDBO, updatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("dbo.ScheduleToDomain: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (r *repo) SaveSchedule(ctx context.Context, userID int64, schedule *domain.Schedule) error {
+	query := `
+		INSERT INTO schedules (
+			user_id,
+			schedule,
+			created_at,
+			updated_at
+		)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (user_id) DO UPDATE
+		SET
+			schedule = $2,
+			updated_at = $3
+	`
+
+	scheduleDBO, err := dbo.ScheduleFromDomain(schedule)
+	if err != nil {
+		return fmt.Errorf("dbo.ScheduleFromDomain: %w", err)
+	}
+
+	_, err = r.db.Exec(
+		ctx,
+		query,
+		userID,             // $1
+		scheduleDBO,        // $2
+		schedule.UpdatedAt, // $3
+	)
+	if err != nil {
+		return fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) GradesHistory(ctx context.Context, userID int64, since time.Time) ([]*domain.GradeChange, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			grades_change,
+			created_at
+		FROM grades_history
+		WHERE user_id = $1 AND created_at > $2
+		ORDER BY created_at, id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	gradesChanges := make([]*domain.GradeChange, 0)
+	for rows.Next() {
+		dboGradeChange := &dboOutbox.GradeChange{}
+		err = rows.Scan(
+			&dboGradeChange.ID,
+			&dboGradeChange.UserID,
+			&dboGradeChange.Data,
+			&dboGradeChange.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		gradeChange, err := dboGradeChange.ToDomain()
+		if err != nil {
+			return nil, fmt.Errorf("dboGradeChange.ToDomain: %w", err)
+		}
+
+		gradesChanges = append(gradesChanges, gradeChange)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return gradesChanges, nil
+}
+
 func buildInsertGradesChangesOutboxQuery(gradesChanges []*domain.GradeChange, timeNow time.Time) (string, []interface{}, error) {
 	if len(gradesChanges) == 0 {
 		return "", nil, nil
 	}
 
+	// изменения сохраняются и в историю, из outbox они удаляются после отправки
 	query := `
+		WITH changes AS (
+			SELECT * FROM UNNEST($1::BIGINT[], $2::JSONB[], $3::TIMESTAMPTZ[])
+			AS t (user_id, grades_change, created_at)
+		), history AS (
+			INSERT INTO grades_history (user_id, grades_change, created_at)
+			SELECT user_id, grades_change, created_at FROM changes
+		)
 		INSERT INTO grades_changes_outbox (user_id, grades_change, created_at)
-		SELECT * FROM UNNEST($1::BIGINT[], $2::JSONB[], $3::TIMESTAMPTZ[])
+		SELECT user_id, grades_change, created_at FROM changes
 	`
 
 	userIDs := make([]int64, 0, len(gradesChanges))
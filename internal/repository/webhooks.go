@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Webhooks interface {
+	Webhooks(ctx context.Context, userID int64) ([]*domain.Webhook, error)
+	// Webhook возвращает nil, если у пользователя нет вебхука с таким id
+	Webhook(ctx context.Context, userID int64, webhookID int64) (*domain.Webhook, error)
+	// Create заполняет ID созданного вебхука
+	Create(ctx context.Context, webhook *domain.Webhook) error
+	// Delete возвращает false, если у пользователя нет вебхука с таким id
+	Delete(ctx context.Context, userID int64, webhookID int64) (bool, error)
+}
@@ -0,0 +1,25 @@
+package dbo
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Webhook struct {
+	ID        int64
+	UserID    int64
+	URL       string
+	Secret    []byte
+	CreatedAt time.Time
+}
+
+func (dbo *Webhook) ToDomain() *domain.Webhook {
+	return &domain.Webhook{
+		ID:        dbo.ID,
+		UserID:    dbo.UserID,
+		URL:       dbo.URL,
+		Secret:    dbo.Secret,
+		CreatedAt: dbo.CreatedAt,
+	}
+}
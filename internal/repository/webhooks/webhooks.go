@@ -0,0 +1,134 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/database"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository/webhooks/dbo"
+	"github.com/jackc/pgx/v4"
+)
+
+type repo struct {
+	db database.PG
+}
+
+func NewRepository(db database.PG) *repo {
+	return &repo{db: db}
+}
+
+func (r *repo) Webhooks(ctx context.Context, userID int64) ([]*domain.Webhook, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			url,
+			secret,
+			created_at
+		FROM webhooks
+		WHERE user_id = $1
+		ORDER BY id
+	`
+
+	rows, err := r.db.Query(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("db.Query: %w", err)
+	}
+	defer rows.Close()
+
+	webhooks := make([]*domain.Webhook, 0)
+	for rows.Next() {
+		dboWebhook := &dbo.Webhook{}
+		err = rows.Scan(
+			&dboWebhook.ID,
+			&dboWebhook.UserID,
+			&dboWebhook.URL,
+			&dboWebhook.Secret,
+			&dboWebhook.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("rows.Scan: %w", err)
+		}
+
+		webhooks = append(webhooks, dboWebhook.ToDomain())
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("rows.Err: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (r *repo) Webhook(ctx context.Context, userID int64, webhookID int64) (*domain.Webhook, error) {
+	query := `
+		SELECT
+			id,
+			user_id,
+			url,
+			secret,
+			created_at
+		FROM webhooks
+		WHERE user_id = $1 AND id = $2
+	`
+
+	dboWebhook := &dbo.Webhook{}
+	err := r.db.QueryRow(ctx, query, userID, webhookID).Scan(
+		&dboWebhook.ID,
+		&dboWebhook.UserID,
+		&dboWebhook.URL,
+		&dboWebhook.Secret,
+		&dboWebhook.CreatedAt,
+	)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return dboWebhook.ToDomain(), nil
+}
+
+func (r *repo) Create(ctx context.Context, webhook *domain.Webhook) error {
+	query := `
+		INSERT INTO webhooks (
+			user_id,
+			url,
+			secret,
+			created_at
+		)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id
+	`
+
+	err := r.db.QueryRow(
+		ctx,
+		query,
+		webhook.UserID,    // $1
+		webhook.URL,       // $2
+		webhook.Secret,    // $3
+		webhook.CreatedAt, // $4
+	).Scan(&webhook.ID)
+	if err != nil {
+		return fmt.Errorf("db.QueryRow.Scan: %w", err)
+	}
+
+	return nil
+}
+
+func (r *repo) Delete(ctx context.Context, userID int64, webhookID int64) (bool, error) {
+	query := `
+		DELETE FROM webhooks
+		WHERE user_id = $1 AND id = $2
+	`
+
+	tag, err := r.db.Exec(ctx, query, userID, webhookID)
+	if err != nil {
+		return false, fmt.Errorf("db.Exec: %w", err)
+	}
+
+	return tag.RowsAffected() != 0, nil
+}
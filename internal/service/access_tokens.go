@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type AccessTokens interface {
+	AccessTokens(ctx context.Context, userID int64) ([]*domain.AccessToken, error)
+	// Issue возвращает токен в открытом виде, повторно получить его нельзя
+	Issue(ctx context.Context, userID int64) (*domain.AccessToken, string, error)
+	Revoke(ctx context.Context, userID int64, tokenID int64) error
+	// Authenticate возвращает владельца токена или ErrInvalidAccessToken
+	Authenticate(ctx context.Context, token string) (int64, error)
+}
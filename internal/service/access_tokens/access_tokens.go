@@ -0,0 +1,112 @@
+package access_tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// tokenPrefix помогает узнать токен в логах и сканерах секретов
+	tokenPrefix = "tb_"
+	tokenBytes  = 32
+)
+
+type svc struct {
+	accessTokensRepo repository.AccessTokens
+	cfg              config.API
+}
+
+func NewService(accessTokensRepo repository.AccessTokens, cfg config.API) *svc {
+	return &svc{
+		accessTokensRepo: accessTokensRepo,
+		cfg:              cfg,
+	}
+}
+
+func (s *svc) AccessTokens(ctx context.Context, userID int64) ([]*domain.AccessToken, error) {
+	tokens, err := s.accessTokensRepo.AccessTokens(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("accessTokensRepo.AccessTokens: %w", err)
+	}
+
+	return tokens, nil
+}
+
+func (s *svc) Issue(ctx context.Context, userID int64) (*domain.AccessToken, string, error) {
+	tokens, err := s.AccessTokens(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(tokens) >= s.cfg.MaxTokensPerUser {
+		return nil, "", ierrors.ErrAccessTokensLimitReached
+	}
+
+	raw := make([]byte, tokenBytes)
+	if _, err = rand.Read(raw); err != nil {
+		return nil, "", fmt.Errorf("rand.Read: %w", err)
+	}
+	plainToken := tokenPrefix + base64.RawURLEncoding.EncodeToString(raw)
+
+	token := &domain.AccessToken{
+		UserID:    userID,
+		TokenHash: hashToken(plainToken),
+		CreatedAt: time.Now(),
+	}
+	err = s.accessTokensRepo.Create(ctx, token)
+	if err != nil {
+		return nil, "", fmt.Errorf("accessTokensRepo.Create: %w", err)
+	}
+
+	return token, plainToken, nil
+}
+
+func (s *svc) Revoke(ctx context.Context, userID int64, tokenID int64) error {
+	deleted, err := s.accessTokensRepo.Delete(ctx, userID, tokenID)
+	if err != nil {
+		return fmt.Errorf("accessTokensRepo.Delete: %w", err)
+	}
+	if !deleted {
+		return ierrors.ErrAccessTokenNotFound
+	}
+
+	return nil
+}
+
+func (s *svc) Authenticate(ctx context.Context, plainToken string) (int64, error) {
+	if !strings.HasPrefix(plainToken, tokenPrefix) {
+		return 0, ierrors.ErrInvalidAccessToken
+	}
+
+	token, err := s.accessTokensRepo.AccessTokenByHash(ctx, hashToken(plainToken))
+	if err != nil {
+		return 0, fmt.Errorf("accessTokensRepo.AccessTokenByHash: %w", err)
+	}
+	if token == nil {
+		return 0, ierrors.ErrInvalidAccessToken
+	}
+
+	// время последнего использования только для информации, из-за него запрос не должен падать
+	err = s.accessTokensRepo.Touch(ctx, token.ID, time.Now())
+	if err != nil {
+		log.Ctx(ctx).Error().Int64("token", token.ID).Msgf("accessTokensRepo.Touch: %v", err)
+	}
+
+	return token.UserID, nil
+}
+
+// hashToken у токена 256 бит случайности, поэтому медленный хэш, как для паролей, не нужен
+func hashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
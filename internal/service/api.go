@@ -0,0 +1,6 @@
+package service
+
+type API interface {
+	Start()
+	Stop() error
+}
@@ -0,0 +1,87 @@
+// Package api HTTP API только для чтения над оценками пользователя. Доступ по персональным токенам из /token
+package api
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+//go:embed openapi.yaml
+var openAPIDocument []byte
+
+type svc struct {
+	userSvc         service.User
+	accessTokensSvc service.AccessTokens
+	calendarSvc     service.Calendar
+	gradesFeedSvc   service.GradesFeed
+	server          *http.Server
+	cfg             config.API
+}
+
+func NewService(
+	userSvc service.User,
+	accessTokensSvc service.AccessTokens,
+	calendarSvc service.Calendar,
+	gradesFeedSvc service.GradesFeed,
+	cfg config.API,
+) *svc {
+	s := &svc{
+		userSvc:         userSvc,
+		accessTokensSvc: accessTokensSvc,
+		calendarSvc:     calendarSvc,
+		gradesFeedSvc:   gradesFeedSvc,
+		cfg:             cfg,
+	}
+
+	s.server = &http.Server{
+		Addr:              cfg.Addr,
+		Handler:           s.routes(),
+		ReadHeaderTimeout: cfg.ReadHeaderTimeout,
+		WriteTimeout:      cfg.WriteTimeout,
+	}
+
+	return s
+}
+
+func (s *svc) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.Handle("/openapi.yaml", get(s.handleOpenAPI))
+
+	mux.Handle("/v1/progress-table", get(s.authenticated(s.handleProgressTable)))
+
+	mux.Handle("/v1/history", get(s.authenticated(s.handleHistory)))
+
+	mux.Handle("/v1/changes", get(s.authenticated(s.handleChanges)))
+
+	// календарные приложения и читалки не умеют передавать заголовки, поэтому токен ленты находится в самом адресе
+	mux.Handle("/v1/calendar/", get(s.handleCalendar))
+
+	mux.Handle("/v1/feed/", get(s.handleGradesFeed))
+
+	return mux
+}
+
+func (s *svc) Start() {
+	log.Info().Msgf("api server is listening on %s", s.cfg.Addr)
+	err := s.server.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error().Msgf("server.ListenAndServe: %v", err)
+	}
+}
+
+func (s *svc) Stop() error {
+	err := s.server.Shutdown(context.Background())
+	if err != nil {
+		return fmt.Errorf("server.Shutdown: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package api
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+// gradeKinds значения поля grade.kind, см. openapi.yaml
+var gradeKinds = map[domain.GradeKind]string{
+	domain.GradeKindNotGraded: "not_graded",
+	domain.GradeKindAbsent:    "absent",
+	domain.GradeKindScore:     "score",
+	domain.GradeKindLetter:    "letter",
+	domain.GradeKindPass:      "pass",
+	domain.GradeKindFail:      "fail",
+	domain.GradeKindUnknown:   "unknown",
+}
+
+type errorJSON struct {
+	Error string `json:"error"`
+}
+
+type progressTableJSON struct {
+	Semester    *semesterJSON    `json:"semester,omitempty"`
+	Disciplines []disciplineJSON `json:"disciplines"`
+}
+
+type semesterJSON struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+type disciplineJSON struct {
+	Name          string             `json:"name"`
+	ControlEvents []controlEventJSON `json:"control_events"`
+}
+
+type controlEventJSON struct {
+	Name         string    `json:"name"`
+	Grade        gradeJSON `json:"grade"`
+	Weight       float64   `json:"weight,omitempty"`
+	DeadlineWeek int       `json:"deadline_week,omitempty"`
+	DeadlineDate string    `json:"deadline_date,omitempty"`
+}
+
+type gradeJSON struct {
+	Raw   string   `json:"raw"`
+	Kind  string   `json:"kind"`
+	Score *float64 `json:"score,omitempty"`
+}
+
+type gradeChangesJSON struct {
+	Changes []gradeChangeJSON `json:"changes"`
+}
+
+type gradeChangeJSON struct {
+	ID           int64     `json:"id"`
+	Discipline   string    `json:"discipline"`
+	ControlEvent string    `json:"control_event"`
+	OldGrade     string    `json:"old_grade"`
+	NewGrade     string    `json:"new_grade"`
+	CreatedAt    time.Time `json:"created_at"`
+	Semester     string    `json:"semester,omitempty"`
+	RecordBook   bool      `json:"record_book,omitempty"`
+}
+
+func progressTableFromDomain(progressTable *domain.ProgressTable) *progressTableJSON {
+	result := &progressTableJSON{
+		Disciplines: make([]disciplineJSON, 0, len(progressTable.Disciplines)),
+	}
+	if progressTable.Semester.ID != "" {
+		result.Semester = &semesterJSON{
+			ID:   progressTable.Semester.ID,
+			Name: progressTable.Semester.Name,
+		}
+	}
+
+	for _, discipline := range progressTable.Disciplines {
+		controlEvents := make([]controlEventJSON, 0, len(discipline.ControlEvents))
+		for _, ce := range discipline.ControlEvents {
+			var deadlineDate string
+			if !ce.DeadlineDate.IsZero() {
+				deadlineDate = ce.DeadlineDate.Format(time.DateOnly)
+			}
+
+			controlEvents = append(controlEvents, controlEventJSON{
+				Name:         ce.Name,
+				Grade:        gradeFromDomain(ce.Grade),
+				Weight:       ce.Weight,
+				DeadlineWeek: ce.DeadlineWeek,
+				DeadlineDate: deadlineDate,
+			})
+		}
+
+		result.Disciplines = append(result.Disciplines, disciplineJSON{
+			Name:          discipline.Name,
+			ControlEvents: controlEvents,
+		})
+	}
+
+	return result
+}
+
+func gradeFromDomain(grade domain.Grade) gradeJSON {
+	result := gradeJSON{
+		Raw:  grade.Raw,
+		Kind: gradeKinds[grade.Kind],
+	}
+	if grade.HasScore() {
+		score := grade.Score
+		result.Score = &score
+	}
+
+	return result
+}
+
+func gradeChangesFromDomain(gradesChanges []*domain.GradeChange) []gradeChangeJSON {
+	result := make([]gradeChangeJSON, 0, len(gradesChanges))
+	for _, change := range gradesChanges {
+		result = append(result, gradeChangeJSON{
+			ID:           change.ID,
+			Discipline:   change.Discipline,
+			ControlEvent: change.ControlEvent,
+			OldGrade:     change.OldGrade,
+			NewGrade:     change.NewGrade,
+			CreatedAt:    change.CreatedAt,
+			Semester:     change.Semester,
+			RecordBook:   change.RecordBook,
+		})
+	}
+
+	return result
+}
@@ -0,0 +1,189 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/rs/zerolog/log"
+)
+
+type contextKey int
+
+const contextKeyUserID contextKey = iota
+
+func get(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeError(w, http.StatusMethodNotAllowed, "method not allowed")
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// authenticated пропускает запросы с заголовком "Authorization: Bearer <токен>"
+func (s *svc) authenticated(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		logger := log.With().Str("path", r.URL.Path).Logger()
+		ctx := logger.WithContext(r.Context())
+
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			writeError(w, http.StatusUnauthorized, "missing bearer token")
+			return
+		}
+
+		userID, err := s.accessTokensSvc.Authenticate(ctx, strings.TrimSpace(token))
+		if errors.Is(err, ierrors.ErrInvalidAccessToken) {
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			writeError(w, http.StatusUnauthorized, "invalid token")
+			return
+		}
+		if err != nil {
+			logger.Error().Msgf("accessTokensSvc.Authenticate: %v", err)
+			writeError(w, http.StatusInternalServerError, "internal error")
+			return
+		}
+
+		logger = logger.With().Int64("user", userID).Logger()
+		ctx = logger.WithContext(context.WithValue(ctx, contextKeyUserID, userID))
+		next(w, r.WithContext(ctx))
+	}
+}
+
+func (s *svc) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	w.Header().Set("Content-Type", "application/yaml")
+	_, _ = w.Write(openAPIDocument)
+}
+
+func (s *svc) handleProgressTable(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+	logger := log.Ctx(ctx)
+
+	user, err := s.userSvc.User(ctx, userID(ctx))
+	if err != nil {
+		logger.Error().Msgf("handleProgressTable: userSvc.User: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+	if user == nil || user.BarsCredentials == nil {
+		writeError(w, http.StatusNotFound, "user is not authorized in BARS")
+		return
+	}
+	if user.ProgressTable == nil {
+		writeError(w, http.StatusNotFound, "progress table is not available yet")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, progressTableFromDomain(user.ProgressTable))
+}
+
+func (s *svc) handleHistory(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	history, err := s.userSvc.GradesHistory(ctx, userID(ctx))
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("handleHistory: userSvc.GradesHistory: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &gradeChangesJSON{Changes: gradeChangesFromDomain(history)})
+}
+
+// handleChanges since обязателен: клиент передаёт created_at последнего полученного изменения
+func (s *svc) handleChanges(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	since, err := time.Parse(time.RFC3339Nano, r.URL.Query().Get("since"))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "since must be an RFC 3339 timestamp")
+		return
+	}
+
+	changes, err := s.userSvc.GradesHistorySince(ctx, userID(ctx), since)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("handleChanges: userSvc.GradesHistorySince: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	writeJSON(w, http.StatusOK, &gradeChangesJSON{Changes: gradeChangesFromDomain(changes)})
+}
+
+func (s *svc) handleCalendar(w http.ResponseWriter, r *http.Request) {
+	logger := log.With().Str("path", "/v1/calendar/").Logger()
+	ctx := logger.WithContext(r.Context())
+
+	token, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/calendar/"), ".ics")
+	if !ok || token == "" || strings.Contains(token, "/") {
+		writeError(w, http.StatusNotFound, "calendar feed not found")
+		return
+	}
+
+	feed, err := s.calendarSvc.Feed(ctx, token)
+	if errors.Is(err, ierrors.ErrFeedNotFound) {
+		writeError(w, http.StatusNotFound, "calendar feed not found")
+		return
+	}
+	if err != nil {
+		logger.Error().Msgf("handleCalendar: calendarSvc.Feed: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=900")
+	_, _ = w.Write(feed)
+}
+
+func (s *svc) handleGradesFeed(w http.ResponseWriter, r *http.Request) {
+	logger := log.With().Str("path", "/v1/feed/").Logger()
+	ctx := logger.WithContext(r.Context())
+
+	token, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/feed/"), ".atom")
+	if !ok || token == "" || strings.Contains(token, "/") {
+		writeError(w, http.StatusNotFound, "grades feed not found")
+		return
+	}
+
+	feed, err := s.gradesFeedSvc.Feed(ctx, token)
+	if errors.Is(err, ierrors.ErrFeedNotFound) {
+		writeError(w, http.StatusNotFound, "grades feed not found")
+		return
+	}
+	if err != nil {
+		logger.Error().Msgf("handleGradesFeed: gradesFeedSvc.Feed: %v", err)
+		writeError(w, http.StatusInternalServerError, "internal error")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/atom+xml; charset=utf-8")
+	w.Header().Set("Cache-Control", "private, max-age=300")
+	_, _ = w.Write(feed)
+}
+
+func userID(ctx context.Context) int64 {
+	id, _ := ctx.Value(contextKeyUserID).(int64)
+	return id
+}
+
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Error().Msgf("json.Encode: %v", err)
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, message string) {
+	writeJSON(w, status, &errorJSON{Error: message})
+}
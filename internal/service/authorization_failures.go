@@ -0,0 +1,13 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type AuthorizationFailures interface {
+	Increment(ctx context.Context, userID int64, reason error) (*domain.AuthorizationFailure, error)
+	AuthorizationFailures(ctx context.Context) ([]*domain.AuthorizationFailure, error)
+	Reset(ctx context.Context, userID int64) error
+}
@@ -0,0 +1,59 @@
+package authorization_failures
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+)
+
+type svc struct {
+	authorizationFailuresRepo repository.AuthorizationFailures
+	cfg                       config.Bars
+}
+
+func NewService(
+	authorizationFailuresRepo repository.AuthorizationFailures,
+	cfg config.Bars,
+) *svc {
+	return &svc{
+		authorizationFailuresRepo: authorizationFailuresRepo,
+		cfg:                       cfg,
+	}
+}
+
+func (s *svc) Increment(
+	ctx context.Context,
+	userID int64,
+	reason error,
+) (*domain.AuthorizationFailure, error) {
+	// неудачи старше AuthorizationFailedRetriesTTL не учитываются, счётчик начинается заново
+	expiredBefore := time.Now().Add(-s.cfg.AuthorizationFailedRetriesTTL)
+	failure, err := s.authorizationFailuresRepo.Increment(ctx, userID, reason.Error(), expiredBefore)
+	if err != nil {
+		return nil, fmt.Errorf("authorizationFailuresRepo.Increment: %w", err)
+	}
+
+	return failure, nil
+}
+
+func (s *svc) AuthorizationFailures(ctx context.Context) ([]*domain.AuthorizationFailure, error) {
+	failures, err := s.authorizationFailuresRepo.AuthorizationFailures(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("authorizationFailuresRepo.AuthorizationFailures: %w", err)
+	}
+
+	return failures, nil
+}
+
+func (s *svc) Reset(ctx context.Context, userID int64) error {
+	err := s.authorizationFailuresRepo.Reset(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("authorizationFailuresRepo.Reset: %w", err)
+	}
+
+	return nil
+}
@@ -21,4 +21,16 @@ type Bars interface {
 		password []byte,
 		barsClient bars.Client,
 	) (*domain.ProgressTable, error)
+	// GetRecordBook получает зачётную книжку клиентом, который уже авторизован в БАРС
+	GetRecordBook(ctx context.Context, barsClient bars.Client) (*domain.RecordBook, error)
+	// RecordBook получает зачётную книжку из БАРС по сохранённым данным пользователя
+	RecordBook(ctx context.Context, userID int64) (*domain.RecordBook, error)
+	// Schedule получает расписание занятий из БАРС по сохранённым данным пользователя
+	Schedule(ctx context.Context, userID int64) (*domain.Schedule, error)
+	Semesters(ctx context.Context, userID int64) ([]domain.Semester, error)
+	SemesterProgressTable(
+		ctx context.Context,
+		userID int64,
+		semesterID string,
+	) (*domain.ProgressTable, error)
 }
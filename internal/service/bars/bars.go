@@ -6,8 +6,10 @@ import (
 	"fmt"
 	"net/http"
 	"regexp"
+	"strconv"
 	"strings"
 	"sync"
+	"time"
 	"unicode/utf8"
 
 	"github.com/PuerkitoBio/goquery"
@@ -22,8 +24,9 @@ import (
 // TODO здесь должнен быть пул клиентов, реализация с мьютексом медленная
 // нужно сбрасывать клиента через Clear() после использования перед возвращением в пул
 type svc struct {
-	userSvc service.User
-	cfg     config.Bars
+	userSvc                  service.User
+	authorizationFailuresSvc service.AuthorizationFailures
+	cfg                      config.Bars
 
 	mu         sync.Mutex
 	barsClient bars.Client
@@ -31,12 +34,14 @@ type svc struct {
 
 func NewService(
 	userSvc service.User,
+	authorizationFailuresSvc service.AuthorizationFailures,
 	cfg config.Bars,
 ) *svc {
 	return &svc{
-		userSvc:    userSvc,
-		cfg:        cfg,
-		barsClient: bars.NewClient(config.BARSRegistrationPageURL),
+		userSvc:                  userSvc,
+		authorizationFailuresSvc: authorizationFailuresSvc,
+		cfg:                      cfg,
+		barsClient:               bars.NewClient(config.BARSRegistrationPageURL),
 	}
 }
 
@@ -54,7 +59,7 @@ func (s *svc) Authorization(
 	if err != nil {
 		return fmt.Errorf("barsCredentialsRepo.User: %w", err)
 	}
-	if user != nil && user.BarsCredentials != nil {
+	if user != nil && user.BarsCredentials != nil && !user.BarsCredentials.IsSuspended() {
 		return ierrors.ErrAlreadyAuth
 	}
 
@@ -68,6 +73,13 @@ func (s *svc) Authorization(
 		return fmt.Errorf("aes.Encrypt (password): %w", err)
 	}
 
+	// при повторной авторизации того же аккаунта сохранённая таблица остаётся,
+	// чтобы следующая проверка нашла изменения, пропущенные за время приостановки
+	if user != nil && user.BarsCredentials != nil && user.ProgressTable != nil &&
+		user.BarsCredentials.Username == username {
+		progressTable = nil
+	}
+
 	err = s.userSvc.Save(ctx, &domain.User{
 		ID:            userID,
 		ProgressTable: progressTable,
@@ -80,6 +92,14 @@ func (s *svc) Authorization(
 		return fmt.Errorf("userSvc.Save: %w", err)
 	}
 
+	// приостановка снята, неудачи до неё не должны приближать следующую
+	if user != nil && user.BarsCredentials != nil && user.BarsCredentials.AuthorizationFailures > 0 {
+		err = s.authorizationFailuresSvc.Reset(ctx, userID)
+		if err != nil {
+			return fmt.Errorf("authorizationFailuresSvc.Reset: %w", err)
+		}
+	}
+
 	return nil
 }
 
@@ -121,6 +141,130 @@ func (s *svc) GetProgressTable(
 	return progressTable, nil
 }
 
+// Semesters каждый вызов авторизуется своим клиентом: вызывается из обработчиков бота
+// и не должен ждать общий клиент, занятый другими запросами в БАРС
+func (s *svc) Semesters(ctx context.Context, userID int64) ([]domain.Semester, error) {
+	barsClient := bars.NewClient(config.BARSRegistrationPageURL)
+
+	document, err := s.getUserGradesPageDocument(ctx, barsClient, userID)
+	if err != nil {
+		return nil, fmt.Errorf("svc.getUserGradesPageDocument: %w", err)
+	}
+
+	semesters := extractSemesterSelect(document)
+	if semesters == nil {
+		return nil, ierrors.ErrSemesterNotFound
+	}
+
+	return semesters.semesters, nil
+}
+
+// SemesterProgressTable получает таблицу выбранного семестра из БАРС только для показа, ничего не сохраняя.
+// Таблицы прошлых семестров архивируются при смене семестра в проверке изменений
+func (s *svc) SemesterProgressTable(
+	ctx context.Context,
+	userID int64,
+	semesterID string,
+) (*domain.ProgressTable, error) {
+	barsClient := bars.NewClient(config.BARSRegistrationPageURL)
+
+	document, err := s.getUserGradesPageDocument(ctx, barsClient, userID)
+	if err != nil {
+		return nil, fmt.Errorf("svc.getUserGradesPageDocument: %w", err)
+	}
+
+	semesters := extractSemesterSelect(document)
+	if semesters == nil {
+		return nil, ierrors.ErrSemesterNotFound
+	}
+	if semesters.current.ID == semesterID {
+		progressTable, err := extractProgressTable(document)
+		if err != nil {
+			return nil, fmt.Errorf("extractProgressTable: %w", err)
+		}
+
+		return progressTable, nil
+	}
+
+	isKnownSemester := false
+	for _, semester := range semesters.semesters {
+		if semester.ID == semesterID {
+			isKnownSemester = true
+			break
+		}
+	}
+	if !isKnownSemester {
+		return nil, ierrors.ErrSemesterNotFound
+	}
+
+	document, err = getSemesterPageDocument(ctx, barsClient, semesters.queryKey, semesterID)
+	if err != nil {
+		return nil, fmt.Errorf("getSemesterPageDocument: %w", err)
+	}
+
+	// если БАРС запоминает выбранный семестр не в сессии, а в аккаунте, следующая проверка изменений
+	// увидела бы прошлый семестр и приняла его за смену семестра, поэтому выбор возвращается обратно
+	_, err = getSemesterPageDocument(ctx, barsClient, semesters.queryKey, semesters.current.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getSemesterPageDocument (current): %w", err)
+	}
+
+	progressTable, err := extractProgressTable(document)
+	if err != nil {
+		return nil, fmt.Errorf("extractProgressTable: %w", err)
+	}
+	if progressTable.Semester.ID != semesterID {
+		return nil, ierrors.ErrSemesterNotFound
+	}
+
+	return progressTable, nil
+}
+
+// authorizeUser авторизует barsClient по сохранённым данным пользователя.
+// Пока отслеживание приостановлено, сохранённый пароль скорее всего неверен и в БАРС не отправляется
+func (s *svc) authorizeUser(ctx context.Context, barsClient bars.Client, userID int64) error {
+	user, err := s.userSvc.User(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("userSvc.User: %w", err)
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return ierrors.ErrNotAuth
+	}
+	if user.BarsCredentials.IsSuspended() {
+		return ierrors.ErrCredentialsSuspended
+	}
+
+	decryptedPassword, err := aes.Decrypt([]byte(s.cfg.EncryptionKey), user.BarsCredentials.Password)
+	if err != nil {
+		return fmt.Errorf("aes.Decrypt: %w", err)
+	}
+
+	err = barsClient.Authorization(ctx, user.BarsCredentials.Username, decryptedPassword)
+	if err != nil {
+		return fmt.Errorf("barsClient.Authorization: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) getUserGradesPageDocument(
+	ctx context.Context,
+	barsClient bars.Client,
+	userID int64,
+) (*goquery.Document, error) {
+	err := s.authorizeUser(ctx, barsClient, userID)
+	if err != nil {
+		return nil, fmt.Errorf("svc.authorizeUser: %w", err)
+	}
+
+	document, err := getGradesPageDocument(ctx, barsClient)
+	if err != nil {
+		return nil, fmt.Errorf("getGradesPageDocument: %w", err)
+	}
+
+	return document, nil
+}
+
 func getGradesPageDocument(
 	ctx context.Context,
 	barsClient bars.Client,
@@ -129,6 +273,7 @@ func getGradesPageDocument(
 	if err != nil {
 		return nil, fmt.Errorf("barsClient.MakeRequest: %w", err)
 	}
+	defer response.Body.Close()
 
 	document, err := goquery.NewDocumentFromReader(response.Body)
 	if err != nil {
@@ -164,6 +309,10 @@ func extractProgressTable(document *goquery.Document) (*domain.ProgressTable, er
 		return nil, fmt.Errorf("extractDisciplineNames: %w", err)
 	}
 
+	if semesters := extractSemesterSelect(document); semesters != nil {
+		progressTable.Semester = semesters.current
+	}
+
 	if err := validateProgressTable(progressTable); err != nil {
 		return nil, fmt.Errorf("validateProgressTable: %w", err)
 	}
@@ -211,6 +360,7 @@ func extractDisciplinesData(
 
 	document.Find("tbody").EachWithBreak(func(tbodyId int, tbody *goquery.Selection) bool {
 		trSelection := tbody.Find("tr").FilterFunction(filterTrSelection)
+		columns := extractControlEventColumns(tbody)
 
 		controlEventsCount := trSelection.Length()
 		discipline := domain.Discipline{
@@ -236,13 +386,11 @@ func extractDisciplinesData(
 						processedData = strings.Replace(processedData, " ", "", 1)
 					}
 					controlEvent.Name = processedData
+					extractControlEventDetails(processedData, &controlEvent)
 				case tdSelection.Length() - 1:
-					if isEmptyData(processedData) {
-						processedData = "отсутствует"
-					} else if strings.HasPrefix(processedData, " ") {
-						processedData = strings.Replace(processedData, " ", "", 1)
-					}
-					controlEvent.Grade = processedData
+					controlEvent.Grade = domain.ParseGrade(processedData)
+				default:
+					extractControlEventColumn(processedData, columns[tdId], &controlEvent)
 				}
 
 				return isContinue
@@ -259,13 +407,124 @@ func extractDisciplinesData(
 	return err
 }
 
+const (
+	// maxDeadlineWeek в семестре меньше недель, большее число - не неделя, а, например, год
+	maxDeadlineWeek = 25
+	// maxControlEventWeight вес БАРС показывает долей или процентами
+	maxControlEventWeight = 100
+)
+
+var (
+	// после "вес" не должно быть буквы, иначе подойдёт и "весна 2024"
+	controlEventWeightRegexp      = regexp.MustCompile(`(?i)(?:^|[^\p{L}])вес[^\p{L}\d]{0,3}(\d+(?:[.,]\d+)?)`)
+	controlEventWeightLabelRegexp = regexp.MustCompile(`(?i)(?:^|[^\p{L}])вес(?:[^\p{L}]|$)`)
+	controlEventWeekRegexp        = regexp.MustCompile(`(?i)(?:(?:^|\D)(\d{1,2})\s*нед)|(?:нед\S*\s*(\d{1,2})(?:\D|$))`)
+	controlEventWeekLabelRegexp   = regexp.MustCompile(`(?i)нед`)
+	plainNumberRegexp             = regexp.MustCompile(`^\s*(\d+(?:[.,]\d+)?)\s*$`)
+	controlEventDateRegexp        = regexp.MustCompile(`\b(\d{2}\.\d{2}\.\d{4})\b`)
+)
+
+// controlEventColumn что показывает колонка таблицы дисциплины между названием мероприятия и оценкой
+type controlEventColumn int
+
+const (
+	controlEventColumnUnknown controlEventColumn = iota
+	controlEventColumnWeight
+	controlEventColumnWeek
+)
+
+// extractControlEventColumns определяет колонки по заголовку таблицы. Колонки без заголовка остаются
+// controlEventColumnUnknown: из них берутся только значения с подписью, например "вес 0,2"
+func extractControlEventColumns(tbody *goquery.Selection) map[int]controlEventColumn {
+	columns := make(map[int]controlEventColumn)
+	tbody.Parent().ChildrenFiltered("thead").Find("th").Each(func(i int, th *goquery.Selection) {
+		label := th.Text()
+		switch {
+		case controlEventWeightLabelRegexp.MatchString(label):
+			columns[i] = controlEventColumnWeight
+		case controlEventWeekLabelRegexp.MatchString(label):
+			columns[i] = controlEventColumnWeek
+		}
+	})
+
+	return columns
+}
+
+// extractControlEventColumn число без подписи принимается только из колонки с известным заголовком
+func extractControlEventColumn(data string, column controlEventColumn, controlEvent *domain.ControlEvent) {
+	if match := plainNumberRegexp.FindStringSubmatch(data); match != nil {
+		switch column {
+		case controlEventColumnWeight:
+			if weight, err := parseDecimal(match[1]); err == nil {
+				setControlEventWeight(controlEvent, weight)
+			}
+		case controlEventColumnWeek:
+			if deadlineWeek, err := strconv.Atoi(match[1]); err == nil {
+				setControlEventDeadlineWeek(controlEvent, deadlineWeek)
+			}
+		}
+		return
+	}
+
+	extractControlEventDetails(data, controlEvent)
+}
+
+// extractControlEventDetails вес и срок с подписью в названии мероприятия или в колонке,
+// например "КМ-1 Контрольная работа (вес 0,2, 6 неделя)" или "(до 14.10.2024)"
+func extractControlEventDetails(data string, controlEvent *domain.ControlEvent) {
+	if match := controlEventWeightRegexp.FindStringSubmatch(data); match != nil {
+		if weight, err := parseDecimal(match[1]); err == nil {
+			setControlEventWeight(controlEvent, weight)
+		}
+	}
+
+	if match := controlEventWeekRegexp.FindStringSubmatch(data); match != nil {
+		week := match[1]
+		if week == "" {
+			week = match[2]
+		}
+		if deadlineWeek, err := strconv.Atoi(week); err == nil {
+			setControlEventDeadlineWeek(controlEvent, deadlineWeek)
+		}
+	}
+
+	if match := controlEventDateRegexp.FindStringSubmatch(data); match != nil && controlEvent.DeadlineDate.IsZero() {
+		if deadlineDate, err := time.ParseInLocation("02.01.2006", match[1], config.BARSLocation); err == nil {
+			controlEvent.DeadlineDate = deadlineDate
+		}
+	}
+}
+
+func setControlEventWeight(controlEvent *domain.ControlEvent, weight float64) {
+	if weight > 0 && weight <= maxControlEventWeight {
+		controlEvent.Weight = weight
+	}
+}
+
+func setControlEventDeadlineWeek(controlEvent *domain.ControlEvent, deadlineWeek int) {
+	if deadlineWeek >= 1 && deadlineWeek <= maxDeadlineWeek {
+		controlEvent.DeadlineWeek = deadlineWeek
+	}
+}
+
+func parseDecimal(data string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(data, ",", ".", 1), 64)
+}
+
 func isEmptyData(data string) bool {
 	return data == "" || data == " "
 }
 
 func validateProgressTable(pt *domain.ProgressTable) error {
-	if !utf8.ValidString(pt.String()) {
-		return errors.New("progress table contains not utf8 characters")
+	for _, discipline := range pt.Disciplines {
+		if !utf8.ValidString(discipline.Name) {
+			return errors.New("progress table contains not utf8 characters")
+		}
+		for _, ce := range discipline.ControlEvents {
+			if !utf8.ValidString(ce.Name) || !utf8.ValidString(ce.Grade.Raw) {
+				return errors.New("progress table contains not utf8 characters")
+			}
+		}
 	}
 
 	return nil
@@ -0,0 +1,261 @@
+package bars
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/authorization_failures"
+)
+
+func TestExtractProgressTableControlEventDetails(t *testing.T) {
+	file, err := os.Open("testdata/grades_page.html")
+	if err != nil {
+		t.Fatalf("os.Open: %v", err)
+	}
+	defer file.Close()
+
+	document, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatalf("goquery.NewDocumentFromReader: %v", err)
+	}
+
+	progressTable, err := extractProgressTable(document)
+	if err != nil {
+		t.Fatalf("extractProgressTable: %v", err)
+	}
+
+	want := []domain.Discipline{
+		{
+			Name: "Математический анализ",
+			ControlEvents: []domain.ControlEvent{
+				{Name: "КМ-1 Контрольная работа", Weight: 0.2, DeadlineWeek: 6},
+				{Name: "КМ-2 Типовой расчёт", Weight: 0.3, DeadlineWeek: 12},
+				// неделя и вес вне допустимых границ отбрасываются
+				{
+					Name:         "КМ-3 Коллоквиум (до 14.05.2024)",
+					DeadlineDate: time.Date(2024, time.May, 14, 0, 0, 0, 0, config.BARSLocation),
+				},
+				{Name: "Балл текущего контроля"},
+			},
+		},
+		{
+			Name: "Учебная практика (весна 2024)",
+			ControlEvents: []domain.ControlEvent{
+				// колонки без заголовка: числа без подписи не считаются ни неделей, ни весом
+				{Name: "КМ-1 Отчёт по практике (вес 0,5, 8 неделя)", Weight: 0.5, DeadlineWeek: 8},
+				{Name: "КМ-2 Защита отчёта, весна 2024", Weight: 0.5, DeadlineWeek: 16},
+				{Name: "Балл текущего контроля"},
+			},
+		},
+	}
+
+	if len(progressTable.Disciplines) != len(want) {
+		t.Fatalf("got %d disciplines, want %d", len(progressTable.Disciplines), len(want))
+	}
+	for i, discipline := range progressTable.Disciplines {
+		if discipline.Name != want[i].Name {
+			t.Errorf("discipline %d: name %q, want %q", i, discipline.Name, want[i].Name)
+		}
+		if len(discipline.ControlEvents) != len(want[i].ControlEvents) {
+			t.Fatalf("discipline %q: got %d control events, want %d",
+				discipline.Name, len(discipline.ControlEvents), len(want[i].ControlEvents))
+		}
+		for j, ce := range discipline.ControlEvents {
+			wantCE := want[i].ControlEvents[j]
+			if ce.Name != wantCE.Name ||
+				ce.Weight != wantCE.Weight ||
+				ce.DeadlineWeek != wantCE.DeadlineWeek ||
+				!ce.DeadlineDate.Equal(wantCE.DeadlineDate) {
+				t.Errorf("control event %q: got weight %v, week %d, date %v; want weight %v, week %d, date %v",
+					ce.Name, ce.Weight, ce.DeadlineWeek, ce.DeadlineDate,
+					wantCE.Weight, wantCE.DeadlineWeek, wantCE.DeadlineDate)
+			}
+		}
+	}
+
+	if progressTable.Semester.ID != "28" {
+		t.Errorf("semester %q, want %q", progressTable.Semester.ID, "28")
+	}
+}
+
+func TestExtractControlEventDetails(t *testing.T) {
+	tests := []struct {
+		data         string
+		weight       float64
+		deadlineWeek int
+	}{
+		{data: "КМ-1 Контрольная работа (вес 0,2, 6 неделя)", weight: 0.2, deadlineWeek: 6},
+		{data: "Вес: 15", weight: 15},
+		{data: "вес0.25", weight: 0.25},
+		{data: "неделя 9", deadlineWeek: 9},
+		{data: "Практика, весна 2024"},
+		{data: "Отчёт (весенний семестр 2024)"},
+		{data: "вес 250"},
+		{data: "42 неделя"},
+		{data: "2024 неделя"},
+		{data: "6"},
+		{data: "0,2"},
+	}
+
+	for _, tt := range tests {
+		controlEvent := domain.ControlEvent{}
+		extractControlEventDetails(tt.data, &controlEvent)
+		if controlEvent.Weight != tt.weight || controlEvent.DeadlineWeek != tt.deadlineWeek {
+			t.Errorf("%q: got weight %v, week %d; want weight %v, week %d",
+				tt.data, controlEvent.Weight, controlEvent.DeadlineWeek, tt.weight, tt.deadlineWeek)
+		}
+	}
+}
+
+// testdataClient отдаёт сохранённую страницу оценок на любой запрос
+type testdataClient struct{}
+
+func (testdataClient) Authorization(context.Context, string, string) error {
+	return nil
+}
+
+func (testdataClient) MakeRequest(context.Context, string, string, io.Reader) (*http.Response, error) {
+	file, err := os.Open("testdata/grades_page.html")
+	if err != nil {
+		return nil, err
+	}
+
+	return &http.Response{StatusCode: http.StatusOK, Body: file}, nil
+}
+
+func (testdataClient) Clear() {}
+
+// memoryAuthorizationFailures повторяет контракт repository.AuthorizationFailures
+type memoryAuthorizationFailures struct {
+	failures map[int64]*domain.AuthorizationFailure
+}
+
+func (r *memoryAuthorizationFailures) Increment(
+	_ context.Context,
+	userID int64,
+	lastError string,
+	expiredBefore time.Time,
+) (*domain.AuthorizationFailure, error) {
+	now := time.Now()
+	failure, ok := r.failures[userID]
+	if !ok || failure.LastFailureAt.Before(expiredBefore) {
+		failure = &domain.AuthorizationFailure{UserID: userID, FirstFailureAt: now}
+		r.failures[userID] = failure
+	}
+	failure.Count++
+	failure.LastError = lastError
+	failure.LastFailureAt = now
+
+	saved := *failure
+	return &saved, nil
+}
+
+func (r *memoryAuthorizationFailures) AuthorizationFailures(context.Context) ([]*domain.AuthorizationFailure, error) {
+	return nil, errors.New("not implemented")
+}
+
+func (r *memoryAuthorizationFailures) Reset(_ context.Context, userID int64) error {
+	delete(r.failures, userID)
+	return nil
+}
+
+// userService встраивается под другим именем, иначе поле конфликтует с методом User
+type userService = service.User
+
+// memoryUsers хранит одного пользователя, счётчик неудач подтягивается из failures, как JOIN в репозитории
+type memoryUsers struct {
+	userService
+	stored   *domain.User
+	failures *memoryAuthorizationFailures
+}
+
+func (s *memoryUsers) User(_ context.Context, userID int64) (*domain.User, error) {
+	if s.stored == nil || s.stored.ID != userID {
+		return nil, nil
+	}
+
+	user := *s.stored
+	if user.BarsCredentials != nil {
+		credentials := *user.BarsCredentials
+		credentials.AuthorizationFailures = 0
+		if failure, ok := s.failures.failures[userID]; ok {
+			credentials.AuthorizationFailures = failure.Count
+		}
+		user.BarsCredentials = &credentials
+	}
+
+	return &user, nil
+}
+
+func (s *memoryUsers) Save(_ context.Context, user *domain.User) error {
+	saved := *user
+	if saved.ProgressTable == nil && s.stored != nil {
+		saved.ProgressTable = s.stored.ProgressTable
+	}
+	s.stored = &saved
+
+	return nil
+}
+
+func (s *memoryUsers) Suspend(_ context.Context, userID int64) error {
+	suspendedAt := time.Now()
+	s.stored.BarsCredentials.SuspendedAt = &suspendedAt
+	return nil
+}
+
+func TestAuthorizationAfterSuspensionResetsFailures(t *testing.T) {
+	const userID = 1
+
+	ctx := context.Background()
+	cfg := config.Bars{
+		AuthorizationFailedRetriesCount: 3,
+		AuthorizationFailedRetriesTTL:   time.Hour,
+		EncryptionKey:                   "0123456789abcdef0123456789abcdef",
+	}
+	failuresRepo := &memoryAuthorizationFailures{failures: make(map[int64]*domain.AuthorizationFailure)}
+	failuresSvc := authorization_failures.NewService(failuresRepo, cfg)
+	userSvc := &memoryUsers{
+		stored: &domain.User{
+			ID:              userID,
+			BarsCredentials: &domain.BarsCredentials{Username: "student"},
+			ProgressTable:   &domain.ProgressTable{},
+		},
+		failures: failuresRepo,
+	}
+	s := NewService(userSvc, failuresSvc, cfg)
+	s.barsClient = testdataClient{}
+
+	// проверки исчерпали попытки, отслеживание приостановлено
+	for i := 0; i < cfg.AuthorizationFailedRetriesCount; i++ {
+		if _, err := failuresSvc.Increment(ctx, userID, errors.New("authorization failed")); err != nil {
+			t.Fatalf("Increment: %v", err)
+		}
+	}
+	if err := userSvc.Suspend(ctx, userID); err != nil {
+		t.Fatalf("Suspend: %v", err)
+	}
+
+	if err := s.Authorization(ctx, userID, "student", []byte("password")); err != nil {
+		t.Fatalf("Authorization: %v", err)
+	}
+	if userSvc.stored.BarsCredentials.IsSuspended() {
+		t.Fatal("user is still suspended after re-authorization")
+	}
+
+	failure, err := failuresSvc.Increment(ctx, userID, errors.New("authorization failed"))
+	if err != nil {
+		t.Fatalf("Increment: %v", err)
+	}
+	if failure.Count != 1 {
+		t.Errorf("got %d failures after re-authorization and one failed check, want 1", failure.Count)
+	}
+}
@@ -0,0 +1,185 @@
+package bars
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/aes"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/bars"
+)
+
+func (s *svc) GetRecordBook(ctx context.Context, barsClient bars.Client) (*domain.RecordBook, error) {
+	document, err := getRecordBookPageDocument(ctx, barsClient)
+	if err != nil {
+		return nil, fmt.Errorf("getRecordBookPageDocument: %w", err)
+	}
+
+	recordBook, err := extractRecordBook(document)
+	if err != nil {
+		return nil, fmt.Errorf("extractRecordBook: %w", err)
+	}
+
+	return recordBook, nil
+}
+
+func (s *svc) RecordBook(ctx context.Context, userID int64) (*domain.RecordBook, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.barsClient.Clear()
+
+	user, err := s.userSvc.User(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.User: %w", err)
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return nil, ierrors.ErrNotAuth
+	}
+
+	decryptedPassword, err := aes.Decrypt([]byte(s.cfg.EncryptionKey), user.BarsCredentials.Password)
+	if err != nil {
+		return nil, fmt.Errorf("aes.Decrypt: %w", err)
+	}
+
+	err = s.barsClient.Authorization(ctx, user.BarsCredentials.Username, decryptedPassword)
+	if err != nil {
+		return nil, fmt.Errorf("barsClient.Authorization: %w", err)
+	}
+
+	recordBook, err := s.GetRecordBook(ctx, s.barsClient)
+	if err != nil {
+		return nil, fmt.Errorf("svc.GetRecordBook: %w", err)
+	}
+
+	return recordBook, nil
+}
+
+func getRecordBookPageDocument(
+	ctx context.Context,
+	barsClient bars.Client,
+) (*goquery.Document, error) {
+	response, err := barsClient.MakeRequest(ctx, http.MethodGet, config.BARSRecordBookPageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("barsClient.MakeRequest: %w", err)
+	}
+	defer response.Body.Close()
+
+	document, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
+	}
+
+	return document, nil
+}
+
+// recordBookColumns номера колонок таблицы зачётной книжки, -1 если колонки нет
+type recordBookColumns struct {
+	discipline  int
+	semester    int
+	controlForm int
+	mark        int
+	date        int
+	teacher     int
+}
+
+// extractRecordBookColumns порядок колонок определяется по заголовкам, а не по позициям,
+// чтобы перестановка колонок в БАРС не ломала разбор
+func extractRecordBookColumns(table *goquery.Selection) recordBookColumns {
+	columns := recordBookColumns{-1, -1, -1, -1, -1, -1}
+	table.Find("tr").First().Find("th, td").Each(func(i int, cell *goquery.Selection) {
+		header := strings.ToLower(normalizeText(cell.Text()))
+		switch {
+		case strings.Contains(header, "дисциплин"):
+			columns.discipline = i
+		case strings.Contains(header, "семестр"):
+			columns.semester = i
+		case strings.Contains(header, "форма") || strings.Contains(header, "контрол"):
+			columns.controlForm = i
+		case strings.Contains(header, "оценк"):
+			columns.mark = i
+		case strings.Contains(header, "дата"):
+			columns.date = i
+		case strings.Contains(header, "преподавател"):
+			columns.teacher = i
+		}
+	})
+
+	return columns
+}
+
+func extractRecordBook(document *goquery.Document) (*domain.RecordBook, error) {
+	recordBook := &domain.RecordBook{
+		Entries: make([]domain.RecordBookEntry, 0),
+	}
+
+	isRecordBookPage := false
+	document.Find("table").Each(func(_ int, table *goquery.Selection) {
+		columns := extractRecordBookColumns(table)
+		if columns.discipline == -1 || columns.mark == -1 {
+			return
+		}
+		isRecordBookPage = true
+
+		// если колонки семестра нет, таблицы разбиты по семестрам с заголовком перед таблицей
+		tableSemester := ""
+		if columns.semester == -1 {
+			tableSemester = extractTableSemester(table)
+		}
+
+		table.Find("tr").Slice(1, goquery.ToEnd).Each(func(_ int, tr *goquery.Selection) {
+			cells := tr.Find("td")
+			cellText := func(column int) string {
+				if column == -1 || column >= cells.Length() {
+					return ""
+				}
+				return normalizeText(cells.Eq(column).Text())
+			}
+
+			entry := domain.RecordBookEntry{
+				Discipline:  cellText(columns.discipline),
+				Semester:    cellText(columns.semester),
+				ControlForm: cellText(columns.controlForm),
+				Mark:        cellText(columns.mark),
+				Date:        cellText(columns.date),
+				Teacher:     cellText(columns.teacher),
+			}
+			if entry.Discipline == "" {
+				return
+			}
+			if entry.Semester == "" {
+				entry.Semester = tableSemester
+			}
+
+			recordBook.Entries = append(recordBook.Entries, entry)
+		})
+	})
+
+	if !isRecordBookPage {
+		return nil, ierrors.ErrWrongRecordBookPage
+	}
+
+	return recordBook, nil
+}
+
+func extractTableSemester(table *goquery.Selection) string {
+	semester := ""
+	table.PrevAll().EachWithBreak(func(_ int, s *goquery.Selection) bool {
+		text := normalizeText(s.Text())
+		if strings.Contains(strings.ToLower(text), "семестр") {
+			semester = text
+			return false
+		}
+		return true
+	})
+
+	return semester
+}
+
+func normalizeText(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,99 @@
+package bars
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+)
+
+func readTestdataDocument(t *testing.T, name string) *goquery.Document {
+	t.Helper()
+
+	file, err := os.Open("testdata/" + name)
+	if err != nil {
+		t.Fatalf("os.Open: %v", err)
+	}
+	defer file.Close()
+
+	document, err := goquery.NewDocumentFromReader(file)
+	if err != nil {
+		t.Fatalf("goquery.NewDocumentFromReader: %v", err)
+	}
+
+	return document
+}
+
+func TestExtractRecordBook(t *testing.T) {
+	tests := []struct {
+		page string
+		want []domain.RecordBookEntry
+	}{
+		{
+			// таблицы по семестрам с заголовком перед таблицей, колонки могут идти в любом порядке
+			page: "record_book_page.html",
+			want: []domain.RecordBookEntry{
+				{
+					Discipline:  "Математический анализ",
+					Semester:    "1 семестр",
+					ControlForm: "Экзамен",
+					Mark:        "отлично",
+					Date:        "18.01.2024",
+					Teacher:     "Иванов И. И.",
+				},
+				{
+					Discipline:  "Физическая культура",
+					Semester:    "1 семестр",
+					ControlForm: "Зачёт",
+					Mark:        "зачтено",
+					Date:        "25.12.2023",
+				},
+				{Discipline: "Линейная алгебра", Semester: "2 семестр", ControlForm: "Экзамен"},
+			},
+		},
+		{
+			page: "record_book_semester_column_page.html",
+			want: []domain.RecordBookEntry{
+				{Discipline: "Базы данных", Semester: "3 семестр", ControlForm: "Курсовая работа", Mark: "хорошо"},
+				{
+					Discipline:  "Операционные системы",
+					Semester:    "4 семестр",
+					ControlForm: "Зачёт с оценкой",
+					Mark:        "удовлетворительно",
+				},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		recordBook, err := extractRecordBook(readTestdataDocument(t, tt.page))
+		if err != nil {
+			t.Fatalf("%s: extractRecordBook: %v", tt.page, err)
+		}
+
+		if len(recordBook.Entries) != len(tt.want) {
+			t.Fatalf("%s: got %d entries, want %d", tt.page, len(recordBook.Entries), len(tt.want))
+		}
+		for i, entry := range recordBook.Entries {
+			if entry != tt.want[i] {
+				t.Errorf("%s: entry %d: got %+v, want %+v", tt.page, i, entry, tt.want[i])
+			}
+		}
+	}
+}
+
+func TestExtractRecordBookWrongPage(t *testing.T) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader("<html><body><form></form></body></html>"))
+	if err != nil {
+		t.Fatalf("goquery.NewDocumentFromReader: %v", err)
+	}
+
+	_, err = extractRecordBook(document)
+	if !errors.Is(err, ierrors.ErrWrongRecordBookPage) {
+		t.Errorf("got error %v, want %v", err, ierrors.ErrWrongRecordBookPage)
+	}
+}
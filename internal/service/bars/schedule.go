@@ -0,0 +1,150 @@
+package bars
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+)
+
+var lessonDayRegexp = regexp.MustCompile(`\d{2}\.\d{2}\.\d{4}`)
+
+func (s *svc) Schedule(ctx context.Context, userID int64) (*domain.Schedule, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	defer s.barsClient.Clear()
+
+	err := s.authorizeUser(ctx, s.barsClient, userID)
+	if err != nil {
+		return nil, fmt.Errorf("svc.authorizeUser: %w", err)
+	}
+
+	response, err := s.barsClient.MakeRequest(ctx, http.MethodGet, config.BARSSchedulePageURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("barsClient.MakeRequest: %w", err)
+	}
+	defer response.Body.Close()
+
+	document, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
+	}
+
+	schedule, err := extractSchedule(document)
+	if err != nil {
+		return nil, fmt.Errorf("extractSchedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+type scheduleColumns struct {
+	day        int
+	timeSlot   int
+	discipline int
+	room       int
+	teacher    int
+	lessonType int
+}
+
+func extractScheduleColumns(table *goquery.Selection) scheduleColumns {
+	columns := scheduleColumns{-1, -1, -1, -1, -1, -1}
+	table.Find("tr").First().Find("th, td").Each(func(i int, cell *goquery.Selection) {
+		header := strings.ToLower(normalizeText(cell.Text()))
+		switch {
+		case strings.Contains(header, "дата") || strings.Contains(header, "день"):
+			columns.day = i
+		case strings.Contains(header, "время"):
+			columns.timeSlot = i
+		case strings.Contains(header, "дисциплин") || strings.Contains(header, "предмет"):
+			columns.discipline = i
+		case strings.Contains(header, "аудитор"):
+			columns.room = i
+		case strings.Contains(header, "преподавател"):
+			columns.teacher = i
+		case strings.Contains(header, "вид") || strings.Contains(header, "тип"):
+			columns.lessonType = i
+		}
+	})
+
+	return columns
+}
+
+// extractSchedule в таблице дата указывается либо в колонке, либо отдельной строкой перед занятиями дня
+func extractSchedule(document *goquery.Document) (*domain.Schedule, error) {
+	schedule := &domain.Schedule{
+		Lessons:   make([]domain.Lesson, 0),
+		UpdatedAt: time.Now(),
+	}
+
+	isSchedulePage := false
+	document.Find("table").Each(func(_ int, table *goquery.Selection) {
+		columns := extractScheduleColumns(table)
+		if columns.discipline == -1 || columns.timeSlot == -1 {
+			return
+		}
+		isSchedulePage = true
+
+		var currentDay time.Time
+		table.Find("tr").Slice(1, goquery.ToEnd).Each(func(_ int, tr *goquery.Selection) {
+			cells := tr.Find("td")
+			cellText := func(column int) string {
+				if column == -1 || column >= cells.Length() {
+					return ""
+				}
+				return normalizeText(cells.Eq(column).Text())
+			}
+
+			if cells.Length() == 1 {
+				if day, ok := parseLessonDay(cellText(0)); ok {
+					currentDay = day
+				}
+				return
+			}
+			if day, ok := parseLessonDay(cellText(columns.day)); ok {
+				currentDay = day
+			}
+
+			lesson := domain.Lesson{
+				Day:        currentDay,
+				TimeSlot:   cellText(columns.timeSlot),
+				Discipline: cellText(columns.discipline),
+				Room:       cellText(columns.room),
+				Teacher:    cellText(columns.teacher),
+				LessonType: cellText(columns.lessonType),
+			}
+			if lesson.Discipline == "" || lesson.Day.IsZero() {
+				return
+			}
+
+			schedule.Lessons = append(schedule.Lessons, lesson)
+		})
+	})
+
+	if !isSchedulePage {
+		return nil, ierrors.ErrWrongSchedulePage
+	}
+
+	return schedule, nil
+}
+
+func parseLessonDay(text string) (time.Time, bool) {
+	match := lessonDayRegexp.FindString(text)
+	if match == "" {
+		return time.Time{}, false
+	}
+
+	day, err := time.ParseInLocation("02.01.2006", match, config.BARSLocation)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return day, true
+}
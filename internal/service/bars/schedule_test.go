@@ -0,0 +1,98 @@
+package bars
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+)
+
+func TestExtractSchedule(t *testing.T) {
+	day := func(month time.Month, d int) time.Time {
+		return time.Date(2024, month, d, 0, 0, 0, 0, config.BARSLocation)
+	}
+
+	tests := []struct {
+		page string
+		want []domain.Lesson
+	}{
+		{
+			// дата дня отдельной строкой перед занятиями
+			page: "schedule_page.html",
+			want: []domain.Lesson{
+				{
+					Day:        day(time.October, 14),
+					TimeSlot:   "09:20-10:55",
+					Discipline: "Математический анализ",
+					Room:       "Б-114",
+					Teacher:    "Иванов И. И.",
+					LessonType: "Лекция",
+				},
+				{
+					Day:        day(time.October, 14),
+					TimeSlot:   "11:10-12:45",
+					Discipline: "Линейная алгебра",
+					Room:       "Ж-120",
+					LessonType: "Практическое занятие",
+				},
+				{
+					Day:        day(time.October, 15),
+					TimeSlot:   "13:45-15:20",
+					Discipline: "Физическая культура",
+					Room:       "Спортзал",
+					Teacher:    "Петров П. П.",
+				},
+			},
+		},
+		{
+			// дата в колонке, пустая ячейка продолжает предыдущий день
+			page: "schedule_day_column_page.html",
+			want: []domain.Lesson{
+				{Day: day(time.October, 16), TimeSlot: "09:20-10:55", Discipline: "Базы данных"},
+				{Day: day(time.October, 16), TimeSlot: "11:10-12:45", Discipline: "Операционные системы"},
+				{Day: day(time.October, 18), TimeSlot: "13:45-15:20", Discipline: "Сети"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		schedule, err := extractSchedule(readTestdataDocument(t, tt.page))
+		if err != nil {
+			t.Fatalf("%s: extractSchedule: %v", tt.page, err)
+		}
+
+		if len(schedule.Lessons) != len(tt.want) {
+			t.Fatalf("%s: got %d lessons, want %d", tt.page, len(schedule.Lessons), len(tt.want))
+		}
+		for i, lesson := range schedule.Lessons {
+			want := tt.want[i]
+			if !lesson.Day.Equal(want.Day) ||
+				lesson.TimeSlot != want.TimeSlot ||
+				lesson.Discipline != want.Discipline ||
+				lesson.Room != want.Room ||
+				lesson.Teacher != want.Teacher ||
+				lesson.LessonType != want.LessonType {
+				t.Errorf("%s: lesson %d: got %+v, want %+v", tt.page, i, lesson, want)
+			}
+		}
+	}
+}
+
+func TestExtractScheduleWrongPage(t *testing.T) {
+	document, err := goquery.NewDocumentFromReader(strings.NewReader(
+		"<table><tr><th>Дисциплина</th><th>Оценка</th></tr></table>",
+	))
+	if err != nil {
+		t.Fatalf("goquery.NewDocumentFromReader: %v", err)
+	}
+
+	_, err = extractSchedule(document)
+	if !errors.Is(err, ierrors.ErrWrongSchedulePage) {
+		t.Errorf("got error %v, want %v", err, ierrors.ErrWrongSchedulePage)
+	}
+}
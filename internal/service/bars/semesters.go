@@ -0,0 +1,97 @@
+package bars
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/bars"
+)
+
+// semesterSelect выпадающий список семестров на странице оценок.
+// БАРС переключает семестр отправкой формы, поэтому имя списка является параметром запроса
+type semesterSelect struct {
+	queryKey  string
+	current   domain.Semester
+	semesters []domain.Semester
+}
+
+func extractSemesterSelect(document *goquery.Document) *semesterSelect {
+	selection := document.Find("select").FilterFunction(func(_ int, s *goquery.Selection) bool {
+		name, _ := s.Attr("name")
+		id, _ := s.Attr("id")
+		return strings.Contains(strings.ToLower(name+id), "sem")
+	}).First()
+	if selection.Length() == 0 {
+		return nil
+	}
+
+	queryKey, ok := selection.Attr("name")
+	if !ok || queryKey == "" {
+		return nil
+	}
+
+	result := &semesterSelect{
+		queryKey:  queryKey,
+		semesters: make([]domain.Semester, 0, selection.Find("option").Length()),
+	}
+	selection.Find("option").Each(func(i int, option *goquery.Selection) {
+		id, ok := option.Attr("value")
+		if !ok || id == "" {
+			return
+		}
+
+		semester := domain.Semester{
+			ID:   id,
+			Name: normalizeText(option.Text()),
+		}
+		result.semesters = append(result.semesters, semester)
+
+		if _, selected := option.Attr("selected"); selected {
+			result.current = semester
+		}
+	})
+
+	// без явно выбранного варианта браузер показывает первый
+	if result.current.ID == "" && len(result.semesters) != 0 {
+		result.current = result.semesters[0]
+	}
+
+	return result
+}
+
+func getSemesterPageDocument(
+	ctx context.Context,
+	barsClient bars.Client,
+	queryKey string,
+	semesterID string,
+) (*goquery.Document, error) {
+	semesterURL := fmt.Sprintf(
+		"%s?%s",
+		config.BARSGradesPageURL,
+		url.Values{queryKey: {semesterID}}.Encode(),
+	)
+
+	response, err := barsClient.MakeRequest(ctx, http.MethodGet, semesterURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("barsClient.MakeRequest: %w", err)
+	}
+	defer response.Body.Close()
+
+	document, err := goquery.NewDocumentFromReader(response.Body)
+	if err != nil {
+		return nil, fmt.Errorf("goquery.NewDocumentFromReader: %w", err)
+	}
+
+	if !isGradePage(document) {
+		return nil, ierrors.ErrWrongGradesPage
+	}
+
+	return document, nil
+}
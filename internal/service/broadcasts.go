@@ -0,0 +1,28 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Broadcasts interface {
+	// Create создаёт активную рассылку, её отправит BroadcastsSender
+	Create(ctx context.Context, adminID int64, text string, filter domain.BroadcastFilter) (*domain.Broadcast, error)
+	// Broadcast возвращает ErrBroadcastNotFound, если рассылки нет
+	Broadcast(ctx context.Context, broadcastID int64) (*domain.Broadcast, error)
+	// Status возвращает ErrBroadcastNotFound, если рассылки нет
+	Status(ctx context.Context, broadcastID int64) (domain.BroadcastStatus, error)
+	// Broadcasts последние рассылки, новые первыми
+	Broadcasts(ctx context.Context) ([]*domain.Broadcast, error)
+	// Pause, Resume и Cancel возвращают ErrBroadcastStatusConflict, если рассылка не в подходящем статусе
+	Pause(ctx context.Context, broadcastID int64) (*domain.Broadcast, error)
+	Resume(ctx context.Context, broadcastID int64) (*domain.Broadcast, error)
+	Cancel(ctx context.Context, broadcastID int64) (*domain.Broadcast, error)
+	// ActiveBroadcasts рассылки, которые нужно отправлять, в порядке создания
+	ActiveBroadcasts(ctx context.Context) ([]*domain.Broadcast, error)
+	PendingRecipients(ctx context.Context, broadcastID int64, limit int) ([]int64, error)
+	SaveRecipientResult(ctx context.Context, recipient *domain.BroadcastRecipient) error
+	// Complete завершает активную рассылку. false - рассылку успели приостановить или отменить
+	Complete(ctx context.Context, broadcastID int64) (bool, error)
+}
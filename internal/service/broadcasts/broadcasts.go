@@ -0,0 +1,159 @@
+package broadcasts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+)
+
+// recentBroadcastsLimit сколько последних рассылок показывается администратору
+const recentBroadcastsLimit = 10
+
+type svc struct {
+	broadcastsRepo repository.Broadcasts
+}
+
+func NewService(broadcastsRepo repository.Broadcasts) *svc {
+	return &svc{
+		broadcastsRepo: broadcastsRepo,
+	}
+}
+
+func (s *svc) Create(
+	ctx context.Context,
+	adminID int64,
+	text string,
+	filter domain.BroadcastFilter,
+) (*domain.Broadcast, error) {
+	broadcastID, err := s.broadcastsRepo.Create(ctx, &domain.Broadcast{
+		AdminID:   adminID,
+		Text:      text,
+		Filter:    filter,
+		Status:    domain.BroadcastStatusActive,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("broadcastsRepo.Create: %w", err)
+	}
+
+	return s.Broadcast(ctx, broadcastID)
+}
+
+func (s *svc) Broadcast(ctx context.Context, broadcastID int64) (*domain.Broadcast, error) {
+	broadcast, err := s.broadcastsRepo.Broadcast(ctx, broadcastID)
+	if err != nil {
+		return nil, fmt.Errorf("broadcastsRepo.Broadcast: %w", err)
+	}
+	if broadcast == nil {
+		return nil, ierrors.ErrBroadcastNotFound
+	}
+
+	return broadcast, nil
+}
+
+func (s *svc) Status(ctx context.Context, broadcastID int64) (domain.BroadcastStatus, error) {
+	status, err := s.broadcastsRepo.Status(ctx, broadcastID)
+	if err != nil {
+		return "", fmt.Errorf("broadcastsRepo.Status: %w", err)
+	}
+	if status == "" {
+		return "", ierrors.ErrBroadcastNotFound
+	}
+
+	return status, nil
+}
+
+func (s *svc) Broadcasts(ctx context.Context) ([]*domain.Broadcast, error) {
+	broadcasts, err := s.broadcastsRepo.Broadcasts(ctx, recentBroadcastsLimit)
+	if err != nil {
+		return nil, fmt.Errorf("broadcastsRepo.Broadcasts: %w", err)
+	}
+
+	return broadcasts, nil
+}
+
+func (s *svc) Pause(ctx context.Context, broadcastID int64) (*domain.Broadcast, error) {
+	return s.updateStatus(ctx, broadcastID, domain.BroadcastStatusPaused, domain.BroadcastStatusActive)
+}
+
+func (s *svc) Resume(ctx context.Context, broadcastID int64) (*domain.Broadcast, error) {
+	return s.updateStatus(ctx, broadcastID, domain.BroadcastStatusActive, domain.BroadcastStatusPaused)
+}
+
+func (s *svc) Cancel(ctx context.Context, broadcastID int64) (*domain.Broadcast, error) {
+	return s.updateStatus(
+		ctx,
+		broadcastID,
+		domain.BroadcastStatusCancelled,
+		domain.BroadcastStatusActive,
+		domain.BroadcastStatusPaused,
+	)
+}
+
+func (s *svc) updateStatus(
+	ctx context.Context,
+	broadcastID int64,
+	status domain.BroadcastStatus,
+	from ...domain.BroadcastStatus,
+) (*domain.Broadcast, error) {
+	updated, err := s.broadcastsRepo.UpdateStatus(ctx, broadcastID, status, from, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("broadcastsRepo.UpdateStatus: %w", err)
+	}
+
+	broadcast, err := s.Broadcast(ctx, broadcastID)
+	if err != nil {
+		return nil, err
+	}
+	if !updated {
+		return broadcast, ierrors.ErrBroadcastStatusConflict
+	}
+
+	return broadcast, nil
+}
+
+func (s *svc) ActiveBroadcasts(ctx context.Context) ([]*domain.Broadcast, error) {
+	broadcasts, err := s.broadcastsRepo.BroadcastsByStatus(ctx, domain.BroadcastStatusActive)
+	if err != nil {
+		return nil, fmt.Errorf("broadcastsRepo.BroadcastsByStatus: %w", err)
+	}
+
+	return broadcasts, nil
+}
+
+func (s *svc) PendingRecipients(ctx context.Context, broadcastID int64, limit int) ([]int64, error) {
+	userIDs, err := s.broadcastsRepo.PendingRecipients(ctx, broadcastID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("broadcastsRepo.PendingRecipients: %w", err)
+	}
+
+	return userIDs, nil
+}
+
+func (s *svc) SaveRecipientResult(ctx context.Context, recipient *domain.BroadcastRecipient) error {
+	err := s.broadcastsRepo.SaveRecipientResult(ctx, recipient)
+	if err != nil {
+		return fmt.Errorf("broadcastsRepo.SaveRecipientResult: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) Complete(ctx context.Context, broadcastID int64) (bool, error) {
+	completed, err := s.broadcastsRepo.UpdateStatus(
+		ctx,
+		broadcastID,
+		domain.BroadcastStatusCompleted,
+		[]domain.BroadcastStatus{domain.BroadcastStatusActive},
+		time.Now(),
+	)
+	if err != nil {
+		return false, fmt.Errorf("broadcastsRepo.UpdateStatus: %w", err)
+	}
+
+	return completed, nil
+}
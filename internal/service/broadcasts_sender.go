@@ -0,0 +1,6 @@
+package service
+
+type BroadcastsSender interface {
+	Start()
+	Stop() error
+}
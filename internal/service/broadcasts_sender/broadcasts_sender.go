@@ -0,0 +1,185 @@
+package broadcasts_sender
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type svc struct {
+	broadcastsSvc   service.Broadcasts
+	telegramSvc     service.Telegram
+	userSettingsSvc service.UserSettings
+	cfg             config.Broadcasts
+	stopFunc        func()
+}
+
+func NewService(
+	broadcastsSvc service.Broadcasts,
+	telegramSvc service.Telegram,
+	userSettingsSvc service.UserSettings,
+	cfg config.Broadcasts,
+) *svc {
+	return &svc{
+		broadcastsSvc:   broadcastsSvc,
+		telegramSvc:     telegramSvc,
+		userSettingsSvc: userSettingsSvc,
+		cfg:             cfg,
+	}
+}
+
+func (s *svc) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopFunc = cancel
+
+	for {
+		select {
+		case <-time.After(s.cfg.CronDelay):
+			if err := s.sendBroadcasts(ctx); err != nil {
+				log.Error().Msgf("sendBroadcasts: %v", err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendBroadcasts активные рассылки отправляются по очереди в порядке создания
+func (s *svc) sendBroadcasts(ctx context.Context) error {
+	broadcasts, err := s.broadcastsSvc.ActiveBroadcasts(ctx)
+	if err != nil {
+		return fmt.Errorf("broadcastsSvc.ActiveBroadcasts: %w", err)
+	}
+
+	for _, broadcast := range broadcasts {
+		logger := log.With().Int64("broadcast", broadcast.ID).Logger()
+		err = s.sendBroadcast(logger.WithContext(ctx), broadcast)
+		if err != nil {
+			logger.Error().Msgf("sendBroadcast: %v", err.Error())
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+	}
+
+	return nil
+}
+
+// sendBroadcast результат сохраняется сразу после отправки каждому получателю, поэтому после перезапуска
+// рассылка продолжается с неотправленных. Сообщение, отправленное перед самым падением, может прийти дважды.
+// Неудачной отправка считается, только если телеграм отказал окончательно, например бот заблокирован
+func (s *svc) sendBroadcast(ctx context.Context, broadcast *domain.Broadcast) error {
+	message := domain.NewPlainMessage(broadcast.Text)
+	interval := time.Second / time.Duration(max(s.cfg.MessagesPerSecond, 1))
+
+	for {
+		userIDs, err := s.broadcastsSvc.PendingRecipients(ctx, broadcast.ID, s.cfg.BatchSize)
+		if err != nil {
+			return fmt.Errorf("broadcastsSvc.PendingRecipients: %w", err)
+		}
+		if len(userIDs) == 0 {
+			return s.complete(ctx, broadcast.ID)
+		}
+
+		for _, userID := range userIDs {
+			select {
+			case <-time.After(interval):
+			case <-ctx.Done():
+				return nil
+			}
+
+			// администратор мог приостановить или отменить рассылку во время отправки пачки
+			status, err := s.broadcastsSvc.Status(ctx, broadcast.ID)
+			if err != nil {
+				return fmt.Errorf("broadcastsSvc.Status: %w", err)
+			}
+			if status != domain.BroadcastStatusActive {
+				return nil
+			}
+
+			recipient := &domain.BroadcastRecipient{
+				BroadcastID: broadcast.ID,
+				UserID:      userID,
+				Status:      domain.BroadcastRecipientStatusSent,
+			}
+			sendErr := s.telegramSvc.SendMessage(userID, message)
+			var retryAfterErr *ierrors.RetryAfterError
+			switch {
+			case sendErr == nil:
+			case errors.As(sendErr, &retryAfterErr):
+				// получатель остаётся в очереди и попадёт в следующую пачку
+				log.Ctx(ctx).Warn().Msgf("telegramSvc.SendMessage: retry after %s", retryAfterErr.RetryAfter)
+				select {
+				case <-time.After(retryAfterErr.RetryAfter):
+					continue
+				case <-ctx.Done():
+					return nil
+				}
+			case errors.Is(sendErr, ierrors.ErrMessageRejected):
+				recipient.Status = domain.BroadcastRecipientStatusFailed
+				recipient.Error = sendErr.Error()
+			default:
+				// сеть или сбой телеграма: получатель остаётся в очереди до следующего запуска
+				return fmt.Errorf("telegramSvc.SendMessage: %w", sendErr)
+			}
+			recipient.ProcessedAt = time.Now()
+
+			err = s.broadcastsSvc.SaveRecipientResult(ctx, recipient)
+			if err != nil {
+				return fmt.Errorf("broadcastsSvc.SaveRecipientResult: %w", err)
+			}
+		}
+	}
+}
+
+// complete отчёт отправляется администратору, создавшему рассылку
+func (s *svc) complete(ctx context.Context, broadcastID int64) error {
+	completed, err := s.broadcastsSvc.Complete(ctx, broadcastID)
+	if err != nil {
+		return fmt.Errorf("broadcastsSvc.Complete: %w", err)
+	}
+	if !completed {
+		return nil
+	}
+
+	broadcast, err := s.broadcastsSvc.Broadcast(ctx, broadcastID)
+	if err != nil {
+		return fmt.Errorf("broadcastsSvc.Broadcast: %w", err)
+	}
+
+	language, err := s.userSettingsSvc.Language(ctx, broadcast.AdminID)
+	if err != nil {
+		log.Ctx(ctx).Error().Msgf("userSettingsSvc.Language: %v", err.Error())
+	}
+
+	err = s.telegramSvc.SendMessage(broadcast.AdminID, domain.NewPlainMessage(answers.Textf(
+		language,
+		answers.AdminBroadcastReport,
+		broadcast.ID,
+		broadcast.Progress.Sent,
+		broadcast.Progress.Total,
+		broadcast.Progress.Failed,
+	)))
+	if err != nil {
+		return fmt.Errorf("telegramSvc.SendMessage: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) Stop() error {
+	if s.stopFunc == nil {
+		return errors.New("service is not started")
+	}
+
+	s.stopFunc()
+	return nil
+}
@@ -0,0 +1,76 @@
+package broadcasts_sender
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+)
+
+type broadcastsService = service.Broadcasts
+
+// memoryBroadcasts одна рассылка, получатели которой отмечаются обработанными при сохранении результата
+type memoryBroadcasts struct {
+	broadcastsService
+	status  domain.BroadcastStatus
+	pending []int64
+}
+
+func (s *memoryBroadcasts) Status(_ context.Context, _ int64) (domain.BroadcastStatus, error) {
+	return s.status, nil
+}
+
+func (s *memoryBroadcasts) PendingRecipients(_ context.Context, _ int64, limit int) ([]int64, error) {
+	return s.pending[:min(limit, len(s.pending))], nil
+}
+
+func (s *memoryBroadcasts) SaveRecipientResult(_ context.Context, recipient *domain.BroadcastRecipient) error {
+	for i, userID := range s.pending {
+		if userID == recipient.UserID {
+			s.pending = append(s.pending[:i], s.pending[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
+type telegramService = service.Telegram
+
+// pausingTelegram приостанавливает рассылку, как это сделал бы администратор, после pauseAfter сообщений
+type pausingTelegram struct {
+	telegramService
+	broadcasts *memoryBroadcasts
+	pauseAfter int
+	sent       []int64
+}
+
+func (s *pausingTelegram) SendMessage(id int64, _ *domain.Message) error {
+	s.sent = append(s.sent, id)
+	if len(s.sent) == s.pauseAfter {
+		s.broadcasts.status = domain.BroadcastStatusPaused
+	}
+	return nil
+}
+
+func TestSendBroadcastStopsWithinBatchWhenPaused(t *testing.T) {
+	broadcasts := &memoryBroadcasts{
+		status:  domain.BroadcastStatusActive,
+		pending: []int64{1, 2, 3, 4, 5},
+	}
+	telegram := &pausingTelegram{broadcasts: broadcasts, pauseAfter: 2}
+	s := NewService(broadcasts, telegram, nil, config.Broadcasts{MessagesPerSecond: 1000, BatchSize: 100})
+
+	err := s.sendBroadcast(context.Background(), &domain.Broadcast{ID: 1, Text: "Новость"})
+	if err != nil {
+		t.Fatalf("sendBroadcast: %v", err)
+	}
+
+	if len(telegram.sent) != 2 {
+		t.Errorf("sent to %v after pause, want 2 recipients", telegram.sent)
+	}
+	if len(broadcasts.pending) != 3 {
+		t.Errorf("pending %v, want the remaining 3 recipients", broadcasts.pending)
+	}
+}
@@ -0,0 +1,12 @@
+package service
+
+import "context"
+
+type Calendar interface {
+	// FeedURL секретный адрес ленты iCalendar, при первом обращении лента создаётся
+	FeedURL(ctx context.Context, userID int64) (string, error)
+	// RotateFeedURL выпускает новый адрес, старый перестаёт работать
+	RotateFeedURL(ctx context.Context, userID int64) (string, error)
+	// Feed лента контрольных мероприятий по токену из адреса или ErrFeedNotFound
+	Feed(ctx context.Context, token string) ([]byte, error)
+}
@@ -0,0 +1,151 @@
+package calendar
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/ical"
+)
+
+const (
+	// feedPath путь ленты в API, за ним следуют токен и расширение .ics
+	feedPath = "/v1/calendar/"
+
+	prodID = "-//tracking-bars//control events//RU"
+)
+
+type svc struct {
+	feedTokensSvc   service.FeedTokens
+	userSvc         service.User
+	userSettingsSvc service.UserSettings
+	publicURL       string
+	autumnStart     time.Time
+	springStart     time.Time
+	cfg             config.Calendar
+}
+
+func NewService(
+	feedTokensSvc service.FeedTokens,
+	userSvc service.User,
+	userSettingsSvc service.UserSettings,
+	publicURL string,
+	cfg config.Calendar,
+) (*svc, error) {
+	autumnStart, err := time.Parse(domain.SemesterStartLayout, cfg.AutumnSemesterStart)
+	if err != nil {
+		return nil, fmt.Errorf("time.Parse (autumn semester start): %w", err)
+	}
+	springStart, err := time.Parse(domain.SemesterStartLayout, cfg.SpringSemesterStart)
+	if err != nil {
+		return nil, fmt.Errorf("time.Parse (spring semester start): %w", err)
+	}
+
+	return &svc{
+		feedTokensSvc:   feedTokensSvc,
+		userSvc:         userSvc,
+		userSettingsSvc: userSettingsSvc,
+		publicURL:       publicURL,
+		autumnStart:     autumnStart,
+		springStart:     springStart,
+		cfg:             cfg,
+	}, nil
+}
+
+func (s *svc) FeedURL(ctx context.Context, userID int64) (string, error) {
+	token, err := s.feedTokensSvc.Token(ctx, userID, domain.FeedKindCalendar)
+	if err != nil {
+		return "", fmt.Errorf("feedTokensSvc.Token: %w", err)
+	}
+
+	return s.feedURL(token), nil
+}
+
+func (s *svc) RotateFeedURL(ctx context.Context, userID int64) (string, error) {
+	token, err := s.feedTokensSvc.Rotate(ctx, userID, domain.FeedKindCalendar)
+	if err != nil {
+		return "", fmt.Errorf("feedTokensSvc.Rotate: %w", err)
+	}
+
+	return s.feedURL(token), nil
+}
+
+func (s *svc) Feed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.feedTokensSvc.FeedToken(ctx, domain.FeedKindCalendar, token)
+	if err != nil {
+		return nil, fmt.Errorf("feedTokensSvc.FeedToken: %w", err)
+	}
+
+	user, err := s.userSvc.User(ctx, feed.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.User: %w", err)
+	}
+
+	language, err := s.userSettingsSvc.Language(ctx, feed.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsSvc.Language: %w", err)
+	}
+
+	now := time.Now().In(config.BARSLocation)
+	calendar := &ical.Calendar{
+		ProdID: prodID,
+		Name:   answers.Text(language, answers.CalendarName),
+	}
+	// пустая лента лучше ошибки: календарное приложение не должно отписываться после /logout
+	if user != nil && user.ProgressTable != nil {
+		semesterStart := domain.SemesterStart(now, s.autumnStart, s.springStart)
+		calendar.Events = controlEventsToEvents(language, feed.UserID, user.ProgressTable, semesterStart)
+	}
+
+	return calendar.Marshal(now), nil
+}
+
+func (s *svc) feedURL(token string) string {
+	return strings.TrimSuffix(s.publicURL, "/") + feedPath + token + ".ics"
+}
+
+func controlEventsToEvents(
+	language domain.Language,
+	userID int64,
+	progressTable *domain.ProgressTable,
+	semesterStart time.Time,
+) []ical.Event {
+	plannedEvents := domain.PlannedControlEvents(progressTable, semesterStart)
+
+	events := make([]ical.Event, 0, len(plannedEvents))
+	for _, event := range plannedEvents {
+		ce := event.ControlEvent
+
+		description := make([]string, 0, 2)
+		if ce.DeadlineWeek > 0 {
+			description = append(description, answers.Textf(language, answers.CalendarEventWeek, ce.DeadlineWeek))
+		}
+		if ce.Grade.IsGraded() {
+			description = append(description, answers.Textf(language, answers.CalendarEventGrade, ce.Grade.Raw))
+		}
+
+		events = append(events, ical.Event{
+			UID:         eventUID(userID, event.Discipline, ce.Name),
+			Summary:     event.Discipline + " — " + ce.Name,
+			Description: strings.Join(description, "\n"),
+			Start:       event.Start,
+			End:         event.End,
+		})
+	}
+
+	return events
+}
+
+// eventUID не меняется между загрузками ленты, чтобы приложение обновляло события, а не дублировало их
+func eventUID(userID int64, discipline string, controlEvent string) string {
+	hash := sha1.Sum([]byte(strconv.FormatInt(userID, 10) + "\x00" + discipline + "\x00" + controlEvent))
+	return hex.EncodeToString(hash[:]) + "@tracking-bars"
+}
@@ -0,0 +1,25 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Chats interface {
+	// Chat возвращает nil, если бота нет в чате
+	Chat(ctx context.Context, chatID int64) (*domain.Chat, error)
+	// Register сохраняет чат, в который добавили бота
+	Register(ctx context.Context, chat *domain.Chat) error
+	// Remove удаляет чат, из которого бота убрали, вместе с участниками
+	Remove(ctx context.Context, chatID int64) error
+	Migrate(ctx context.Context, fromChatID int64, toChatID int64) error
+	// Join подписывает чат на обезличенные сообщения об оценках пользователя, незнакомый чат регистрируется
+	Join(ctx context.Context, chat *domain.Chat, userID int64) error
+	// Leave возвращает ErrNotChatMember, если пользователь не вступал в чат
+	Leave(ctx context.Context, chatID int64, userID int64) error
+	MemberChatIDs(ctx context.Context, userID int64) ([]int64, error)
+	// IsAnnounced было ли сообщение с таким ключом за GROUP_CHATS_ANNOUNCEMENT_PERIOD
+	IsAnnounced(ctx context.Context, chatID int64, key string) (bool, error)
+	MarkAnnounced(ctx context.Context, chatID int64, key string) error
+}
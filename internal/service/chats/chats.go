@@ -0,0 +1,134 @@
+package chats
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+)
+
+type svc struct {
+	chatsRepo repository.Chats
+	cfg       config.GroupChats
+}
+
+func NewService(
+	chatsRepo repository.Chats,
+	cfg config.GroupChats,
+) *svc {
+	return &svc{
+		chatsRepo: chatsRepo,
+		cfg:       cfg,
+	}
+}
+
+func (s *svc) Chat(ctx context.Context, chatID int64) (*domain.Chat, error) {
+	chat, err := s.chatsRepo.Chat(ctx, chatID)
+	if err != nil {
+		return nil, fmt.Errorf("chatsRepo.Chat: %w", err)
+	}
+
+	return chat, nil
+}
+
+func (s *svc) Register(ctx context.Context, chat *domain.Chat) error {
+	err := s.chatsRepo.Save(ctx, chat)
+	if err != nil {
+		return fmt.Errorf("chatsRepo.Save: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) Remove(ctx context.Context, chatID int64) error {
+	err := s.chatsRepo.Delete(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("chatsRepo.Delete: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) Migrate(ctx context.Context, fromChatID int64, toChatID int64) error {
+	err := s.chatsRepo.Migrate(ctx, fromChatID, toChatID)
+	if err != nil {
+		return fmt.Errorf("chatsRepo.Migrate: %w", err)
+	}
+
+	return nil
+}
+
+// Join бота могли добавить в чат до того, как он начал их запоминать, поэтому чат регистрируется здесь же.
+// Язык уже известного чата не меняется: его выбрал тот, кто добавил бота
+func (s *svc) Join(ctx context.Context, chat *domain.Chat, userID int64) error {
+	existing, err := s.chatsRepo.Chat(ctx, chat.ID)
+	if err != nil {
+		return fmt.Errorf("chatsRepo.Chat: %w", err)
+	}
+	if existing == nil {
+		err = s.chatsRepo.Save(ctx, chat)
+		if err != nil {
+			return fmt.Errorf("chatsRepo.Save: %w", err)
+		}
+	}
+
+	err = s.chatsRepo.AddMember(ctx, chat.ID, userID)
+	if err != nil {
+		return fmt.Errorf("chatsRepo.AddMember: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) Leave(ctx context.Context, chatID int64, userID int64) error {
+	removed, err := s.chatsRepo.RemoveMember(ctx, chatID, userID)
+	if err != nil {
+		return fmt.Errorf("chatsRepo.RemoveMember: %w", err)
+	}
+	if !removed {
+		return ierrors.ErrNotChatMember
+	}
+
+	return nil
+}
+
+func (s *svc) MemberChatIDs(ctx context.Context, userID int64) ([]int64, error) {
+	chatIDs, err := s.chatsRepo.MemberChatIDs(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("chatsRepo.MemberChatIDs: %w", err)
+	}
+
+	return chatIDs, nil
+}
+
+func (s *svc) IsAnnounced(ctx context.Context, chatID int64, key string) (bool, error) {
+	isAnnounced, err := s.chatsRepo.IsAnnounced(ctx, chatID, key, time.Now().Add(-s.cfg.AnnouncementPeriod))
+	if err != nil {
+		return false, fmt.Errorf("chatsRepo.IsAnnounced: %w", err)
+	}
+
+	return isAnnounced, nil
+}
+
+func (s *svc) MarkAnnounced(ctx context.Context, chatID int64, key string) error {
+	err := s.chatsRepo.SaveAnnouncement(ctx, &domain.ChatAnnouncement{
+		ChatID:      chatID,
+		Key:         key,
+		AnnouncedAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("chatsRepo.SaveAnnouncement: %w", err)
+	}
+
+	// отметки старше GROUP_CHATS_ANNOUNCEMENT_PERIOD уже ни на что не влияют
+	err = s.chatsRepo.DeleteAnnouncements(ctx, chatID, time.Now().Add(-s.cfg.AnnouncementPeriod))
+	if err != nil {
+		return fmt.Errorf("chatsRepo.DeleteAnnouncements: %w", err)
+	}
+
+	return nil
+}
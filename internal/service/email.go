@@ -0,0 +1,12 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Email interface {
+	// Send отправляет письмо, тип содержимого определяется message.Format
+	Send(ctx context.Context, to string, subject string, message *domain.Message) error
+}
@@ -0,0 +1,128 @@
+package email
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+// base64LineLength длина строки тела письма, RFC 2045 ограничивает её 76 символами
+const base64LineLength = 76
+
+type svc struct {
+	cfg config.Email
+}
+
+func NewService(cfg config.Email) *svc {
+	return &svc{cfg: cfg}
+}
+
+// Send STARTTLS включается, только если сервер его объявляет, а авторизация - только при заданном логине,
+// поэтому письма можно отправлять и в локальный SMTP-стенд
+func (s *svc) Send(ctx context.Context, to string, subject string, message *domain.Message) error {
+	content, err := s.buildMessage(to, subject, message, time.Now())
+	if err != nil {
+		return fmt.Errorf("buildMessage: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.SMTPTimeout)
+	defer cancel()
+
+	addr := net.JoinHostPort(s.cfg.SMTPHost, strconv.Itoa(s.cfg.SMTPPort))
+	dialer := &net.Dialer{}
+	conn, err := dialer.DialContext(ctx, "tcp", addr)
+	if err != nil {
+		return fmt.Errorf("dialer.DialContext: %w", err)
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		if err = conn.SetDeadline(deadline); err != nil {
+			conn.Close()
+			return fmt.Errorf("conn.SetDeadline: %w", err)
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.SMTPHost)
+	if err != nil {
+		conn.Close()
+		return fmt.Errorf("smtp.NewClient: %w", err)
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err = client.StartTLS(&tls.Config{ServerName: s.cfg.SMTPHost}); err != nil {
+			return fmt.Errorf("client.StartTLS: %w", err)
+		}
+	}
+	if s.cfg.SMTPUsername != "" {
+		auth := smtp.PlainAuth("", s.cfg.SMTPUsername, s.cfg.SMTPPassword, s.cfg.SMTPHost)
+		if err = client.Auth(auth); err != nil {
+			return fmt.Errorf("client.Auth: %w", err)
+		}
+	}
+
+	if err = client.Mail(s.cfg.From); err != nil {
+		return fmt.Errorf("client.Mail: %w", err)
+	}
+	if err = client.Rcpt(to); err != nil {
+		return fmt.Errorf("client.Rcpt: %w", err)
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return fmt.Errorf("client.Data: %w", err)
+	}
+	if _, err = w.Write(content); err != nil {
+		return fmt.Errorf("w.Write: %w", err)
+	}
+	if err = w.Close(); err != nil {
+		return fmt.Errorf("w.Close: %w", err)
+	}
+
+	if err = client.Quit(); err != nil {
+		return fmt.Errorf("client.Quit: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) buildMessage(to string, subject string, message *domain.Message, now time.Time) ([]byte, error) {
+	var contentType string
+	switch message.Format {
+	case domain.MessageFormatHTML:
+		contentType = "text/html"
+	case domain.MessageFormatPlain:
+		contentType = "text/plain"
+	default:
+		return nil, fmt.Errorf("unsupported message format %q", message.Format)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "From: %s\r\n", s.cfg.From)
+	fmt.Fprintf(&b, "To: %s\r\n", to)
+	fmt.Fprintf(&b, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", subject))
+	fmt.Fprintf(&b, "Date: %s\r\n", now.Format(time.RFC1123Z))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	fmt.Fprintf(&b, "Content-Type: %s; charset=utf-8\r\n", contentType)
+	b.WriteString("Content-Transfer-Encoding: base64\r\n\r\n")
+
+	encoded := base64.StdEncoding.EncodeToString([]byte(message.Text))
+	for len(encoded) > base64LineLength {
+		b.WriteString(encoded[:base64LineLength])
+		b.WriteString("\r\n")
+		encoded = encoded[base64LineLength:]
+	}
+	b.WriteString(encoded)
+	b.WriteString("\r\n")
+
+	return b.Bytes(), nil
+}
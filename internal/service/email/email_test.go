@@ -0,0 +1,86 @@
+package email
+
+import (
+	"encoding/base64"
+	"mime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+func TestBuildMessage(t *testing.T) {
+	s := NewService(config.Email{From: "tracking-bars@localhost"})
+	now := time.Date(2024, time.October, 18, 19, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	subject := "Код подтверждения tracking-bars"
+	text := strings.Repeat("Ваш код подтверждения: 123456\n", 10)
+
+	content, err := s.buildMessage("student@mpei.ru", subject, domain.NewPlainMessage(text), now)
+	if err != nil {
+		t.Fatalf("buildMessage: %v", err)
+	}
+
+	header, body, ok := strings.Cut(string(content), "\r\n\r\n")
+	if !ok {
+		t.Fatalf("no blank line between header and body in %q", content)
+	}
+
+	headers := make(map[string]string)
+	for _, line := range strings.Split(header, "\r\n") {
+		name, value, _ := strings.Cut(line, ": ")
+		headers[name] = value
+	}
+	wantHeaders := map[string]string{
+		"From":                      "tracking-bars@localhost",
+		"To":                        "student@mpei.ru",
+		"Date":                      "Fri, 18 Oct 2024 19:00:00 +0300",
+		"MIME-Version":              "1.0",
+		"Content-Type":              "text/plain; charset=utf-8",
+		"Content-Transfer-Encoding": "base64",
+	}
+	for name, want := range wantHeaders {
+		if got := headers[name]; got != want {
+			t.Errorf("header %s = %q, want %q", name, got, want)
+		}
+	}
+
+	encodedSubject := headers["Subject"]
+	if !strings.HasPrefix(encodedSubject, "=?utf-8?q?") {
+		t.Errorf("subject %q is not Q-encoded", encodedSubject)
+	}
+	decodedSubject, err := new(mime.WordDecoder).DecodeHeader(encodedSubject)
+	if err != nil {
+		t.Fatalf("DecodeHeader: %v", err)
+	}
+	if decodedSubject != subject {
+		t.Errorf("decoded subject = %q, want %q", decodedSubject, subject)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(body, "\r\n"), "\r\n")
+	if len(lines) < 2 {
+		t.Fatalf("body is not split into lines: %q", body)
+	}
+	for _, line := range lines {
+		if len(line) > base64LineLength {
+			t.Errorf("body line is %d characters long, want at most %d", len(line), base64LineLength)
+		}
+	}
+	decodedBody, err := base64.StdEncoding.DecodeString(strings.Join(lines, ""))
+	if err != nil {
+		t.Fatalf("base64.DecodeString: %v", err)
+	}
+	if string(decodedBody) != text {
+		t.Errorf("decoded body = %q, want %q", decodedBody, text)
+	}
+}
+
+func TestBuildMessageUnsupportedFormat(t *testing.T) {
+	s := NewService(config.Email{})
+	message := &domain.Message{Text: "*text*", Format: domain.MessageFormatMarkdownV2}
+
+	if _, err := s.buildMessage("student@mpei.ru", "subject", message, time.Now()); err == nil {
+		t.Errorf("buildMessage() with markdown succeeded, want error")
+	}
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type FeedTokens interface {
+	// Token токен ленты пользователя, при первом обращении лента создаётся
+	Token(ctx context.Context, userID int64, kind domain.FeedKind) (string, error)
+	// Rotate выпускает новый токен, старый перестаёт работать
+	Rotate(ctx context.Context, userID int64, kind domain.FeedKind) (string, error)
+	// FeedToken лента по токену из адреса или ErrFeedNotFound
+	FeedToken(ctx context.Context, kind domain.FeedKind, token string) (*domain.FeedToken, error)
+}
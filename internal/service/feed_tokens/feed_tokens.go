@@ -0,0 +1,92 @@
+package feed_tokens
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/aes"
+)
+
+const tokenBytes = 32
+
+type svc struct {
+	feedTokensRepo repository.FeedTokens
+	encryptionKey  string
+}
+
+func NewService(
+	feedTokensRepo repository.FeedTokens,
+	encryptionKey string,
+) *svc {
+	return &svc{
+		feedTokensRepo: feedTokensRepo,
+		encryptionKey:  encryptionKey,
+	}
+}
+
+func (s *svc) Token(ctx context.Context, userID int64, kind domain.FeedKind) (string, error) {
+	feedToken, err := s.feedTokensRepo.FeedToken(ctx, userID, kind)
+	if err != nil {
+		return "", fmt.Errorf("feedTokensRepo.FeedToken: %w", err)
+	}
+	if feedToken == nil {
+		return s.Rotate(ctx, userID, kind)
+	}
+
+	token, err := aes.Decrypt([]byte(s.encryptionKey), feedToken.Token)
+	if err != nil {
+		return "", fmt.Errorf("aes.Decrypt: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *svc) Rotate(ctx context.Context, userID int64, kind domain.FeedKind) (string, error) {
+	raw := make([]byte, tokenBytes)
+	if _, err := rand.Read(raw); err != nil {
+		return "", fmt.Errorf("rand.Read: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	encryptedToken, err := aes.Encrypt([]byte(s.encryptionKey), []byte(token))
+	if err != nil {
+		return "", fmt.Errorf("aes.Encrypt (token): %w", err)
+	}
+
+	err = s.feedTokensRepo.Save(ctx, &domain.FeedToken{
+		UserID:    userID,
+		Kind:      kind,
+		TokenHash: hashToken(token),
+		Token:     encryptedToken,
+		CreatedAt: time.Now(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("feedTokensRepo.Save: %w", err)
+	}
+
+	return token, nil
+}
+
+func (s *svc) FeedToken(ctx context.Context, kind domain.FeedKind, token string) (*domain.FeedToken, error) {
+	feedToken, err := s.feedTokensRepo.FeedTokenByHash(ctx, kind, hashToken(token))
+	if err != nil {
+		return nil, fmt.Errorf("feedTokensRepo.FeedTokenByHash: %w", err)
+	}
+	if feedToken == nil {
+		return nil, ierrors.ErrFeedNotFound
+	}
+
+	return feedToken, nil
+}
+
+func hashToken(token string) []byte {
+	hash := sha256.Sum256([]byte(token))
+	return hash[:]
+}
@@ -0,0 +1,16 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type GradeDistributions interface {
+	// Distributions распределения по мероприятиям дисциплины текущего семестра
+	Distributions(ctx context.Context, semester string, discipline string) ([]*domain.GradeDistribution, error)
+	// Search распределения для администратора, в названии дисциплины которых есть query
+	Search(ctx context.Context, query string) ([]*domain.GradeDistribution, error)
+	Start()
+	Stop() error
+}
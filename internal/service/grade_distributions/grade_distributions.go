@@ -0,0 +1,206 @@
+package grade_distributions
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+const (
+	// minContributorsFloor при меньшем числе участников даже округлённые квартили выдают оценки
+	// отдельных людей, поэтому меньший DISTRIBUTIONS_MIN_CONTRIBUTORS не принимается
+	minContributorsFloor = 10
+	searchLimit          = 30
+)
+
+type distributionKey struct {
+	semester     string
+	discipline   string
+	controlEvent string
+}
+
+type svc struct {
+	gradeDistributionsRepo repository.GradeDistributions
+	userSvc                service.User
+	userSettingsSvc        service.UserSettings
+	cfg                    config.Distributions
+	stopFunc               func()
+}
+
+func NewService(
+	gradeDistributionsRepo repository.GradeDistributions,
+	userSvc service.User,
+	userSettingsSvc service.UserSettings,
+	cfg config.Distributions,
+) (*svc, error) {
+	if cfg.MinContributors < minContributorsFloor {
+		return nil, fmt.Errorf("min contributors must be at least %d, got %d", minContributorsFloor, cfg.MinContributors)
+	}
+
+	return &svc{
+		gradeDistributionsRepo: gradeDistributionsRepo,
+		userSvc:                userSvc,
+		userSettingsSvc:        userSettingsSvc,
+		cfg:                    cfg,
+	}, nil
+}
+
+func (s *svc) Distributions(
+	ctx context.Context,
+	semester string,
+	discipline string,
+) ([]*domain.GradeDistribution, error) {
+	distributions, err := s.gradeDistributionsRepo.Distributions(ctx, semester, discipline, s.cfg.MinContributors)
+	if err != nil {
+		return nil, fmt.Errorf("gradeDistributionsRepo.Distributions: %w", err)
+	}
+
+	return distributions, nil
+}
+
+func (s *svc) Search(ctx context.Context, query string) ([]*domain.GradeDistribution, error) {
+	distributions, err := s.gradeDistributionsRepo.Search(ctx, query, s.cfg.MinContributors, searchLimit)
+	if err != nil {
+		return nil, fmt.Errorf("gradeDistributionsRepo.Search: %w", err)
+	}
+
+	return distributions, nil
+}
+
+func (s *svc) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopFunc = cancel
+
+	for {
+		select {
+		case <-time.After(s.cfg.CronDelay):
+			log.Info().Msg("aggregating grade distributions")
+			if err := s.aggregate(ctx); err != nil {
+				log.Error().Msgf("aggregate: %v", err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// aggregate пересчитывает распределения по текущим таблицам успеваемости пользователей с ShareGrades.
+// Учитываются только баллы, мероприятия с числом участников меньше cfg.MinContributors не сохраняются вовсе
+func (s *svc) aggregate(ctx context.Context) error {
+	users, err := s.userSvc.Users(ctx)
+	if err != nil {
+		return fmt.Errorf("userSvc.Users: %w", err)
+	}
+
+	scores := make(map[distributionKey]map[int64]float64)
+	for _, user := range users {
+		if user.ProgressTable == nil {
+			continue
+		}
+
+		settings, err := s.userSettingsSvc.UserSettings(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("userSettingsSvc.UserSettings: %w", err)
+		}
+		if settings == nil || !settings.ShareGrades {
+			continue
+		}
+
+		for _, discipline := range user.ProgressTable.Disciplines {
+			for _, ce := range discipline.ControlEvents {
+				if !ce.Grade.HasScore() {
+					continue
+				}
+
+				key := distributionKey{
+					semester:     user.ProgressTable.Semester.Name,
+					discipline:   discipline.Name,
+					controlEvent: ce.Name,
+				}
+				if scores[key] == nil {
+					scores[key] = make(map[int64]float64)
+				}
+				// одинаковые названия в таблице одного пользователя не должны засчитываться ему дважды
+				if _, ok := scores[key][user.ID]; ok {
+					continue
+				}
+				scores[key][user.ID] = ce.Grade.Score
+			}
+		}
+	}
+
+	published, err := s.gradeDistributionsRepo.Published(ctx)
+	if err != nil {
+		return fmt.Errorf("gradeDistributionsRepo.Published: %w", err)
+	}
+
+	distributions := selectDistributions(scores, published, s.cfg.MinContributors, time.Now())
+
+	err = s.gradeDistributionsRepo.Replace(ctx, distributions)
+	if err != nil {
+		return fmt.Errorf("gradeDistributionsRepo.Replace: %w", err)
+	}
+
+	log.Info().Msgf("grade distributions aggregated: %d published", len(distributions))
+
+	return nil
+}
+
+// selectDistributions распределения для публикации. Если с прошлой публикации только добавилось меньше
+// minContributors участников, остаётся прошлое распределение: по разнице двух публикаций восстанавливаются
+// оценки добавившихся. Если кто-то выбыл, например отказался делиться оценками, прошлое распределение
+// с его оценкой не сохраняется
+func selectDistributions(
+	scores map[distributionKey]map[int64]float64,
+	published []*domain.GradeDistribution,
+	minContributors int,
+	now time.Time,
+) []*domain.GradeDistribution {
+	publishedByKey := make(map[distributionKey]*domain.GradeDistribution, len(published))
+	for _, distribution := range published {
+		publishedByKey[distributionKey{
+			semester:     distribution.Semester,
+			discipline:   distribution.Discipline,
+			controlEvent: distribution.ControlEvent,
+		}] = distribution
+	}
+
+	distributions := make([]*domain.GradeDistribution, 0)
+	for key, keyScores := range scores {
+		if len(keyScores) < minContributors {
+			continue
+		}
+
+		distribution := domain.NewGradeDistribution(
+			key.semester,
+			key.discipline,
+			key.controlEvent,
+			keyScores,
+			now,
+		)
+		previous, ok := publishedByKey[key]
+		if ok && distribution.KeepsContributors(previous) && distribution.ContributorsChanged(previous) < minContributors {
+			distribution = previous
+		}
+
+		distributions = append(distributions, distribution)
+	}
+
+	return distributions
+}
+
+func (s *svc) Stop() error {
+	if s.stopFunc == nil {
+		return errors.New("service is not started")
+	}
+
+	s.stopFunc()
+	return nil
+}
@@ -0,0 +1,108 @@
+package grade_distributions
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+const testMinContributors = 10
+
+var testKey = distributionKey{semester: "Осень 2024", discipline: "Физика", controlEvent: "КМ-1"}
+
+// contributorScores баллы пользователей с идентификаторами от first до last включительно
+func contributorScores(first, last int64, score float64) map[int64]float64 {
+	scores := make(map[int64]float64)
+	for userID := first; userID <= last; userID++ {
+		scores[userID] = score
+	}
+	return scores
+}
+
+func TestSelectDistributions(t *testing.T) {
+	publishedAt := time.Date(2024, time.October, 14, 0, 0, 0, 0, time.UTC)
+	now := publishedAt.AddDate(0, 0, 2)
+	published := domain.NewGradeDistribution(
+		testKey.semester,
+		testKey.discipline,
+		testKey.controlEvent,
+		contributorScores(1, 10, 4),
+		publishedAt,
+	)
+
+	tests := []struct {
+		name      string
+		scores    map[int64]float64
+		published []*domain.GradeDistribution
+		// wantUpdatedAt нулевое, если распределение не публикуется
+		wantUpdatedAt time.Time
+	}{
+		{
+			name:          "too few contributors",
+			scores:        contributorScores(1, 9, 4),
+			wantUpdatedAt: time.Time{},
+		},
+		{
+			name:          "first publication",
+			scores:        contributorScores(1, 10, 4),
+			wantUpdatedAt: now,
+		},
+		{
+			// иначе балл добавившегося равен разнице сумм двух публикаций
+			name:          "one contributor joined",
+			scores:        contributorScores(1, 11, 2),
+			published:     []*domain.GradeDistribution{published},
+			wantUpdatedAt: publishedAt,
+		},
+		{
+			name:          "score changed without changing contributors",
+			scores:        contributorScores(1, 10, 5),
+			published:     []*domain.GradeDistribution{published},
+			wantUpdatedAt: publishedAt,
+		},
+		{
+			name:          "enough contributors joined",
+			scores:        contributorScores(1, 20, 5),
+			published:     []*domain.GradeDistribution{published},
+			wantUpdatedAt: now,
+		},
+		{
+			name:          "contributors replaced",
+			scores:        contributorScores(6, 15, 5),
+			published:     []*domain.GradeDistribution{published},
+			wantUpdatedAt: now,
+		},
+		{
+			// выбывший отказался делиться оценками, его балл не должен оставаться в публикации
+			name:          "contributor opted out",
+			scores:        contributorScores(2, 11, 4),
+			published:     []*domain.GradeDistribution{published},
+			wantUpdatedAt: now,
+		},
+		{
+			name:          "dropped below the threshold",
+			scores:        contributorScores(1, 9, 4),
+			published:     []*domain.GradeDistribution{published},
+			wantUpdatedAt: time.Time{},
+		},
+	}
+
+	for _, tt := range tests {
+		scores := map[distributionKey]map[int64]float64{testKey: tt.scores}
+		distributions := selectDistributions(scores, tt.published, testMinContributors, now)
+
+		if tt.wantUpdatedAt.IsZero() {
+			if len(distributions) != 0 {
+				t.Errorf("%s: published %+v, want nothing", tt.name, distributions[0])
+			}
+			continue
+		}
+		if len(distributions) != 1 {
+			t.Fatalf("%s: published %d distributions, want 1", tt.name, len(distributions))
+		}
+		if !distributions[0].UpdatedAt.Equal(tt.wantUpdatedAt) {
+			t.Errorf("%s: updated at %s, want %s", tt.name, distributions[0].UpdatedAt, tt.wantUpdatedAt)
+		}
+	}
+}
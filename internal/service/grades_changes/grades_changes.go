@@ -4,7 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"strings"
+	"slices"
 	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/config"
@@ -14,32 +14,36 @@ import (
 	"github.com/ilyadubrovsky/tracking-bars/internal/service"
 	"github.com/ilyadubrovsky/tracking-bars/pkg/aes"
 	"github.com/ilyadubrovsky/tracking-bars/pkg/bars"
-	"github.com/jellydator/ttlcache/v3"
 	"github.com/rs/zerolog/log"
 )
 
 type svc struct {
-	telegramSvc       service.Telegram
-	barsSvc           service.Bars
-	userSvc           service.User
-	retriesCountCache *ttlcache.Cache[int64, int]
-	cfg               config.Bars
-	stopFunc          func()
+	telegramSvc              service.Telegram
+	barsSvc                  service.Bars
+	userSvc                  service.User
+	authorizationFailuresSvc service.AuthorizationFailures
+	userSettingsSvc          service.UserSettings
+	userLocks                *userLocks
+	cfg                      config.Bars
+	stopFunc                 func()
 }
 
 func NewService(
 	telegramSvc service.Telegram,
 	barsSvc service.Bars,
 	userSvc service.User,
-	retriesCountCache *ttlcache.Cache[int64, int],
+	authorizationFailuresSvc service.AuthorizationFailures,
+	userSettingsSvc service.UserSettings,
 	cfg config.Bars,
 ) *svc {
 	return &svc{
-		telegramSvc:       telegramSvc,
-		barsSvc:           barsSvc,
-		userSvc:           userSvc,
-		retriesCountCache: retriesCountCache,
-		cfg:               cfg,
+		telegramSvc:              telegramSvc,
+		barsSvc:                  barsSvc,
+		userSvc:                  userSvc,
+		authorizationFailuresSvc: authorizationFailuresSvc,
+		userSettingsSvc:          userSettingsSvc,
+		userLocks:                newUserLocks(),
+		cfg:                      cfg,
 	}
 }
 
@@ -52,11 +56,14 @@ func (s *svc) Start() {
 		log.Info().Msgf("start %d grades changes worker", i+1)
 		go s.checkChangesWorker(usersChan)
 	}
+	go s.checkRequestedUsers(ctx)
 	func() {
 		log.Info().Msg("start actual credentials sender")
 		for {
 			select {
 			case <-time.After(s.cfg.CronDelay):
+				log.Info().Msg("deleting expired suspensions")
+				s.deleteExpiredSuspensions(ctx)
 				log.Info().Msg("sending actual credentials")
 				s.sendActualCredentials(ctx, usersChan)
 			case <-ctx.Done():
@@ -83,6 +90,51 @@ func (s *svc) sendActualCredentials(
 	}
 }
 
+// checkRequestedUsers внеочередные проверки идут отдельно от общего обхода, чтобы не ждать CronDelay.
+// Запрос снимается до проверки: при ошибке администратор увидит её в /auser и запросит проверку снова
+func (s *svc) checkRequestedUsers(ctx context.Context) {
+	barsClient := bars.NewClient(config.BARSRegistrationPageURL)
+	for {
+		select {
+		case <-time.After(s.cfg.CheckRequestsDelay):
+			users, err := s.userSvc.CheckRequestedUsers(ctx)
+			if err != nil {
+				log.Error().Msgf("checkRequestedUsers: userSvc.CheckRequestedUsers: %v", err.Error())
+				continue
+			}
+
+			for _, user := range users {
+				s.checkRequestedUser(ctx, barsClient, user)
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+func (s *svc) checkRequestedUser(ctx context.Context, barsClient bars.Client, user *domain.User) {
+	defer barsClient.Clear()
+
+	err := s.userSvc.ClearCheckRequest(ctx, user.ID)
+	if err != nil {
+		log.Error().
+			Int64("user", user.ID).
+			Msgf("checkRequestedUser: userSvc.ClearCheckRequest: %v", err.Error())
+		return
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	log.Info().Int64("user", user.ID).Msg("checking grades on request")
+	err = s.checkChanges(checkCtx, barsClient, user)
+	if err != nil {
+		log.Error().
+			Int64("user", user.ID).
+			Msgf("checkRequestedUser: checkChanges: %v", err.Error())
+	}
+}
+
 func (s *svc) checkChangesWorker(usersChan <-chan *domain.User) {
 	barsClient := bars.NewClient(config.BARSRegistrationPageURL)
 	for user := range usersChan {
@@ -112,12 +164,25 @@ func (s *svc) checkChangesWorker(usersChan <-chan *domain.User) {
 	}
 }
 
+// checkChanges user - снимок из общего обхода или очереди внеочередных проверок. Пока пользователь ждал,
+// его таблицу могла обновить другая проверка, поэтому сравнение идёт с таблицей, перечитанной под блокировкой
 func (s *svc) checkChanges(
 	ctx context.Context,
 	barsClient bars.Client,
 	user *domain.User,
 ) error {
-	// TODO в теории могут быть проблемы с тем, что пользователь мог разлогиниться за время обхода
+	unlock := s.userLocks.lock(user.ID)
+	defer unlock()
+
+	user, err := s.userSvc.User(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("userSvc.User: %w", err)
+	}
+	// пользователь разлогинился или отслеживание приостановили, пока он ждал проверки
+	if user == nil || user.BarsCredentials == nil || user.BarsCredentials.IsSuspended() {
+		return nil
+	}
+
 	decryptedPassword, err := aes.Decrypt([]byte(s.cfg.EncryptionKey), user.BarsCredentials.Password)
 	if err != nil {
 		return fmt.Errorf("aes.Decrypt: %w", err)
@@ -130,66 +195,69 @@ func (s *svc) checkChanges(
 		barsClient,
 	)
 	if errors.Is(err, bars.ErrAuthorizationFailed) {
-		retriesCount := s.nextRetriesCount(user.ID)
-		if retriesCount < s.cfg.AuthorizationFailedRetriesCount {
-			log.Info().
-				Int64("user", user.ID).
-				Str("reason", bars.ErrAuthorizationFailed.Error()).
-				Msgf("new retries count value <%d>", retriesCount)
-			return nil
-		}
-
-		sendMsgErr := s.telegramSvc.SendMessageWithOpts(user.ID, answers.CredentialsExpired)
-		if sendMsgErr != nil {
-			return fmt.Errorf("telegramSvc.SendMessageWithOpts(credentialsExpired): %w", err)
-		}
-
-		deleteErr := s.barsSvc.Logout(ctx, user.ID)
-		if deleteErr != nil {
-			return fmt.Errorf("barsSvc.Logout(authFailed): %w", err)
-		}
-
-		log.Info().
-			Int64("user", user.ID).
-			Msg("deleting user with err authorization failed")
-		return nil
+		return s.handleRetriableError(ctx, user.ID, bars.ErrAuthorizationFailed, answers.CredentialsExpired)
 	}
 	if errors.Is(err, ierrors.ErrWrongGradesPage) {
-		retriesCount := s.nextRetriesCount(user.ID)
-		if retriesCount < s.cfg.AuthorizationFailedRetriesCount {
-			log.Info().
-				Int64("user", user.ID).
-				Str("reason", ierrors.ErrWrongGradesPage.Error()).
-				Msgf("new retries count value <%d>", retriesCount)
-			return nil
-		}
+		return s.handleRetriableError(ctx, user.ID, ierrors.ErrWrongGradesPage, answers.GradesPageWrong)
+	}
+	if err != nil {
+		return fmt.Errorf("barsSvc.GetProgressTable: %w", err)
+	}
 
-		sendMsgErr := s.telegramSvc.SendMessageWithOpts(user.ID, answers.GradesPageWrong)
-		if sendMsgErr != nil {
-			return fmt.Errorf("telegramSvc.SendMessageWithOpts(gradesPageWrong): %w", err)
+	// счётчик сбрасывается только после неудач, чтобы не писать в базу при каждой проверке
+	if user.BarsCredentials.AuthorizationFailures > 0 {
+		err = s.authorizationFailuresSvc.Reset(ctx, user.ID)
+		if err != nil {
+			return fmt.Errorf("authorizationFailuresSvc.Reset: %w", err)
 		}
+	}
 
-		deleteErr := s.barsSvc.Logout(ctx, user.ID)
-		if deleteErr != nil {
-			return fmt.Errorf("barsSvc.Delete(wrongGradesPage): %w", err)
-		}
+	err = s.userSvc.SaveCheckedAt(ctx, user.ID, time.Now())
+	if err != nil {
+		return fmt.Errorf("userSvc.SaveCheckedAt: %w", err)
+	}
 
-		log.Info().
-			Int64("user", user.ID).
-			Msg("deleting user with wrong grades page")
-		return nil
+	err = s.checkProgressTableChanges(ctx, user, progressTable)
+	if err != nil {
+		return fmt.Errorf("checkProgressTableChanges: %w", err)
 	}
+
+	err = s.checkRecordBookChanges(ctx, barsClient, user)
 	if err != nil {
-		return fmt.Errorf("barsSvc.GetProgressTable: %w", err)
+		return fmt.Errorf("checkRecordBookChanges: %w", err)
 	}
 
+	return nil
+}
+
+func (s *svc) checkProgressTableChanges(
+	ctx context.Context,
+	user *domain.User,
+	progressTable *domain.ProgressTable,
+) error {
+	var err error
 	changes := make([]*domain.GradeChange, 0, len(progressTable.Disciplines))
 	if user.ProgressTable != nil {
+		if isSemesterChanged(user.ProgressTable, progressTable) {
+			err = s.userSvc.RolloverProgressTable(ctx, user.ID, user.ProgressTable, progressTable)
+			if err != nil {
+				return fmt.Errorf("userSvc.RolloverProgressTable: %w", err)
+			}
+
+			log.Info().
+				Int64("user", user.ID).
+				Str("old_semester", user.ProgressTable.Semester.ID).
+				Str("new_semester", progressTable.Semester.ID).
+				Msg("semester changed, progress table archived")
+			return nil
+		}
+
 		changes, err = compareProgressTables(user.ID, progressTable, user.ProgressTable)
 		if err != nil && !errors.Is(err, ierrors.ErrProgressTableStructChanged) {
 			return fmt.Errorf("compareProgressTables: %w", err)
 		}
-		if len(changes) == 0 && !errors.Is(err, ierrors.ErrProgressTableStructChanged) {
+		if len(changes) == 0 && !errors.Is(err, ierrors.ErrProgressTableStructChanged) &&
+			user.ProgressTable.Semester == progressTable.Semester {
 			return nil
 		}
 	}
@@ -202,23 +270,151 @@ func (s *svc) checkChanges(
 	return nil
 }
 
-func (s *svc) nextRetriesCount(userID int64) int {
-	// сервер барса после падений может отдавать неожидаемое поведение
-	// часто возникает, фиксим ретраями
-	retriesCount := s.retriesCountCache.Get(userID)
-	if retriesCount == nil || retriesCount.IsExpired() {
-		s.retriesCountCache.Set(userID, 1, ttlcache.DefaultTTL)
-		return 1
+// checkRecordBookChanges barsClient уже авторизован при получении таблицы успеваемости
+func (s *svc) checkRecordBookChanges(
+	ctx context.Context,
+	barsClient bars.Client,
+	user *domain.User,
+) error {
+	recordBook, err := s.barsSvc.GetRecordBook(ctx, barsClient)
+	// зачётная книжка доступна не во всех аккаунтах, это не ошибка
+	if errors.Is(err, ierrors.ErrWrongRecordBookPage) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("barsSvc.GetRecordBook: %w", err)
+	}
+
+	oldRecordBook, err := s.userSvc.RecordBook(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("userSvc.RecordBook: %w", err)
+	}
+
+	changes := make([]*domain.GradeChange, 0)
+	if oldRecordBook != nil {
+		if slices.Equal(recordBook.Entries, oldRecordBook.Entries) {
+			return nil
+		}
+		changes = compareRecordBooks(user.ID, recordBook, oldRecordBook)
+	}
+
+	err = s.userSvc.UpdateRecordBook(ctx, user.ID, recordBook, changes)
+	if err != nil {
+		return fmt.Errorf("userSvc.UpdateRecordBook: %w", err)
+	}
+
+	return nil
+}
+
+// сервер барса после падений может отдавать неожидаемое поведение
+// часто возникает, фиксим ретраями
+func (s *svc) handleRetriableError(
+	ctx context.Context,
+	userID int64,
+	reason error,
+	expiredAnswer answers.Key,
+) error {
+	language := s.language(ctx, userID)
+
+	failure, err := s.authorizationFailuresSvc.Increment(ctx, userID, reason)
+	if err != nil {
+		return fmt.Errorf("authorizationFailuresSvc.Increment: %w", err)
+	}
+
+	if failure.Count < s.cfg.AuthorizationFailedRetriesCount {
+		log.Info().
+			Int64("user", userID).
+			Str("reason", reason.Error()).
+			Msgf("new retries count value <%d>", failure.Count)
+
+		// последняя попытка перед приостановкой, предупреждаем пользователя
+		if failure.Count == s.cfg.AuthorizationFailedRetriesCount-1 {
+			sendMsgErr := s.telegramSvc.SendMessage(
+				userID,
+				domain.NewPlainMessage(answers.Textf(
+					language,
+					answers.CredentialsDeletionWarning,
+					failure.Count,
+					s.cfg.AuthorizationFailedRetriesCount,
+				)),
+			)
+			if sendMsgErr != nil {
+				return fmt.Errorf("telegramSvc.SendMessage(credentialsDeletionWarning): %w", sendMsgErr)
+			}
+		}
+
+		return nil
+	}
+
+	// данные не удаляются сразу: отслеживание приостанавливается до повторной авторизации,
+	// окончательное удаление выполняется в deleteExpiredSuspensions
+	err = s.userSvc.Suspend(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("userSvc.Suspend: %w", err)
 	}
 
-	newRetriesCount := retriesCount.Value() + 1
-	s.retriesCountCache.Set(
+	sendMsgErr := s.telegramSvc.SendReauthorizationRequest(
 		userID,
-		newRetriesCount,
-		ttlcache.DefaultTTL,
+		fmt.Sprintf(
+			"%s\n\n%s",
+			answers.Text(language, expiredAnswer),
+			answers.Textf(language, answers.CredentialsSuspended, int(s.cfg.CredentialsSuspensionPeriod.Hours()/24)),
+		),
 	)
+	if sendMsgErr != nil {
+		return fmt.Errorf("telegramSvc.SendReauthorizationRequest: %w", sendMsgErr)
+	}
+
+	log.Info().
+		Int64("user", userID).
+		Str("reason", reason.Error()).
+		Msg("suspending user after retries exhausted")
+	return nil
+}
+
+// language при ошибке возвращает язык по умолчанию: уведомление важнее перевода
+func (s *svc) language(ctx context.Context, userID int64) domain.Language {
+	language, err := s.userSettingsSvc.Language(ctx, userID)
+	if err != nil {
+		log.Error().
+			Int64("user", userID).
+			Msgf("userSettingsSvc.Language: %v", err.Error())
+	}
+
+	return language
+}
+
+func (s *svc) deleteExpiredSuspensions(ctx context.Context) {
+	suspendedBefore := time.Now().Add(-s.cfg.CredentialsSuspensionPeriod)
+	userIDs, err := s.userSvc.SuspendedUserIDs(ctx, suspendedBefore)
+	if err != nil {
+		log.Error().Msgf("deleteExpiredSuspensions: userSvc.SuspendedUserIDs: %v", err.Error())
+		return
+	}
+
+	for _, userID := range userIDs {
+		err = s.barsSvc.Logout(ctx, userID)
+		if err != nil {
+			log.Error().
+				Int64("user", userID).
+				Msgf("deleteExpiredSuspensions: barsSvc.Logout: %v", err.Error())
+			continue
+		}
+
+		log.Info().
+			Int64("user", userID).
+			Msg("deleting user after suspension period expired")
 
-	return retriesCount.Value()
+		sendMsgErr := s.telegramSvc.SendMessage(
+			userID,
+			domain.NewPlainMessage(answers.Text(s.language(ctx, userID), answers.SuspensionExpired)),
+		)
+		if sendMsgErr != nil {
+			log.Error().
+				Int64("user", userID).
+				Msgf("deleteExpiredSuspensions: telegramSvc.SendMessage: %v", sendMsgErr.Error())
+		}
+	}
 }
 
 func (s *svc) Stop() error {
@@ -230,6 +426,14 @@ func (s *svc) Stop() error {
 	return nil
 }
 
+// isSemesterChanged у таблиц, сохранённых до поддержки семестров, семестр неизвестен,
+// для них смена семестра определяется только как изменение структуры
+func isSemesterChanged(oldProgressTable, newProgressTable *domain.ProgressTable) bool {
+	return oldProgressTable.Semester.ID != "" &&
+		newProgressTable.Semester.ID != "" &&
+		oldProgressTable.Semester.ID != newProgressTable.Semester.ID
+}
+
 func compareProgressTables(
 	userID int64,
 	newProgressTable *domain.ProgressTable,
@@ -255,14 +459,15 @@ func compareProgressTables(
 				return changes, ierrors.ErrProgressTableStructChanged
 			}
 
-			if controlEvent.Grade != oldControlEvent.Grade &&
-				!strings.HasPrefix(controlEvent.Name, "Балл текущего контроля") {
+			if controlEvent.Grade.Raw != oldControlEvent.Grade.Raw &&
+				!controlEvent.IsCurrentControlScore() {
 				changes = append(changes, &domain.GradeChange{
 					UserID:       userID,
 					Discipline:   discipline.Name,
 					ControlEvent: controlEvent.Name,
-					OldGrade:     oldControlEvent.Grade,
-					NewGrade:     controlEvent.Grade,
+					OldGrade:     oldControlEvent.Grade.String(),
+					NewGrade:     controlEvent.Grade.String(),
+					Semester:     newProgressTable.Semester.Name,
 				})
 			}
 		}
@@ -270,3 +475,43 @@ func compareProgressTables(
 
 	return changes, nil
 }
+
+type recordBookEntryKey struct {
+	discipline  string
+	semester    string
+	controlForm string
+}
+
+func compareRecordBooks(
+	userID int64,
+	newRecordBook *domain.RecordBook,
+	oldRecordBook *domain.RecordBook,
+) []*domain.GradeChange {
+	oldMarks := make(map[recordBookEntryKey]string, len(oldRecordBook.Entries))
+	for _, entry := range oldRecordBook.Entries {
+		oldMarks[recordBookEntryKey{entry.Discipline, entry.Semester, entry.ControlForm}] = entry.Mark
+	}
+
+	changes := make([]*domain.GradeChange, 0)
+	for _, entry := range newRecordBook.Entries {
+		oldMark := oldMarks[recordBookEntryKey{entry.Discipline, entry.Semester, entry.ControlForm}]
+		if entry.Mark == "" || entry.Mark == oldMark {
+			continue
+		}
+		if oldMark == "" {
+			oldMark = domain.Grade{}.String()
+		}
+
+		changes = append(changes, &domain.GradeChange{
+			UserID:       userID,
+			Discipline:   entry.Discipline,
+			ControlEvent: entry.ControlForm,
+			OldGrade:     oldMark,
+			NewGrade:     entry.Mark,
+			Semester:     entry.Semester,
+			RecordBook:   true,
+		})
+	}
+
+	return changes
+}
@@ -0,0 +1,43 @@
+package grades_changes
+
+import "sync"
+
+// userLocks не даёт проверять оценки одного пользователя одновременно в общем обходе и по запросу администратора
+type userLocks struct {
+	mu    sync.Mutex
+	locks map[int64]*userLock
+}
+
+type userLock struct {
+	mu      sync.Mutex
+	waiters int
+}
+
+func newUserLocks() *userLocks {
+	return &userLocks{locks: make(map[int64]*userLock)}
+}
+
+// lock возвращает функцию, которая снимает блокировку
+func (l *userLocks) lock(userID int64) func() {
+	l.mu.Lock()
+	ul, ok := l.locks[userID]
+	if !ok {
+		ul = &userLock{}
+		l.locks[userID] = ul
+	}
+	ul.waiters++
+	l.mu.Unlock()
+
+	ul.mu.Lock()
+
+	return func() {
+		ul.mu.Unlock()
+
+		l.mu.Lock()
+		ul.waiters--
+		if ul.waiters == 0 {
+			delete(l.locks, userID)
+		}
+		l.mu.Unlock()
+	}
+}
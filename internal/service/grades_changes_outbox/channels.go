@@ -0,0 +1,227 @@
+package grades_changes_outbox
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+)
+
+// deliveryChannel способ доставки изменения оценки, состояние доставки хранится отдельно
+// для каждого адресата в канале
+type deliveryChannel interface {
+	name() domain.NotificationChannel
+	// endpoints адресаты пользователя в канале, пустой список - канал пользователю не нужен
+	endpoints(ctx context.Context, settings *domain.UserSettings) ([]string, error)
+	send(ctx context.Context, settings *domain.UserSettings, endpoint string, gradeChange *domain.GradeChange) error
+}
+
+// selectedEndpoints единственный адресат без имени, если пользователь выбрал канал в настройках
+func selectedEndpoints(settings *domain.UserSettings, channel domain.NotificationChannel) []string {
+	if !settings.HasNotificationChannel(channel) {
+		return nil
+	}
+
+	return []string{""}
+}
+
+type telegramChannel struct {
+	telegramSvc service.Telegram
+	rendererSvc service.Renderer
+}
+
+func (c *telegramChannel) name() domain.NotificationChannel {
+	return domain.NotificationChannelTelegram
+}
+
+func (c *telegramChannel) endpoints(_ context.Context, settings *domain.UserSettings) ([]string, error) {
+	return selectedEndpoints(settings, c.name()), nil
+}
+
+func (c *telegramChannel) send(
+	_ context.Context,
+	settings *domain.UserSettings,
+	_ string,
+	gradeChange *domain.GradeChange,
+) error {
+	message, err := c.rendererSvc.GradeChange(domain.MessageFormatMarkdownV2, settings.Language, gradeChange)
+	if err != nil {
+		return fmt.Errorf("rendererSvc.GradeChange: %w", err)
+	}
+
+	err = c.telegramSvc.SendMessage(gradeChange.UserID, message)
+	if err != nil {
+		return fmt.Errorf("telegramSvc.SendMessage: %w", err)
+	}
+
+	return nil
+}
+
+type emailChannel struct {
+	emailSvc    service.Email
+	rendererSvc service.Renderer
+}
+
+func (c *emailChannel) name() domain.NotificationChannel {
+	return domain.NotificationChannelEmail
+}
+
+// endpoints с неподтверждённым адресом письма не отправляются, а изменение не ждёт подтверждения в outbox
+func (c *emailChannel) endpoints(_ context.Context, settings *domain.UserSettings) ([]string, error) {
+	if !settings.IsEmailVerified() {
+		return nil, nil
+	}
+
+	return selectedEndpoints(settings, c.name()), nil
+}
+
+func (c *emailChannel) send(
+	ctx context.Context,
+	settings *domain.UserSettings,
+	_ string,
+	gradeChange *domain.GradeChange,
+) error {
+	message, err := c.rendererSvc.GradeChange(domain.MessageFormatHTML, settings.Language, gradeChange)
+	if err != nil {
+		return fmt.Errorf("rendererSvc.GradeChange: %w", err)
+	}
+
+	subject := answers.Textf(settings.Language, answers.EmailGradeChangeSubject, gradeChange.Discipline)
+	err = c.emailSvc.Send(ctx, settings.Email, subject, message)
+	if err != nil {
+		return fmt.Errorf("emailSvc.Send: %w", err)
+	}
+
+	return nil
+}
+
+type webhookChannel struct {
+	webhooksSvc service.Webhooks
+}
+
+func (c *webhookChannel) name() domain.NotificationChannel {
+	return domain.NotificationChannelWebhook
+}
+
+func (c *webhookChannel) endpoints(ctx context.Context, settings *domain.UserSettings) ([]string, error) {
+	webhooks, err := c.webhooksSvc.Webhooks(ctx, settings.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("webhooksSvc.Webhooks: %w", err)
+	}
+
+	endpoints := make([]string, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		endpoints = append(endpoints, strconv.FormatInt(webhook.ID, 10))
+	}
+
+	return endpoints, nil
+}
+
+func (c *webhookChannel) send(
+	ctx context.Context,
+	_ *domain.UserSettings,
+	endpoint string,
+	gradeChange *domain.GradeChange,
+) error {
+	webhookID, err := strconv.ParseInt(endpoint, 10, 64)
+	if err != nil {
+		return fmt.Errorf("strconv.ParseInt: %w", err)
+	}
+
+	err = c.webhooksSvc.SendGradeChange(ctx, webhookID, gradeChange)
+	if err != nil {
+		return fmt.Errorf("webhooksSvc.SendGradeChange: %w", err)
+	}
+
+	return nil
+}
+
+// groupChatChannel пишет в групповые чаты, куда вступил пользователь, что по мероприятию выставлены оценки.
+// Ни оценки, ни того, чья она, в сообщении нет, а об одном мероприятии чат узнаёт один раз
+type groupChatChannel struct {
+	chatsSvc    service.Chats
+	telegramSvc service.Telegram
+	rendererSvc service.Renderer
+}
+
+func (c *groupChatChannel) name() domain.NotificationChannel {
+	return domain.NotificationChannelGroupChat
+}
+
+func (c *groupChatChannel) endpoints(ctx context.Context, settings *domain.UserSettings) ([]string, error) {
+	chatIDs, err := c.chatsSvc.MemberChatIDs(ctx, settings.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("chatsSvc.MemberChatIDs: %w", err)
+	}
+
+	endpoints := make([]string, 0, len(chatIDs))
+	for _, chatID := range chatIDs {
+		endpoints = append(endpoints, strconv.FormatInt(chatID, 10))
+	}
+
+	return endpoints, nil
+}
+
+func (c *groupChatChannel) send(
+	ctx context.Context,
+	_ *domain.UserSettings,
+	endpoint string,
+	gradeChange *domain.GradeChange,
+) error {
+	if !gradeChange.IsPublication() {
+		return nil
+	}
+
+	chatID, err := strconv.ParseInt(endpoint, 10, 64)
+	if err != nil {
+		return fmt.Errorf("strconv.ParseInt: %w", err)
+	}
+
+	key := announcementKey(gradeChange)
+	isAnnounced, err := c.chatsSvc.IsAnnounced(ctx, chatID, key)
+	if err != nil {
+		return fmt.Errorf("chatsSvc.IsAnnounced: %w", err)
+	}
+	if isAnnounced {
+		return nil
+	}
+
+	chat, err := c.chatsSvc.Chat(ctx, chatID)
+	if err != nil {
+		return fmt.Errorf("chatsSvc.Chat: %w", err)
+	}
+	// бота убрали из чата, пока изменение ждало доставки
+	if chat == nil {
+		return nil
+	}
+
+	message, err := c.rendererSvc.GradePublished(domain.MessageFormatMarkdownV2, chat.Language, gradeChange)
+	if err != nil {
+		return fmt.Errorf("rendererSvc.GradePublished: %w", err)
+	}
+
+	err = c.telegramSvc.SendChatMessage(chatID, message)
+	if err != nil {
+		return fmt.Errorf("telegramSvc.SendChatMessage: %w", err)
+	}
+
+	err = c.chatsSvc.MarkAnnounced(ctx, chatID, key)
+	if err != nil {
+		return fmt.Errorf("chatsSvc.MarkAnnounced: %w", err)
+	}
+
+	return nil
+}
+
+// announcementKey одноимённые мероприятия разных семестров объявляются отдельно
+func announcementKey(gradeChange *domain.GradeChange) string {
+	hash := sha256.Sum256([]byte(
+		gradeChange.Discipline + "\x00" + gradeChange.ControlEvent + "\x00" + gradeChange.Semester,
+	))
+	return hex.EncodeToString(hash[:16])
+}
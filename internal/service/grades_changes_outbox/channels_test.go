@@ -0,0 +1,97 @@
+package grades_changes_outbox
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/renderer"
+)
+
+type chatsService = service.Chats
+
+// memoryChats чаты, где бот состоит, и отметки об уже отправленных сообщениях
+type memoryChats struct {
+	chatsService
+	chats     map[int64]*domain.Chat
+	announced map[int64]map[string]bool
+}
+
+func (s *memoryChats) Chat(_ context.Context, chatID int64) (*domain.Chat, error) {
+	return s.chats[chatID], nil
+}
+
+func (s *memoryChats) IsAnnounced(_ context.Context, chatID int64, key string) (bool, error) {
+	return s.announced[chatID][key], nil
+}
+
+func (s *memoryChats) MarkAnnounced(_ context.Context, chatID int64, key string) error {
+	if s.announced[chatID] == nil {
+		s.announced[chatID] = make(map[string]bool)
+	}
+	s.announced[chatID][key] = true
+	return nil
+}
+
+type telegramService = service.Telegram
+
+type chatMessagesTelegram struct {
+	telegramService
+	sent map[int64][]string
+}
+
+func (s *chatMessagesTelegram) SendChatMessage(chatID int64, message *domain.Message) error {
+	s.sent[chatID] = append(s.sent[chatID], message.Text)
+	return nil
+}
+
+func TestGroupChatChannelAnnouncesPublicationOnce(t *testing.T) {
+	const chatID = -100
+
+	rendererSvc, err := renderer.NewService(config.Render{})
+	if err != nil {
+		t.Fatalf("renderer.NewService: %v", err)
+	}
+	chatsSvc := &memoryChats{
+		chats:     map[int64]*domain.Chat{chatID: {ID: chatID, Language: domain.LanguageRussian}},
+		announced: make(map[int64]map[string]bool),
+	}
+	telegramSvc := &chatMessagesTelegram{sent: make(map[int64][]string)}
+	channel := &groupChatChannel{
+		chatsSvc:    chatsSvc,
+		telegramSvc: telegramSvc,
+		rendererSvc: rendererSvc,
+	}
+
+	endpoint := strconv.FormatInt(chatID, 10)
+	gradesChanges := []*domain.GradeChange{
+		// исправление уже выставленной оценки не объявляется
+		{UserID: 1, Discipline: "Физика", ControlEvent: "КМ-1", OldGrade: "4", NewGrade: "5", Semester: "1"},
+		{UserID: 1, Discipline: "Физика", ControlEvent: "КМ-2", OldGrade: "", NewGrade: "4", Semester: "1"},
+		// одногруппнику выставили оценку за то же мероприятие
+		{UserID: 2, Discipline: "Физика", ControlEvent: "КМ-2", OldGrade: "", NewGrade: "3", Semester: "1"},
+	}
+	for _, gradeChange := range gradesChanges {
+		err = channel.send(context.Background(), nil, endpoint, gradeChange)
+		if err != nil {
+			t.Fatalf("channel.send: %v", err)
+		}
+	}
+
+	sent := telegramSvc.sent[chatID]
+	if len(sent) != 1 {
+		t.Fatalf("sent %d messages, want 1: %q", len(sent), sent)
+	}
+	if !strings.Contains(sent[0], "КМ\\-2") {
+		t.Errorf("message %q does not name the control event", sent[0])
+	}
+	for _, grade := range []string{"4", "3"} {
+		if strings.Contains(sent[0], grade) {
+			t.Errorf("message %q reveals grade %s", sent[0], grade)
+		}
+	}
+}
@@ -7,17 +7,18 @@ import (
 	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service"
 	"github.com/rs/zerolog/log"
-	"gopkg.in/telebot.v3"
 )
 
 const defaultGradesChangesLimit = 50
 
 type svc struct {
 	gradesChangesOutboxRepo repository.GradesChangesOutbox
-	telegramSvc             service.Telegram
+	userSettingsSvc         service.UserSettings
+	channels                []deliveryChannel
 	cfg                     config.Bars
 	stopFunc                func()
 }
@@ -25,12 +26,23 @@ type svc struct {
 func NewService(
 	gradesChangesOutboxRepo repository.GradesChangesOutbox,
 	telegramSvc service.Telegram,
+	emailSvc service.Email,
+	webhooksSvc service.Webhooks,
+	chatsSvc service.Chats,
+	userSettingsSvc service.UserSettings,
+	rendererSvc service.Renderer,
 	cfg config.Bars,
 ) *svc {
 	return &svc{
 		gradesChangesOutboxRepo: gradesChangesOutboxRepo,
-		telegramSvc:             telegramSvc,
-		cfg:                     cfg,
+		userSettingsSvc:         userSettingsSvc,
+		channels: []deliveryChannel{
+			&telegramChannel{telegramSvc: telegramSvc, rendererSvc: rendererSvc},
+			&emailChannel{emailSvc: emailSvc, rendererSvc: rendererSvc},
+			&webhookChannel{webhooksSvc: webhooksSvc},
+			&groupChatChannel{chatsSvc: chatsSvc, telegramSvc: telegramSvc, rendererSvc: rendererSvc},
+		},
+		cfg: cfg,
 	}
 }
 
@@ -51,33 +63,102 @@ func (s *svc) Start() {
 	}
 }
 
-// TODO может произойти зацикливание на какой-то ошибке и отправка не выполнится никогда
+type deliveryKey struct {
+	channel  domain.NotificationChannel
+	endpoint string
+}
+
+// sendGradesChanges изменение удаляется из outbox, когда каждому адресату пользователя оно доставлено
+// или число неудач достигло cfg.OutboxMaxDeliveryFailures. Между неудачными попытками выдерживается
+// растущая пауза, см. domain.NotificationDelivery.RetryDelay. Пока она идёт, изменение откладывается
+// целиком и не занимает место в выборке, иначе ждущие изменения задерживали бы доставку новых
 func (s *svc) sendGradesChanges(ctx context.Context) error {
-	gradesChanges, err := s.gradesChangesOutboxRepo.GradesChanges(ctx, defaultGradesChangesLimit)
+	gradesChanges, err := s.gradesChangesOutboxRepo.GradesChanges(ctx, time.Now(), defaultGradesChangesLimit)
 	if err != nil {
 		return fmt.Errorf("gradesChangesOutboxRepo.GradesChanges: %w", err)
 	}
+	if len(gradesChanges) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(gradesChanges))
+	for _, gradeChange := range gradesChanges {
+		ids = append(ids, gradeChange.ID)
+	}
+
+	deliveriesList, err := s.gradesChangesOutboxRepo.Deliveries(ctx, ids)
+	if err != nil {
+		return fmt.Errorf("gradesChangesOutboxRepo.Deliveries: %w", err)
+	}
+	deliveries := make(map[int64]map[deliveryKey]*domain.NotificationDelivery, len(gradesChanges))
+	for _, delivery := range deliveriesList {
+		if deliveries[delivery.GradeChangeID] == nil {
+			deliveries[delivery.GradeChangeID] = make(map[deliveryKey]*domain.NotificationDelivery)
+		}
+		deliveries[delivery.GradeChangeID][deliveryKey{channel: delivery.Channel, endpoint: delivery.Endpoint}] = delivery
+	}
 
-	successfulSendingIDs := make([]int64, 0, len(gradesChanges))
+	completedIDs := make([]int64, 0, len(gradesChanges))
+	usersSettings := make(map[int64]*domain.UserSettings)
 	for _, gradeChange := range gradesChanges {
-		sendMsgErr := s.telegramSvc.SendMessageWithOpts(
-			gradeChange.UserID,
-			gradeChange.String(),
-			// TODO от зависимости телебота нужно избавиться
-			telebot.ModeMarkdown,
-		)
-		if sendMsgErr != nil {
-			log.Error().
-				Int64("user", gradeChange.UserID).
-				Msgf("sending grade change <id: %d> failed: %v", gradeChange.ID, sendMsgErr)
+		settings, ok := usersSettings[gradeChange.UserID]
+		if !ok {
+			settings = s.userSettings(ctx, gradeChange.UserID)
+			usersSettings[gradeChange.UserID] = settings
+		}
+
+		completed := true
+		var nextAttemptAt time.Time
+		postpone := func(at time.Time) {
+			completed = false
+			if nextAttemptAt.IsZero() || at.Before(nextAttemptAt) {
+				nextAttemptAt = at
+			}
+		}
+		for _, channel := range s.channels {
+			endpoints, endpointsErr := channel.endpoints(ctx, settings)
+			if endpointsErr != nil {
+				log.Error().
+					Int64("user", gradeChange.UserID).
+					Str("channel", string(channel.name())).
+					Msgf("channel.endpoints: %v", endpointsErr)
+				postpone(time.Now().Add(s.cfg.OutboxRetryBackoff))
+				continue
+			}
+
+			for _, endpoint := range endpoints {
+				delivery, ok := deliveries[gradeChange.ID][deliveryKey{channel: channel.name(), endpoint: endpoint}]
+				if !ok {
+					delivery = &domain.NotificationDelivery{
+						GradeChangeID: gradeChange.ID,
+						Channel:       channel.name(),
+						Endpoint:      endpoint,
+					}
+				}
+
+				delivered, err := s.deliver(ctx, channel, settings, delivery, gradeChange)
+				if err != nil {
+					return err
+				}
+				if !delivered {
+					postpone(delivery.NextAttemptAt)
+				}
+			}
+		}
+
+		if completed {
+			completedIDs = append(completedIDs, gradeChange.ID)
 			continue
 		}
 
-		successfulSendingIDs = append(successfulSendingIDs, gradeChange.ID)
+		err = s.gradesChangesOutboxRepo.Postpone(ctx, gradeChange.ID, nextAttemptAt)
+		if err != nil {
+			return fmt.Errorf("gradesChangesOutboxRepo.Postpone: %w", err)
+		}
 	}
 
-	if len(successfulSendingIDs) != 0 {
-		err = s.gradesChangesOutboxRepo.Delete(ctx, successfulSendingIDs)
+	if len(completedIDs) != 0 {
+		err = s.gradesChangesOutboxRepo.Delete(ctx, completedIDs)
 		if err != nil {
 			return fmt.Errorf("gradesChangesOutboxRepo.Delete: %w", err)
 		}
@@ -86,6 +167,62 @@ func (s *svc) sendGradesChanges(ctx context.Context) error {
 	return nil
 }
 
+// deliver возвращает false, если изменение ещё нужно попытаться доставить этому адресату не раньше
+// delivery.NextAttemptAt
+func (s *svc) deliver(
+	ctx context.Context,
+	channel deliveryChannel,
+	settings *domain.UserSettings,
+	delivery *domain.NotificationDelivery,
+	gradeChange *domain.GradeChange,
+) (bool, error) {
+	if delivery.IsDelivered() || delivery.FailuresCount >= s.cfg.OutboxMaxDeliveryFailures {
+		return true, nil
+	}
+	if time.Now().Before(delivery.NextAttemptAt) {
+		return false, nil
+	}
+
+	sendErr := channel.send(ctx, settings, delivery.Endpoint, gradeChange)
+	if sendErr != nil {
+		log.Error().
+			Int64("user", gradeChange.UserID).
+			Str("channel", string(channel.name())).
+			Str("endpoint", delivery.Endpoint).
+			Msgf("sending grade change <id: %d> failed: %v", gradeChange.ID, sendErr)
+
+		delivery.FailuresCount++
+		delivery.LastError = sendErr.Error()
+		delivery.NextAttemptAt = time.Now().Add(delivery.RetryDelay(s.cfg.OutboxRetryBackoff, s.cfg.OutboxMaxRetryBackoff))
+	} else {
+		now := time.Now()
+		delivery.DeliveredAt = &now
+		delivery.LastError = ""
+	}
+
+	err := s.gradesChangesOutboxRepo.SaveDelivery(ctx, delivery)
+	if err != nil {
+		return false, fmt.Errorf("gradesChangesOutboxRepo.SaveDelivery: %w", err)
+	}
+
+	return sendErr == nil || delivery.FailuresCount >= s.cfg.OutboxMaxDeliveryFailures, nil
+}
+
+// userSettings изменение лучше доставить в Telegram на языке по умолчанию, чем задержать
+func (s *svc) userSettings(ctx context.Context, userID int64) *domain.UserSettings {
+	settings, err := s.userSettingsSvc.UserSettings(ctx, userID)
+	if err != nil {
+		log.Error().
+			Int64("user", userID).
+			Msgf("userSettingsSvc.UserSettings: %v", err)
+	}
+	if settings == nil {
+		settings = domain.NewUserSettings(userID, domain.DefaultLanguage)
+	}
+
+	return settings
+}
+
 func (s *svc) Stop() error {
 	if s.stopFunc == nil {
 		return errors.New("service is not started")
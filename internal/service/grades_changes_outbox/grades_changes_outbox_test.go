@@ -0,0 +1,172 @@
+package grades_changes_outbox
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+)
+
+// memoryOutbox повторяет контракт repository.GradesChangesOutbox: GradesChanges отдаёт только изменения,
+// для которых подошло время следующей попытки, в порядке добавления
+type memoryOutbox struct {
+	gradesChanges map[int64]*domain.GradeChange
+	nextAttemptAt map[int64]time.Time
+	deliveries    map[int64][]*domain.NotificationDelivery
+}
+
+func newMemoryOutbox() *memoryOutbox {
+	return &memoryOutbox{
+		gradesChanges: make(map[int64]*domain.GradeChange),
+		nextAttemptAt: make(map[int64]time.Time),
+		deliveries:    make(map[int64][]*domain.NotificationDelivery),
+	}
+}
+
+func (r *memoryOutbox) add(gradeChange *domain.GradeChange) {
+	r.gradesChanges[gradeChange.ID] = gradeChange
+}
+
+func (r *memoryOutbox) GradesChanges(_ context.Context, now time.Time, limit int64) ([]*domain.GradeChange, error) {
+	gradesChanges := make([]*domain.GradeChange, 0)
+	for id, gradeChange := range r.gradesChanges {
+		if !r.nextAttemptAt[id].After(now) {
+			gradesChanges = append(gradesChanges, gradeChange)
+		}
+	}
+	sort.Slice(gradesChanges, func(i, j int) bool {
+		return gradesChanges[i].ID < gradesChanges[j].ID
+	})
+	if int64(len(gradesChanges)) > limit {
+		gradesChanges = gradesChanges[:limit]
+	}
+
+	return gradesChanges, nil
+}
+
+func (r *memoryOutbox) Postpone(_ context.Context, id int64, nextAttemptAt time.Time) error {
+	r.nextAttemptAt[id] = nextAttemptAt
+	return nil
+}
+
+func (r *memoryOutbox) Deliveries(_ context.Context, ids []int64) ([]*domain.NotificationDelivery, error) {
+	deliveries := make([]*domain.NotificationDelivery, 0)
+	for _, id := range ids {
+		deliveries = append(deliveries, r.deliveries[id]...)
+	}
+
+	return deliveries, nil
+}
+
+func (r *memoryOutbox) SaveDelivery(_ context.Context, delivery *domain.NotificationDelivery) error {
+	saved := *delivery
+	for i, d := range r.deliveries[delivery.GradeChangeID] {
+		if d.Channel == delivery.Channel && d.Endpoint == delivery.Endpoint {
+			r.deliveries[delivery.GradeChangeID][i] = &saved
+			return nil
+		}
+	}
+	r.deliveries[delivery.GradeChangeID] = append(r.deliveries[delivery.GradeChangeID], &saved)
+
+	return nil
+}
+
+func (r *memoryOutbox) Delete(_ context.Context, ids []int64) error {
+	for _, id := range ids {
+		delete(r.gradesChanges, id)
+		delete(r.nextAttemptAt, id)
+		delete(r.deliveries, id)
+	}
+
+	return nil
+}
+
+// unavailableChannel не может доставить изменения пользователя unavailableUserID, например из-за мёртвого вебхука
+type unavailableChannel struct {
+	unavailableUserID int64
+	sent              []int64
+}
+
+func (c *unavailableChannel) name() domain.NotificationChannel {
+	return domain.NotificationChannelWebhook
+}
+
+func (c *unavailableChannel) endpoints(_ context.Context, _ *domain.UserSettings) ([]string, error) {
+	return []string{"1"}, nil
+}
+
+func (c *unavailableChannel) send(
+	_ context.Context,
+	_ *domain.UserSettings,
+	_ string,
+	gradeChange *domain.GradeChange,
+) error {
+	if gradeChange.UserID == c.unavailableUserID {
+		return errors.New("connection refused")
+	}
+
+	c.sent = append(c.sent, gradeChange.ID)
+	return nil
+}
+
+type userSettingsService = service.UserSettings
+
+// defaultUserSettings у пользователей нет сохранённых настроек, остальные методы не вызываются
+type defaultUserSettings struct {
+	userSettingsService
+}
+
+func (defaultUserSettings) UserSettings(_ context.Context, _ int64) (*domain.UserSettings, error) {
+	return nil, nil
+}
+
+func TestSendGradesChangesBackingOffDoesNotStarveFreshChanges(t *testing.T) {
+	const unavailableUserID = 1
+
+	outbox := newMemoryOutbox()
+	channel := &unavailableChannel{unavailableUserID: unavailableUserID}
+	s := &svc{
+		gradesChangesOutboxRepo: outbox,
+		userSettingsSvc:         defaultUserSettings{},
+		channels:                []deliveryChannel{channel},
+		cfg: config.Bars{
+			OutboxMaxDeliveryFailures: 10,
+			OutboxRetryBackoff:        time.Minute,
+			OutboxMaxRetryBackoff:     6 * time.Hour,
+		},
+	}
+
+	ctx := context.Background()
+	for id := int64(1); id <= defaultGradesChangesLimit; id++ {
+		outbox.add(&domain.GradeChange{ID: id, UserID: unavailableUserID})
+	}
+	if err := s.sendGradesChanges(ctx); err != nil {
+		t.Fatalf("sendGradesChanges: %v", err)
+	}
+
+	freshID := int64(defaultGradesChangesLimit + 1)
+	outbox.add(&domain.GradeChange{ID: freshID, UserID: unavailableUserID + 1})
+	if err := s.sendGradesChanges(ctx); err != nil {
+		t.Fatalf("sendGradesChanges: %v", err)
+	}
+
+	if len(channel.sent) != 1 || channel.sent[0] != freshID {
+		t.Fatalf("sent %v, want only the fresh change %d", channel.sent, freshID)
+	}
+	if _, ok := outbox.gradesChanges[freshID]; ok {
+		t.Errorf("delivered change %d is still in the outbox", freshID)
+	}
+	for id := int64(1); id <= defaultGradesChangesLimit; id++ {
+		if !outbox.nextAttemptAt[id].After(time.Now()) {
+			t.Errorf("failed change %d is not postponed", id)
+		}
+		if deliveries := outbox.deliveries[id]; len(deliveries) != 1 || deliveries[0].FailuresCount != 1 {
+			t.Errorf("failed change %d was retried during backoff: %+v", id, deliveries)
+		}
+	}
+}
@@ -0,0 +1,12 @@
+package service
+
+import "context"
+
+type GradesFeed interface {
+	// FeedURL секретный адрес Atom-ленты изменений оценок, при первом обращении лента создаётся
+	FeedURL(ctx context.Context, userID int64) (string, error)
+	// RotateFeedURL выпускает новый адрес, старый перестаёт работать
+	RotateFeedURL(ctx context.Context, userID int64) (string, error)
+	// Feed лента по токену из адреса или ErrFeedNotFound
+	Feed(ctx context.Context, token string) ([]byte, error)
+}
@@ -0,0 +1,140 @@
+package grades_feed
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/atom"
+)
+
+const (
+	// feedPath путь ленты в API, за ним следуют токен и расширение .atom
+	feedPath = "/v1/feed/"
+
+	feedAuthor = "tracking-bars"
+)
+
+type svc struct {
+	feedTokensSvc   service.FeedTokens
+	userSvc         service.User
+	userSettingsSvc service.UserSettings
+	rendererSvc     service.Renderer
+	publicURL       string
+	cfg             config.GradesFeed
+}
+
+func NewService(
+	feedTokensSvc service.FeedTokens,
+	userSvc service.User,
+	userSettingsSvc service.UserSettings,
+	rendererSvc service.Renderer,
+	publicURL string,
+	cfg config.GradesFeed,
+) *svc {
+	return &svc{
+		feedTokensSvc:   feedTokensSvc,
+		userSvc:         userSvc,
+		userSettingsSvc: userSettingsSvc,
+		rendererSvc:     rendererSvc,
+		publicURL:       publicURL,
+		cfg:             cfg,
+	}
+}
+
+func (s *svc) FeedURL(ctx context.Context, userID int64) (string, error) {
+	token, err := s.feedTokensSvc.Token(ctx, userID, domain.FeedKindGrades)
+	if err != nil {
+		return "", fmt.Errorf("feedTokensSvc.Token: %w", err)
+	}
+
+	return s.feedURL(token), nil
+}
+
+func (s *svc) RotateFeedURL(ctx context.Context, userID int64) (string, error) {
+	token, err := s.feedTokensSvc.Rotate(ctx, userID, domain.FeedKindGrades)
+	if err != nil {
+		return "", fmt.Errorf("feedTokensSvc.Rotate: %w", err)
+	}
+
+	return s.feedURL(token), nil
+}
+
+func (s *svc) Feed(ctx context.Context, token string) ([]byte, error) {
+	feed, err := s.feedTokensSvc.FeedToken(ctx, domain.FeedKindGrades, token)
+	if err != nil {
+		return nil, fmt.Errorf("feedTokensSvc.FeedToken: %w", err)
+	}
+
+	gradesChanges, err := s.userSvc.GradesHistory(ctx, feed.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.GradesHistory: %w", err)
+	}
+
+	language, err := s.userSettingsSvc.Language(ctx, feed.UserID)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsSvc.Language: %w", err)
+	}
+
+	// история отсортирована по возрастанию, а в ленте первыми идут свежие изменения
+	if len(gradesChanges) > s.cfg.MaxEntries {
+		gradesChanges = gradesChanges[len(gradesChanges)-s.cfg.MaxEntries:]
+	}
+
+	atomFeed := &atom.Feed{
+		ID:      feedID(feed.UserID),
+		Title:   answers.Text(language, answers.GradesFeedTitle),
+		Author:  feedAuthor,
+		SelfURL: s.feedURL(token),
+		Updated: feed.CreatedAt,
+		Entries: make([]atom.Entry, 0, len(gradesChanges)),
+	}
+	for i := len(gradesChanges) - 1; i >= 0; i-- {
+		gradeChange := gradesChanges[i]
+
+		message, err := s.rendererSvc.GradeChange(domain.MessageFormatHTML, language, gradeChange)
+		if err != nil {
+			return nil, fmt.Errorf("rendererSvc.GradeChange: %w", err)
+		}
+
+		atomFeed.Entries = append(atomFeed.Entries, atom.Entry{
+			ID: "urn:tracking-bars:grade-change:" + strconv.FormatInt(gradeChange.ID, 10),
+			Title: answers.Textf(
+				language,
+				answers.GradesFeedEntryTitle,
+				gradeChange.Discipline,
+				answers.ControlEvent(language, gradeChange),
+			),
+			Updated:    gradeChange.CreatedAt,
+			Categories: []string{gradeChange.Discipline},
+			HTML:       message.Text,
+		})
+		if gradeChange.CreatedAt.After(atomFeed.Updated) {
+			atomFeed.Updated = gradeChange.CreatedAt
+		}
+	}
+
+	content, err := atomFeed.Marshal()
+	if err != nil {
+		return nil, fmt.Errorf("atomFeed.Marshal: %w", err)
+	}
+
+	return content, nil
+}
+
+func (s *svc) feedURL(token string) string {
+	return strings.TrimSuffix(s.publicURL, "/") + feedPath + token + ".atom"
+}
+
+// feedID не зависит от токена, чтобы после смены адреса читалка не считала ленту новой
+func feedID(userID int64) string {
+	hash := sha256.Sum256([]byte("grades-feed:" + strconv.FormatInt(userID, 10)))
+	return "urn:tracking-bars:grades-feed:" + hex.EncodeToString(hash[:16])
+}
@@ -0,0 +1,6 @@
+package service
+
+type Reminders interface {
+	Start()
+	Stop() error
+}
@@ -0,0 +1,248 @@
+package reminders
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+const keyDateLayout = "2006-01-02"
+
+type svc struct {
+	remindersRepo repository.Reminders
+	telegramSvc   service.Telegram
+	rendererSvc   service.Renderer
+	autumnStart   time.Time
+	springStart   time.Time
+	cfg           config.Reminders
+	stopFunc      func()
+}
+
+func NewService(
+	remindersRepo repository.Reminders,
+	telegramSvc service.Telegram,
+	rendererSvc service.Renderer,
+	calendarCfg config.Calendar,
+	cfg config.Reminders,
+) (*svc, error) {
+	autumnStart, err := time.Parse(domain.SemesterStartLayout, calendarCfg.AutumnSemesterStart)
+	if err != nil {
+		return nil, fmt.Errorf("time.Parse (autumn semester start): %w", err)
+	}
+	springStart, err := time.Parse(domain.SemesterStartLayout, calendarCfg.SpringSemesterStart)
+	if err != nil {
+		return nil, fmt.Errorf("time.Parse (spring semester start): %w", err)
+	}
+
+	return &svc{
+		remindersRepo: remindersRepo,
+		telegramSvc:   telegramSvc,
+		rendererSvc:   rendererSvc,
+		autumnStart:   autumnStart,
+		springStart:   springStart,
+		cfg:           cfg,
+	}, nil
+}
+
+func (s *svc) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	s.stopFunc = cancel
+
+	for {
+		select {
+		case <-time.After(s.cfg.CronDelay):
+			log.Info().Msg("sending reminders")
+			if err := s.sendReminders(ctx); err != nil {
+				log.Error().Msgf("sendReminders: %v", err.Error())
+			}
+		case <-ctx.Done():
+			return
+		}
+	}
+}
+
+// sendReminders план мероприятий берётся из сохранённых таблиц успеваемости, поэтому БАРС здесь не опрашивается.
+// Каждое напоминание отмечается в sent_reminders после отправки: неудачная отправка повторится на следующем запуске
+func (s *svc) sendReminders(ctx context.Context) error {
+	now := time.Now().In(config.BARSLocation)
+	if now.Hour() < s.cfg.SendHour {
+		return nil
+	}
+
+	err := s.remindersRepo.DeleteSentBefore(ctx, now.Add(-s.cfg.SentRetention))
+	if err != nil {
+		return fmt.Errorf("remindersRepo.DeleteSentBefore: %w", err)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	recipients, err := s.remindersRepo.Recipients(ctx, domain.WeeklySummaryKey(today))
+	if err != nil {
+		return fmt.Errorf("remindersRepo.Recipients: %w", err)
+	}
+
+	semesterStart := domain.SemesterStart(now, s.autumnStart, s.springStart)
+	for _, recipient := range recipients {
+		logger := log.With().Int64("user", recipient.Settings.UserID).Logger()
+		err = s.sendUserReminders(logger.WithContext(ctx), recipient, today, semesterStart)
+		if err != nil {
+			logger.Error().Msgf("sendUserReminders: %v", err.Error())
+		}
+	}
+
+	return nil
+}
+
+func (s *svc) sendUserReminders(
+	ctx context.Context,
+	recipient *domain.ReminderRecipient,
+	today time.Time,
+	semesterStart time.Time,
+) error {
+	settings := recipient.Settings
+
+	ungradedEvents := make([]domain.PlannedControlEvent, 0)
+	for _, event := range domain.PlannedControlEvents(recipient.ProgressTable, semesterStart) {
+		if !event.ControlEvent.Grade.IsGraded() {
+			ungradedEvents = append(ungradedEvents, event)
+		}
+	}
+
+	var errs []error
+	// сводка уходит при первом запуске на неделе, даже если в понедельник бот не работал
+	if settings.WeeklySummary && !recipient.WeeklySummarySent {
+		err := s.sendWeeklySummary(ctx, settings, today, ungradedEvents)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("sendWeeklySummary: %w", err))
+		}
+	}
+
+	if settings.ReminderDaysBefore > 0 {
+		for _, event := range ungradedEvents {
+			remindFrom := event.Start.AddDate(0, 0, -settings.ReminderDaysBefore)
+			if today.Before(remindFrom) || !today.Before(event.Start) {
+				continue
+			}
+
+			err := s.sendControlEventReminder(ctx, settings, event)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("sendControlEventReminder: %w", err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// sendWeeklySummary в сводку попадают мероприятия недели, в которую входит today, срок которых ещё не прошёл
+func (s *svc) sendWeeklySummary(
+	ctx context.Context,
+	settings *domain.UserSettings,
+	today time.Time,
+	events []domain.PlannedControlEvent,
+) error {
+	weekStart := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+	weekEnd := weekStart.AddDate(0, 0, 7)
+	weekEvents := make([]domain.PlannedControlEvent, 0)
+	for _, event := range events {
+		if event.Start.Before(weekEnd) && event.End.After(today) {
+			weekEvents = append(weekEvents, event)
+		}
+	}
+	if len(weekEvents) == 0 {
+		return nil
+	}
+
+	message, err := s.rendererSvc.WeeklySummary(domain.MessageFormatMarkdownV2, settings.Language, weekStart, weekEvents)
+	if err != nil {
+		return fmt.Errorf("rendererSvc.WeeklySummary: %w", err)
+	}
+
+	return s.send(ctx, settings.UserID, domain.ReminderKindWeeklySummary, domain.WeeklySummaryKey(today), message)
+}
+
+func (s *svc) sendControlEventReminder(
+	ctx context.Context,
+	settings *domain.UserSettings,
+	event domain.PlannedControlEvent,
+) error {
+	render := func() (*domain.Message, error) {
+		return s.rendererSvc.ControlEventReminder(domain.MessageFormatMarkdownV2, settings.Language, event)
+	}
+
+	return s.sendOnce(ctx, settings.UserID, domain.ReminderKindControlEvent, controlEventKey(event), render)
+}
+
+func (s *svc) sendOnce(
+	ctx context.Context,
+	userID int64,
+	kind domain.ReminderKind,
+	key string,
+	render func() (*domain.Message, error),
+) error {
+	isSent, err := s.remindersRepo.IsSent(ctx, userID, kind, key)
+	if err != nil {
+		return fmt.Errorf("remindersRepo.IsSent: %w", err)
+	}
+	if isSent {
+		return nil
+	}
+
+	message, err := render()
+	if err != nil {
+		return fmt.Errorf("render: %w", err)
+	}
+
+	return s.send(ctx, userID, kind, key, message)
+}
+
+// send отмечает напоминание отправленным, только если телеграм принял сообщение
+func (s *svc) send(
+	ctx context.Context,
+	userID int64,
+	kind domain.ReminderKind,
+	key string,
+	message *domain.Message,
+) error {
+	err := s.telegramSvc.SendMessage(userID, message)
+	if err != nil {
+		return fmt.Errorf("telegramSvc.SendMessage: %w", err)
+	}
+
+	err = s.remindersRepo.SaveSent(ctx, &domain.SentReminder{
+		UserID: userID,
+		Kind:   kind,
+		Key:    key,
+		SentAt: time.Now(),
+	})
+	if err != nil {
+		return fmt.Errorf("remindersRepo.SaveSent: %w", err)
+	}
+
+	return nil
+}
+
+// controlEventKey при переносе срока в БАРС ключ меняется, и о мероприятии напомнят ещё раз
+func controlEventKey(event domain.PlannedControlEvent) string {
+	hash := sha256.Sum256([]byte(
+		event.Discipline + "\x00" + event.ControlEvent.Name + "\x00" + event.Start.Format(keyDateLayout),
+	))
+	return hex.EncodeToString(hash[:16])
+}
+
+func (s *svc) Stop() error {
+	if s.stopFunc == nil {
+		return errors.New("service is not started")
+	}
+
+	s.stopFunc()
+	return nil
+}
@@ -0,0 +1,128 @@
+package reminders
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/renderer"
+)
+
+type remindersRepository = repository.Reminders
+
+// memoryReminders отметки об отправке, остальные методы не вызываются
+type memoryReminders struct {
+	remindersRepository
+	sent []*domain.SentReminder
+}
+
+func (r *memoryReminders) IsSent(_ context.Context, userID int64, kind domain.ReminderKind, key string) (bool, error) {
+	for _, reminder := range r.sent {
+		if reminder.UserID == userID && reminder.Kind == kind && reminder.Key == key {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryReminders) SaveSent(_ context.Context, reminder *domain.SentReminder) error {
+	r.sent = append(r.sent, reminder)
+	return nil
+}
+
+type telegramService = service.Telegram
+
+type messagesTelegram struct {
+	telegramService
+	sent []string
+}
+
+func (s *messagesTelegram) SendMessage(_ int64, message *domain.Message) error {
+	s.sent = append(s.sent, message.Text)
+	return nil
+}
+
+func newTestService(t *testing.T) (*svc, *memoryReminders, *messagesTelegram) {
+	t.Helper()
+
+	rendererSvc, err := renderer.NewService(config.Render{})
+	if err != nil {
+		t.Fatalf("renderer.NewService: %v", err)
+	}
+	remindersRepo := &memoryReminders{}
+	telegramSvc := &messagesTelegram{}
+
+	return &svc{
+		remindersRepo: remindersRepo,
+		telegramSvc:   telegramSvc,
+		rendererSvc:   rendererSvc,
+	}, remindersRepo, telegramSvc
+}
+
+func newRecipient(weeklySummarySent bool) *domain.ReminderRecipient {
+	settings := domain.NewUserSettings(1, domain.LanguageEnglish)
+	settings.ReminderDaysBefore = 0
+
+	return &domain.ReminderRecipient{
+		ProgressTable: &domain.ProgressTable{
+			Disciplines: []domain.Discipline{{
+				Name: "Physics",
+				ControlEvents: []domain.ControlEvent{
+					{Name: "Lab 1", DeadlineDate: time.Date(2024, time.October, 14, 0, 0, 0, 0, config.BARSLocation)},
+					{Name: "Lab 2", DeadlineDate: time.Date(2024, time.October, 18, 0, 0, 0, 0, config.BARSLocation)},
+					{Name: "Lab 3", DeadlineDate: time.Date(2024, time.October, 25, 0, 0, 0, 0, config.BARSLocation)},
+				},
+			}},
+		},
+		Settings:          settings,
+		WeeklySummarySent: weeklySummarySent,
+	}
+}
+
+// TestWeeklySummaryAfterMissedMonday сводка не теряется, если в понедельник бот не работал
+func TestWeeklySummaryAfterMissedMonday(t *testing.T) {
+	s, remindersRepo, telegramSvc := newTestService(t)
+	wednesday := time.Date(2024, time.October, 16, 0, 0, 0, 0, config.BARSLocation)
+
+	err := s.sendUserReminders(context.Background(), newRecipient(false), wednesday, time.Time{})
+	if err != nil {
+		t.Fatalf("sendUserReminders: %v", err)
+	}
+
+	if len(telegramSvc.sent) != 1 {
+		t.Fatalf("sent %d messages, want 1", len(telegramSvc.sent))
+	}
+	summary := telegramSvc.sent[0]
+	if !strings.Contains(summary, "Lab 2") {
+		t.Errorf("summary %q lacks the event later this week", summary)
+	}
+	// мероприятие понедельника уже прошло, следующей недели - ещё не началось
+	for _, name := range []string{"Lab 1", "Lab 3"} {
+		if strings.Contains(summary, name) {
+			t.Errorf("summary %q contains %s", summary, name)
+		}
+	}
+
+	if len(remindersRepo.sent) != 1 || remindersRepo.sent[0].Key != "2024-W42" {
+		t.Errorf("saved %+v, want the weekly summary for 2024-W42", remindersRepo.sent)
+	}
+}
+
+func TestWeeklySummarySentOncePerWeek(t *testing.T) {
+	s, _, telegramSvc := newTestService(t)
+	thursday := time.Date(2024, time.October, 17, 0, 0, 0, 0, config.BARSLocation)
+
+	err := s.sendUserReminders(context.Background(), newRecipient(true), thursday, time.Time{})
+	if err != nil {
+		t.Fatalf("sendUserReminders: %v", err)
+	}
+
+	if len(telegramSvc.sent) != 0 {
+		t.Errorf("sent %q, want nothing", telegramSvc.sent)
+	}
+}
@@ -0,0 +1,83 @@
+package service
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+// Renderer превращает доменные события в сообщения для конкретного канала доставки
+type Renderer interface {
+	GradeChange(
+		format domain.MessageFormat,
+		language domain.Language,
+		gradeChange *domain.GradeChange,
+	) (*domain.Message, error)
+	// ProgressTable список дисциплин семестра
+	ProgressTable(
+		format domain.MessageFormat,
+		language domain.Language,
+		progressTable *domain.ProgressTable,
+	) (*domain.Message, error)
+	// Discipline оценки по дисциплине, при hideControlEventNames названия КМ заменяются на номера.
+	// Медиана курса выводится для мероприятий, у которых есть распределение в distributions
+	Discipline(
+		format domain.MessageFormat,
+		language domain.Language,
+		discipline domain.Discipline,
+		hideControlEventNames bool,
+		distributions []*domain.GradeDistribution,
+	) (*domain.Message, error)
+	// WeeklySummary мероприятия без оценки на неделе, которая начинается в weekStart
+	WeeklySummary(
+		format domain.MessageFormat,
+		language domain.Language,
+		weekStart time.Time,
+		events []domain.PlannedControlEvent,
+	) (*domain.Message, error)
+	// ControlEventReminder напоминание о приближающемся мероприятии
+	ControlEventReminder(
+		format domain.MessageFormat,
+		language domain.Language,
+		event domain.PlannedControlEvent,
+	) (*domain.Message, error)
+	// GradePublished обезличенное сообщение о выставленной оценке для группового чата, без самой оценки
+	GradePublished(
+		format domain.MessageFormat,
+		language domain.Language,
+		gradeChange *domain.GradeChange,
+	) (*domain.Message, error)
+	// DisciplineForecast текущий балл по дисциплине и оценки, необходимые для каждой итоговой
+	DisciplineForecast(
+		format domain.MessageFormat,
+		language domain.Language,
+		discipline domain.Discipline,
+	) (*domain.Message, error)
+	// RecordBook страница зачётной книжки, page - номер семестра в recordBook.Semesters()
+	RecordBook(
+		format domain.MessageFormat,
+		language domain.Language,
+		recordBook *domain.RecordBook,
+		page int,
+	) (*domain.Message, error)
+	// Schedule занятия с from включительно по to не включительно
+	Schedule(
+		format domain.MessageFormat,
+		language domain.Language,
+		schedule *domain.Schedule,
+		period domain.SchedulePeriod,
+		from time.Time,
+		to time.Time,
+	) (*domain.Message, error)
+	// Statistics статистика текущего семестра, при наличии архивных семестров - с динамикой среднего балла
+	Statistics(
+		format domain.MessageFormat,
+		language domain.Language,
+		statistics *domain.SemesterStatistics,
+		archivedStatistics []*domain.SemesterStatistics,
+	) (*domain.Message, error)
+	// Github ссылка на репозиторий бота
+	Github(format domain.MessageFormat, language domain.Language) (*domain.Message, error)
+	// FixGrades инструкция для пользователей, у которых страница оценок не основная в БАРС
+	FixGrades(format domain.MessageFormat, language domain.Language) (*domain.Message, error)
+}
@@ -0,0 +1,243 @@
+package renderer
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+const (
+	gradeChangeTemplate          = "grade_change"
+	progressTableTemplate        = "progress_table"
+	disciplineTemplate           = "discipline"
+	weeklySummaryTemplate        = "weekly_summary"
+	controlEventReminderTemplate = "control_event_reminder"
+	gradePublishedTemplate       = "grade_published"
+	disciplineForecastTemplate   = "discipline_forecast"
+	recordBookTemplate           = "record_book"
+	scheduleTemplate             = "schedule"
+	statisticsTemplate           = "statistics"
+	githubTemplate               = "github"
+	fixGradesTemplate            = "fix_grades"
+)
+
+// embeddedTemplates шаблоны по умолчанию, файл на каждую пару формат-язык: <format>.<language>.tmpl
+//
+//go:embed templates/*.tmpl
+var embeddedTemplates embed.FS
+
+var ErrUnknownFormat = errors.New("unknown message format")
+
+type templateKey struct {
+	format   domain.MessageFormat
+	language domain.Language
+}
+
+type svc struct {
+	templates map[templateKey]*template.Template
+}
+
+// NewService загружает встроенные шаблоны. Одноимённые файлы из cfg.TemplatesDir разбираются поверх встроенных,
+// поэтому в них достаточно переопределить только нужные блоки {{define}}
+func NewService(cfg config.Render) (*svc, error) {
+	s := &svc{
+		templates: make(map[templateKey]*template.Template),
+	}
+
+	for _, format := range domain.MessageFormats {
+		for _, language := range domain.Languages {
+			t, err := loadTemplate(cfg.TemplatesDir, templateFileName(format, language))
+			if err != nil {
+				return nil, fmt.Errorf("loadTemplate: %w", err)
+			}
+			s.templates[templateKey{format: format, language: language}] = t
+		}
+	}
+
+	return s, nil
+}
+
+func templateFileName(format domain.MessageFormat, language domain.Language) string {
+	return fmt.Sprintf("%s.%s.tmpl", format, language)
+}
+
+func loadTemplate(dir string, name string) (*template.Template, error) {
+	content, err := embeddedTemplates.ReadFile(path.Join("templates", name))
+	if err != nil {
+		return nil, fmt.Errorf("embeddedTemplates.ReadFile: %w", err)
+	}
+
+	t, err := template.New(name).Funcs(templateFuncs).Parse(string(content))
+	if err != nil {
+		return nil, fmt.Errorf("template.Parse(%s): %w", name, err)
+	}
+
+	if dir == "" {
+		return t, nil
+	}
+
+	override, err := os.ReadFile(filepath.Join(dir, name))
+	if errors.Is(err, fs.ErrNotExist) {
+		return t, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("os.ReadFile: %w", err)
+	}
+
+	t, err = t.Parse(string(override))
+	if err != nil {
+		return nil, fmt.Errorf("template.Parse(%s): %w", filepath.Join(dir, name), err)
+	}
+
+	return t, nil
+}
+
+var templateFuncs = template.FuncMap{
+	"inc": func(i int) int { return i + 1 },
+}
+
+func (s *svc) GradeChange(
+	format domain.MessageFormat,
+	language domain.Language,
+	gradeChange *domain.GradeChange,
+) (*domain.Message, error) {
+	return s.render(format, language, gradeChangeTemplate, newGradeChangeView(format, language, gradeChange))
+}
+
+func (s *svc) ProgressTable(
+	format domain.MessageFormat,
+	language domain.Language,
+	progressTable *domain.ProgressTable,
+) (*domain.Message, error) {
+	return s.render(format, language, progressTableTemplate, newProgressTableView(format, progressTable))
+}
+
+func (s *svc) Discipline(
+	format domain.MessageFormat,
+	language domain.Language,
+	discipline domain.Discipline,
+	hideControlEventNames bool,
+	distributions []*domain.GradeDistribution,
+) (*domain.Message, error) {
+	return s.render(
+		format,
+		language,
+		disciplineTemplate,
+		newDisciplineView(format, language, discipline, hideControlEventNames, distributions),
+	)
+}
+
+func (s *svc) WeeklySummary(
+	format domain.MessageFormat,
+	language domain.Language,
+	weekStart time.Time,
+	events []domain.PlannedControlEvent,
+) (*domain.Message, error) {
+	return s.render(format, language, weeklySummaryTemplate, newWeeklySummaryView(format, language, weekStart, events))
+}
+
+func (s *svc) ControlEventReminder(
+	format domain.MessageFormat,
+	language domain.Language,
+	event domain.PlannedControlEvent,
+) (*domain.Message, error) {
+	return s.render(
+		format,
+		language,
+		controlEventReminderTemplate,
+		newUpcomingControlEventView(format, language, event),
+	)
+}
+
+func (s *svc) GradePublished(
+	format domain.MessageFormat,
+	language domain.Language,
+	gradeChange *domain.GradeChange,
+) (*domain.Message, error) {
+	return s.render(format, language, gradePublishedTemplate, newGradePublishedView(format, language, gradeChange))
+}
+
+func (s *svc) DisciplineForecast(
+	format domain.MessageFormat,
+	language domain.Language,
+	discipline domain.Discipline,
+) (*domain.Message, error) {
+	return s.render(format, language, disciplineForecastTemplate, newDisciplineForecastView(format, discipline))
+}
+
+func (s *svc) RecordBook(
+	format domain.MessageFormat,
+	language domain.Language,
+	recordBook *domain.RecordBook,
+	page int,
+) (*domain.Message, error) {
+	return s.render(format, language, recordBookTemplate, newRecordBookView(format, language, recordBook, page))
+}
+
+func (s *svc) Schedule(
+	format domain.MessageFormat,
+	language domain.Language,
+	schedule *domain.Schedule,
+	period domain.SchedulePeriod,
+	from time.Time,
+	to time.Time,
+) (*domain.Message, error) {
+	return s.render(
+		format,
+		language,
+		scheduleTemplate,
+		newScheduleView(format, language, schedule, period, from, to),
+	)
+}
+
+func (s *svc) Statistics(
+	format domain.MessageFormat,
+	language domain.Language,
+	statistics *domain.SemesterStatistics,
+	archivedStatistics []*domain.SemesterStatistics,
+) (*domain.Message, error) {
+	return s.render(format, language, statisticsTemplate, newStatisticsView(format, statistics, archivedStatistics))
+}
+
+func (s *svc) Github(format domain.MessageFormat, language domain.Language) (*domain.Message, error) {
+	return s.render(format, language, githubTemplate, nil)
+}
+
+func (s *svc) FixGrades(format domain.MessageFormat, language domain.Language) (*domain.Message, error) {
+	return s.render(format, language, fixGradesTemplate, nil)
+}
+
+func (s *svc) render(
+	format domain.MessageFormat,
+	language domain.Language,
+	name string,
+	data any,
+) (*domain.Message, error) {
+	t, ok := s.templates[templateKey{format: format, language: language}]
+	if !ok {
+		t, ok = s.templates[templateKey{format: format, language: domain.DefaultLanguage}]
+	}
+	if !ok {
+		return nil, ErrUnknownFormat
+	}
+
+	var b strings.Builder
+	if err := t.ExecuteTemplate(&b, name, data); err != nil {
+		return nil, fmt.Errorf("t.ExecuteTemplate(%s): %w", name, err)
+	}
+
+	return &domain.Message{
+		Format: format,
+		Text:   strings.TrimSpace(b.String()),
+	}, nil
+}
@@ -0,0 +1,188 @@
+package renderer
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/markdown"
+)
+
+// FuzzMessageGenerators названия из БАРС не должны ломать разметку MarkdownV2 ни в одном сообщении
+func FuzzMessageGenerators(f *testing.F) {
+	for _, seed := range []string{
+		"Математический анализ",
+		"Программирование_на_C++",
+		"*Физика*",
+		"[ссылка](https://example.com)",
+		"`код`",
+		"КМ-1 (вес 0,2, 6 неделя).",
+		"\\*\\",
+		"a_b*c[d]e(f)g~h`i>j#k+l-m=n|o{p}q.r!s",
+		"",
+	} {
+		f.Add(seed)
+	}
+
+	rendererSvc, err := NewService(config.Render{})
+	if err != nil {
+		f.Fatalf("NewService: %v", err)
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		if !utf8.ValidString(name) {
+			t.Skip("БАРС отдаёт только UTF-8, см. validateProgressTable")
+		}
+
+		now := time.Date(2024, time.October, 16, 12, 0, 0, 0, config.BARSLocation)
+		today := time.Date(2024, time.October, 16, 0, 0, 0, 0, config.BARSLocation)
+		discipline := domain.Discipline{
+			Name: name,
+			ControlEvents: []domain.ControlEvent{
+				{Name: name, Grade: domain.ParseGrade("4"), Weight: 0.5},
+				{Name: name, Grade: domain.ParseGrade(name), Weight: 0.5},
+				{Name: "Промежуточная аттестация", Grade: domain.ParseGrade("")},
+			},
+		}
+		distributions := []*domain.GradeDistribution{
+			domain.NewGradeDistribution(name, name, name, map[int64]float64{1: 3, 2: 4, 3: 4.5, 4: 5, 5: 5}, now),
+		}
+		progressTable := &domain.ProgressTable{
+			Semester:    domain.Semester{ID: "1", Name: name},
+			Disciplines: []domain.Discipline{discipline},
+		}
+		recordBook := &domain.RecordBook{
+			Entries: []domain.RecordBookEntry{{
+				Discipline:  name,
+				Semester:    name,
+				ControlForm: name,
+				Mark:        name,
+				Date:        name,
+				Teacher:     name,
+			}},
+		}
+		schedule := &domain.Schedule{
+			Lessons: []domain.Lesson{{
+				Day:        today,
+				TimeSlot:   name,
+				Discipline: name,
+				Room:       name,
+				Teacher:    name,
+				LessonType: name,
+			}},
+		}
+		gradeChange := &domain.GradeChange{
+			Discipline:   name,
+			ControlEvent: name,
+			OldGrade:     name,
+			NewGrade:     "5",
+		}
+		weekStart := time.Date(2024, time.October, 14, 0, 0, 0, 0, config.BARSLocation)
+		plannedEvents := []domain.PlannedControlEvent{
+			{
+				Discipline:   name,
+				ControlEvent: domain.ControlEvent{Name: name, DeadlineDate: weekStart.AddDate(0, 0, 2)},
+				Start:        weekStart.AddDate(0, 0, 2),
+				End:          weekStart.AddDate(0, 0, 3),
+			},
+			{
+				Discipline:   name,
+				ControlEvent: domain.ControlEvent{Name: name, DeadlineWeek: 7},
+				Start:        weekStart,
+				End:          weekStart.AddDate(0, 0, 7),
+			},
+		}
+
+		for _, language := range domain.Languages {
+			messages := make(map[string]string)
+			rendered := map[string]func() (*domain.Message, error){
+				"rendererSvc.GradeChange": func() (*domain.Message, error) {
+					return rendererSvc.GradeChange(domain.MessageFormatMarkdownV2, language, gradeChange)
+				},
+				"rendererSvc.ProgressTable": func() (*domain.Message, error) {
+					return rendererSvc.ProgressTable(domain.MessageFormatMarkdownV2, language, progressTable)
+				},
+				"rendererSvc.Discipline": func() (*domain.Message, error) {
+					return rendererSvc.Discipline(domain.MessageFormatMarkdownV2, language, discipline, false, distributions)
+				},
+				"rendererSvc.WeeklySummary": func() (*domain.Message, error) {
+					return rendererSvc.WeeklySummary(domain.MessageFormatMarkdownV2, language, weekStart, plannedEvents)
+				},
+				"rendererSvc.ControlEventReminder": func() (*domain.Message, error) {
+					return rendererSvc.ControlEventReminder(domain.MessageFormatMarkdownV2, language, plannedEvents[1])
+				},
+				"rendererSvc.GradePublished": func() (*domain.Message, error) {
+					return rendererSvc.GradePublished(domain.MessageFormatMarkdownV2, language, gradeChange)
+				},
+				"rendererSvc.DisciplineForecast": func() (*domain.Message, error) {
+					return rendererSvc.DisciplineForecast(domain.MessageFormatMarkdownV2, language, discipline)
+				},
+				"rendererSvc.RecordBook": func() (*domain.Message, error) {
+					return rendererSvc.RecordBook(domain.MessageFormatMarkdownV2, language, recordBook, 0)
+				},
+				"rendererSvc.Schedule": func() (*domain.Message, error) {
+					return rendererSvc.Schedule(
+						domain.MessageFormatMarkdownV2,
+						language,
+						schedule,
+						domain.SchedulePeriodToday,
+						today,
+						today.AddDate(0, 0, 1),
+					)
+				},
+				"rendererSvc.Statistics": func() (*domain.Message, error) {
+					return rendererSvc.Statistics(
+						domain.MessageFormatMarkdownV2,
+						language,
+						progressTable.Statistics(),
+						[]*domain.SemesterStatistics{progressTable.Statistics()},
+					)
+				},
+			}
+			for generator, render := range rendered {
+				message, err := render()
+				if err != nil {
+					t.Fatalf("%s: %v", generator, err)
+				}
+				messages[generator] = message.Text
+			}
+
+			for generator, text := range messages {
+				checkMarkdownV2(t, generator, text)
+				if !strings.Contains(markdown.StripV2(text), strings.TrimSpace(name)) {
+					t.Errorf("%s(%s): text %q lost name %q", generator, language, text, name)
+				}
+			}
+		}
+	})
+}
+
+// checkMarkdownV2 вне экранирования допустимы только парные звёздочки жирного шрифта
+func checkMarkdownV2(t *testing.T, generator string, text string) {
+	t.Helper()
+
+	escaped := false
+	stars := 0
+	for _, r := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			stars++
+		case strings.ContainsRune("_[]()~`>#+-=|{}.!", r):
+			t.Fatalf("%s: unescaped %q in %q", generator, r, text)
+		}
+	}
+
+	if escaped {
+		t.Fatalf("%s: trailing backslash in %q", generator, text)
+	}
+	if stars%2 != 0 {
+		t.Fatalf("%s: unbalanced bold in %q", generator, text)
+	}
+}
@@ -0,0 +1,101 @@
+package renderer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+func TestGradeChangeEscapesForFormat(t *testing.T) {
+	rendererSvc, err := NewService(config.Render{})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	gradeChange := &domain.GradeChange{
+		Discipline:   "Программирование_на_<C++>",
+		ControlEvent: "КМ-1",
+		OldGrade:     "",
+		NewGrade:     "5",
+	}
+	tests := []struct {
+		format     domain.MessageFormat
+		discipline string
+	}{
+		{format: domain.MessageFormatPlain, discipline: "Программирование_на_<C++>"},
+		{format: domain.MessageFormatMarkdownV2, discipline: `Программирование\_на\_<C\+\+\>`},
+		{format: domain.MessageFormatHTML, discipline: "Программирование_на_&lt;C++&gt;"},
+	}
+
+	for _, tt := range tests {
+		message, err := rendererSvc.GradeChange(tt.format, domain.LanguageRussian, gradeChange)
+		if err != nil {
+			t.Fatalf("GradeChange(%s): %v", tt.format, err)
+		}
+		if message.Format != tt.format {
+			t.Errorf("GradeChange(%s): format = %s", tt.format, message.Format)
+		}
+		if !strings.Contains(message.Text, tt.discipline) {
+			t.Errorf("GradeChange(%s): text %q does not contain %q", tt.format, message.Text, tt.discipline)
+		}
+	}
+}
+
+func TestTemplatesOverride(t *testing.T) {
+	dir := t.TempDir()
+	override := `{{define "github" -}}
+https://example.com
+{{- end}}`
+	name := templateFileName(domain.MessageFormatPlain, domain.LanguageEnglish)
+	err := os.WriteFile(filepath.Join(dir, name), []byte(override), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	rendererSvc, err := NewService(config.Render{TemplatesDir: dir})
+	if err != nil {
+		t.Fatalf("NewService: %v", err)
+	}
+
+	message, err := rendererSvc.Github(domain.MessageFormatPlain, domain.LanguageEnglish)
+	if err != nil {
+		t.Fatalf("Github: %v", err)
+	}
+	if message.Text != "https://example.com" {
+		t.Errorf("overridden github = %q", message.Text)
+	}
+
+	// блоки, которых нет в файле, и файлы, которых нет в каталоге, остаются встроенными
+	message, err = rendererSvc.FixGrades(domain.MessageFormatPlain, domain.LanguageEnglish)
+	if err != nil {
+		t.Fatalf("FixGrades: %v", err)
+	}
+	if message.Text == "" {
+		t.Error("embedded fix_grades is empty")
+	}
+	message, err = rendererSvc.Github(domain.MessageFormatPlain, domain.LanguageRussian)
+	if err != nil {
+		t.Fatalf("Github: %v", err)
+	}
+	if message.Text == "https://example.com" {
+		t.Error("override leaked into another language")
+	}
+}
+
+func TestTemplatesOverrideInvalid(t *testing.T) {
+	dir := t.TempDir()
+	name := templateFileName(domain.MessageFormatHTML, domain.LanguageRussian)
+	err := os.WriteFile(filepath.Join(dir, name), []byte(`{{define "github"}}`), 0o600)
+	if err != nil {
+		t.Fatalf("os.WriteFile: %v", err)
+	}
+
+	_, err = NewService(config.Render{TemplatesDir: dir})
+	if err == nil {
+		t.Fatal("NewService accepted a broken template")
+	}
+}
@@ -0,0 +1,456 @@
+package renderer
+
+import (
+	"html"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/markdown"
+)
+
+const (
+	dateLayout     = "02.01.2006"
+	dayMonthLayout = "02.01"
+)
+
+// Данные для шаблонов. Все строки в них уже экранированы под формат,
+// поэтому в шаблонах их можно выводить как есть
+
+type gradeChangeView struct {
+	Discipline   string
+	ControlEvent string
+	OldGrade     string
+	NewGrade     string
+}
+
+// gradePublishedView сообщение для группового чата, оценки в нём нет
+type gradePublishedView struct {
+	Discipline   string
+	ControlEvent string
+}
+
+type progressTableView struct {
+	Semester    string
+	Disciplines []string
+}
+
+type disciplineView struct {
+	Name          string
+	ControlEvents []controlEventView
+}
+
+type controlEventView struct {
+	Name  string
+	Grade string
+	// CourseMedian пусто, если распределения по мероприятию нет
+	CourseMedian string
+}
+
+type weeklySummaryView struct {
+	Week          string
+	ControlEvents []upcomingControlEventView
+}
+
+type upcomingControlEventView struct {
+	Discipline   string
+	ControlEvent string
+	Due          string
+}
+
+type disciplineForecastView struct {
+	Discipline string
+	// CurrentScore пусто, если оценок за мероприятия ещё нет
+	CurrentScore       string
+	GradedEventsCount  int
+	ScoredEventsCount  int
+	HasRemainingEvents bool
+	Requirements       []gradeRequirementView
+}
+
+type gradeRequirementView struct {
+	Target      int
+	Achieved    bool
+	Unreachable bool
+	MinAverage  string
+}
+
+type recordBookView struct {
+	Semester   string
+	Page       int
+	PagesCount int
+	Entries    []recordBookEntryView
+}
+
+type recordBookEntryView struct {
+	Discipline  string
+	ControlForm string
+	Mark        string
+	Date        string
+	Teacher     string
+}
+
+type scheduleView struct {
+	IsTomorrow bool
+	IsWeek     bool
+	From       string
+	// To последний день периода включительно
+	To   string
+	Days []scheduleDayView
+}
+
+type scheduleDayView struct {
+	Weekday string
+	Date    string
+	Lessons []lessonView
+}
+
+type lessonView struct {
+	TimeSlot   string
+	Discipline string
+	LessonType string
+	Room       string
+	Teacher    string
+}
+
+type statisticsView struct {
+	Semester    string
+	Disciplines []disciplineStatisticsView
+	// Average пусто, если оценок в баллах нет
+	Average      string
+	GradedCount  int
+	PendingCount int
+	AtRisk       []string
+	// Trend пуст без архивных семестров, последним идёт текущий семестр
+	Trend []semesterTrendView
+}
+
+type disciplineStatisticsView struct {
+	Name         string
+	Average      string
+	GradedCount  int
+	PendingCount int
+}
+
+// semesterTrendView Semester пусто у текущего семестра без названия
+type semesterTrendView struct {
+	Semester string
+	Average  string
+}
+
+func newGradeChangeView(
+	format domain.MessageFormat,
+	language domain.Language,
+	gradeChange *domain.GradeChange,
+) gradeChangeView {
+	escape := escapeFunc(format)
+
+	return gradeChangeView{
+		Discipline:   escape(gradeChange.Discipline),
+		ControlEvent: escape(answers.ControlEvent(language, gradeChange)),
+		OldGrade:     escape(answers.Grade(language, domain.ParseGrade(gradeChange.OldGrade))),
+		NewGrade:     escape(answers.Grade(language, domain.ParseGrade(gradeChange.NewGrade))),
+	}
+}
+
+func newGradePublishedView(
+	format domain.MessageFormat,
+	language domain.Language,
+	gradeChange *domain.GradeChange,
+) gradePublishedView {
+	escape := escapeFunc(format)
+
+	return gradePublishedView{
+		Discipline:   escape(gradeChange.Discipline),
+		ControlEvent: escape(answers.ControlEvent(language, gradeChange)),
+	}
+}
+
+func newProgressTableView(format domain.MessageFormat, progressTable *domain.ProgressTable) progressTableView {
+	escape := escapeFunc(format)
+
+	view := progressTableView{
+		Semester:    escape(progressTable.Semester.Name),
+		Disciplines: make([]string, 0, len(progressTable.Disciplines)),
+	}
+	for _, discipline := range progressTable.Disciplines {
+		view.Disciplines = append(view.Disciplines, escape(discipline.Name))
+	}
+
+	return view
+}
+
+func newDisciplineView(
+	format domain.MessageFormat,
+	language domain.Language,
+	discipline domain.Discipline,
+	hideControlEventNames bool,
+	distributions []*domain.GradeDistribution,
+) disciplineView {
+	escape := escapeFunc(format)
+
+	distributionByControlEvent := make(map[string]*domain.GradeDistribution, len(distributions))
+	for _, distribution := range distributions {
+		distributionByControlEvent[distribution.ControlEvent] = distribution
+	}
+
+	view := disciplineView{
+		Name:          escape(discipline.Name),
+		ControlEvents: make([]controlEventView, 0, len(discipline.ControlEvents)),
+	}
+	for i, ce := range discipline.ControlEvents {
+		name := ce.Name
+		if hideControlEventNames && !ce.IsSummary() {
+			name = answers.Textf(language, answers.ControlEventShortName, i+1)
+		}
+		var courseMedian string
+		if distribution, ok := distributionByControlEvent[ce.Name]; ok {
+			courseMedian = answers.Textf(
+				language,
+				answers.CourseMedian,
+				formatScore(distribution.Median),
+				formatScore(distribution.Q1),
+				formatScore(distribution.Q3),
+				distribution.Contributors,
+			)
+		}
+
+		view.ControlEvents = append(view.ControlEvents, controlEventView{
+			Name:         escape(name),
+			Grade:        escape(answers.Grade(language, ce.Grade)),
+			CourseMedian: escape(courseMedian),
+		})
+	}
+
+	return view
+}
+
+func newWeeklySummaryView(
+	format domain.MessageFormat,
+	language domain.Language,
+	weekStart time.Time,
+	events []domain.PlannedControlEvent,
+) weeklySummaryView {
+	escape := escapeFunc(format)
+
+	view := weeklySummaryView{
+		Week:          escape(formatPeriod(weekStart, weekStart.AddDate(0, 0, 7))),
+		ControlEvents: make([]upcomingControlEventView, 0, len(events)),
+	}
+	for _, event := range events {
+		view.ControlEvents = append(view.ControlEvents, newUpcomingControlEventView(format, language, event))
+	}
+
+	return view
+}
+
+func newUpcomingControlEventView(
+	format domain.MessageFormat,
+	language domain.Language,
+	event domain.PlannedControlEvent,
+) upcomingControlEventView {
+	escape := escapeFunc(format)
+
+	due := event.Start.Format(dateLayout)
+	if !event.HasExactDate() {
+		due = answers.Textf(
+			language,
+			answers.ReminderDueWeek,
+			event.ControlEvent.DeadlineWeek,
+			formatPeriod(event.Start, event.End),
+		)
+	}
+
+	return upcomingControlEventView{
+		Discipline:   escape(event.Discipline),
+		ControlEvent: escape(event.ControlEvent.Name),
+		Due:          escape(due),
+	}
+}
+
+func newDisciplineForecastView(format domain.MessageFormat, discipline domain.Discipline) disciplineForecastView {
+	escape := escapeFunc(format)
+
+	forecast := discipline.Forecast()
+	view := disciplineForecastView{
+		Discipline:         escape(discipline.Name),
+		GradedEventsCount:  forecast.GradedEventsCount,
+		ScoredEventsCount:  forecast.ScoredEventsCount,
+		HasRemainingEvents: forecast.HasRemainingEvents,
+		Requirements:       make([]gradeRequirementView, 0, len(forecast.Requirements)),
+	}
+	if forecast.HasCurrentScore {
+		view.CurrentScore = escape(formatAverage(forecast.CurrentScore))
+	}
+	for _, requirement := range forecast.Requirements {
+		view.Requirements = append(view.Requirements, gradeRequirementView{
+			Target:      requirement.Target,
+			Achieved:    requirement.Status == domain.RequirementStatusAchieved,
+			Unreachable: requirement.Status == domain.RequirementStatusUnreachable,
+			MinAverage:  escape(formatAverage(requirement.MinAverage)),
+		})
+	}
+
+	return view
+}
+
+func newRecordBookView(
+	format domain.MessageFormat,
+	language domain.Language,
+	recordBook *domain.RecordBook,
+	page int,
+) recordBookView {
+	escape := escapeFunc(format)
+
+	semesters := recordBook.Semesters()
+	semester := semesters[page]
+	entries := recordBook.SemesterEntries(semester)
+
+	view := recordBookView{
+		Semester:   escape(semester),
+		Page:       page + 1,
+		PagesCount: len(semesters),
+		Entries:    make([]recordBookEntryView, 0, len(entries)),
+	}
+	for _, entry := range entries {
+		mark := entry.Mark
+		if mark == "" {
+			mark = answers.Text(language, answers.GradeNotGraded)
+		}
+
+		view.Entries = append(view.Entries, recordBookEntryView{
+			Discipline:  escape(entry.Discipline),
+			ControlForm: escape(entry.ControlForm),
+			Mark:        escape(mark),
+			Date:        escape(entry.Date),
+			Teacher:     escape(entry.Teacher),
+		})
+	}
+
+	return view
+}
+
+func newScheduleView(
+	format domain.MessageFormat,
+	language domain.Language,
+	schedule *domain.Schedule,
+	period domain.SchedulePeriod,
+	from time.Time,
+	to time.Time,
+) scheduleView {
+	escape := escapeFunc(format)
+
+	view := scheduleView{
+		IsTomorrow: period == domain.SchedulePeriodTomorrow,
+		IsWeek:     period == domain.SchedulePeriodWeek,
+		From:       escape(from.Format(dayMonthLayout)),
+		To:         escape(to.AddDate(0, 0, -1).Format(dayMonthLayout)),
+		Days:       make([]scheduleDayView, 0),
+	}
+	for _, lesson := range schedule.LessonsBetween(from, to) {
+		date := escape(lesson.Day.Format(dayMonthLayout))
+		if len(view.Days) == 0 || view.Days[len(view.Days)-1].Date != date {
+			view.Days = append(view.Days, scheduleDayView{
+				Weekday: escape(answers.Text(language, answers.WeekdaySunday+answers.Key(lesson.Day.Weekday()))),
+				Date:    date,
+			})
+		}
+
+		day := &view.Days[len(view.Days)-1]
+		day.Lessons = append(day.Lessons, lessonView{
+			TimeSlot:   escape(lesson.TimeSlot),
+			Discipline: escape(lesson.Discipline),
+			LessonType: escape(lesson.LessonType),
+			Room:       escape(lesson.Room),
+			Teacher:    escape(lesson.Teacher),
+		})
+	}
+
+	return view
+}
+
+func newStatisticsView(
+	format domain.MessageFormat,
+	statistics *domain.SemesterStatistics,
+	archivedStatistics []*domain.SemesterStatistics,
+) statisticsView {
+	escape := escapeFunc(format)
+
+	view := statisticsView{
+		Semester:     escape(statistics.Semester.Name),
+		Disciplines:  make([]disciplineStatisticsView, 0, len(statistics.Disciplines)),
+		GradedCount:  statistics.GradedCount,
+		PendingCount: statistics.PendingCount,
+		AtRisk:       make([]string, 0),
+	}
+	if statistics.HasAverage {
+		view.Average = escape(formatAverage(statistics.Average))
+	}
+	for _, discipline := range statistics.Disciplines {
+		disciplineView := disciplineStatisticsView{
+			Name:         escape(discipline.Name),
+			GradedCount:  discipline.GradedCount,
+			PendingCount: discipline.PendingCount,
+		}
+		if discipline.HasAverage {
+			disciplineView.Average = escape(formatAverage(discipline.Average))
+		}
+		view.Disciplines = append(view.Disciplines, disciplineView)
+
+		if discipline.IsAtRisk {
+			view.AtRisk = append(view.AtRisk, escape(discipline.Name))
+		}
+	}
+
+	if len(archivedStatistics) == 0 {
+		return view
+	}
+	view.Trend = make([]semesterTrendView, 0, len(archivedStatistics)+1)
+	for _, semesterStatistics := range archivedStatistics {
+		view.Trend = append(view.Trend, newSemesterTrendView(format, semesterStatistics))
+	}
+	view.Trend = append(view.Trend, newSemesterTrendView(format, statistics))
+
+	return view
+}
+
+func newSemesterTrendView(format domain.MessageFormat, statistics *domain.SemesterStatistics) semesterTrendView {
+	escape := escapeFunc(format)
+
+	view := semesterTrendView{
+		Semester: escape(statistics.Semester.Name),
+	}
+	if statistics.HasAverage {
+		view.Average = escape(formatAverage(statistics.Average))
+	}
+
+	return view
+}
+
+// formatAverage средние оценки выводятся с точностью до сотых
+func formatAverage(average float64) string {
+	return strconv.FormatFloat(average, 'f', 2, 64)
+}
+
+// formatScore квартили интерполируются, поэтому округляются до сотых
+func formatScore(score float64) string {
+	return strconv.FormatFloat(math.Round(score*100)/100, 'f', -1, 64)
+}
+
+// formatPeriod период [start, end) в виде "14.10 – 20.10"
+func formatPeriod(start, end time.Time) string {
+	return start.Format(dayMonthLayout) + " – " + end.AddDate(0, 0, -1).Format(dayMonthLayout)
+}
+
+func escapeFunc(format domain.MessageFormat) func(string) string {
+	switch format {
+	case domain.MessageFormatMarkdownV2:
+		return markdown.EscapeV2
+	case domain.MessageFormatHTML:
+		return html.EscapeString
+	default:
+		return func(s string) string { return s }
+	}
+}
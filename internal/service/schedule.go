@@ -0,0 +1,11 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Schedule interface {
+	Schedule(ctx context.Context, userID int64) (*domain.Schedule, error)
+}
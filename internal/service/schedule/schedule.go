@@ -0,0 +1,59 @@
+package schedule
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/rs/zerolog/log"
+)
+
+type svc struct {
+	userSvc service.User
+	barsSvc service.Bars
+	cfg     config.Bars
+}
+
+func NewService(
+	userSvc service.User,
+	barsSvc service.Bars,
+	cfg config.Bars,
+) *svc {
+	return &svc{
+		userSvc: userSvc,
+		barsSvc: barsSvc,
+		cfg:     cfg,
+	}
+}
+
+// Schedule возвращает расписание из кеша в Postgres, если оно не старше ScheduleCacheTTL,
+// иначе получает его из БАРС
+func (s *svc) Schedule(ctx context.Context, userID int64) (*domain.Schedule, error) {
+	cachedSchedule, err := s.userSvc.Schedule(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.Schedule: %w", err)
+	}
+	if cachedSchedule != nil && time.Since(cachedSchedule.UpdatedAt) < s.cfg.ScheduleCacheTTL {
+		return cachedSchedule, nil
+	}
+
+	schedule, err := s.barsSvc.Schedule(ctx, userID)
+	if err != nil {
+		// устаревшее расписание лучше, чем никакого
+		if cachedSchedule != nil {
+			log.Ctx(ctx).Error().Msgf("schedule.Schedule: barsSvc.Schedule: %v", err.Error())
+			return cachedSchedule, nil
+		}
+		return nil, fmt.Errorf("barsSvc.Schedule: %w", err)
+	}
+
+	err = s.userSvc.SaveSchedule(ctx, userID, schedule)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.SaveSchedule: %w", err)
+	}
+
+	return schedule, nil
+}
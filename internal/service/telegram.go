@@ -1,9 +1,17 @@
 package service
 
-// TODO тут нужно будет завязаться на опции сервиса, а не телебота
+import (
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
 type Telegram interface {
-	SendMessageWithOpts(id int64, message string, opts ...interface{}) error
-	EditMessageWithOpts(id int64, messageID int, msg string, opts ...interface{}) error
+	// SendMessage отправляет сообщение, разметка определяется message.Format.
+	// Возвращает *errors.RetryAfterError при ограничении частоты и errors.ErrMessageRejected, если повторять бесполезно
+	SendMessage(id int64, message *domain.Message) error
+	// SendChatMessage отправляет сообщение в групповой чат. Если бота из чата убрали, чат забывается
+	SendChatMessage(chatID int64, message *domain.Message) error
+	// SendReauthorizationRequest отправляет сообщение с кнопкой повторного ввода пароля
+	SendReauthorizationRequest(id int64, message string) error
 	Start()
 	Stop()
 }
@@ -1,17 +1,26 @@
 package telegram
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
 
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
 	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/export"
+	"github.com/ilyadubrovsky/tracking-bars/internal/report"
 	"github.com/ilyadubrovsky/tracking-bars/pkg/bars"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/fuzzy"
+	"github.com/jellydator/ttlcache/v3"
 	"github.com/rs/zerolog/log"
 	tele "gopkg.in/telebot.v3"
 )
@@ -20,294 +29,2429 @@ const (
 	callbackProgressTable                        = "pt"
 	callbackProgressTableBackOption              = "back"
 	callbackProgressTableDisciplineDetailsOption = "show"
+	callbackProgressTableForecastOption          = "forecast"
+	callbackProgressTableSemestersOption         = "semesters"
+	callbackProgressTableSemesterOption          = "sem"
+	callbackProgressTableImageOption             = "img"
+	callbackProgressTablePDFOption               = "pdf"
+	// callbackProgressTableChartOption за ним следует номер дисциплины, 0 означает общий график
+	callbackProgressTableChartOption = "chart"
+	// callbackProgressTableSemesterSeparator отделяет номер семестра, пустой номер означает текущий семестр
+	callbackProgressTableSemesterSeparator = "@"
+	callbackReauthorization                = "reauth"
+	callbackRecordBook                     = "rb"
+	callbackSchedule                       = "sch"
+	callbackScheduleTodayOption            = string(domain.SchedulePeriodToday)
+	callbackScheduleTomorrowOption         = string(domain.SchedulePeriodTomorrow)
+	callbackScheduleWeekOption             = string(domain.SchedulePeriodWeek)
+	callbackExport                         = "exp"
+	// callbackExportHistorySuffix означает, что в файл нужно добавить историю изменений оценок
+	callbackExportHistorySuffix = "+h"
+	callbackLanguage            = "lang"
+	callbackNotify              = "notify"
+	// callbackNotifyBothOption включает все каналы из domain.NotificationChannels
+	callbackNotifyBothOption     = "both"
+	callbackWebhooks             = "wh"
+	callbackWebhooksTestOption   = "test"
+	callbackWebhooksRemoveOption = "rm"
+	callbackRemind               = "remind"
+	// callbackRemindSummaryOption за ним следует 1 или 0: включить или выключить сводку на неделю
+	callbackRemindSummaryOption = "s"
+	// callbackRemindDaysOption за ним следует число дней до мероприятия, 0 выключает напоминания
+	callbackRemindDaysOption = "d"
+	// callbackShare за ним следует 1 или 0: участвовать в распределениях оценок или нет
+	callbackShare = "share"
 )
 
+const (
+	// inlineResultsLimit телеграм принимает не больше 50 результатов, но столько дисциплин в семестре не бывает
+	inlineResultsLimit = 20
+	// inlineStartParameter с ним телеграм открывает личный чат с ботом, если делиться ещё нечем
+	inlineStartParameter = "inline"
+)
+
+// reminderDaysOptions варианты числа дней в кнопках /remind, остальные значения задаются командой
+var reminderDaysOptions = []int{1, 2, 3, 7}
+
 func (s *svc) handleOnCallback(c tele.Context) error {
 	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
 	if strings.HasPrefix(callbackData, callbackProgressTable) {
 		return s.handleProgressTableCallback(c)
 	}
+	if callbackData == callbackReauthorization {
+		return s.handleReauthorizationCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackRecordBook) {
+		return s.handleRecordBookCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackSchedule) {
+		return s.handleScheduleCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackExport) {
+		return s.handleExportCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackLanguage) {
+		return s.handleLanguageCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackNotify) {
+		return s.handleNotifyCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackWebhooks) {
+		return s.handleWebhooksCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackRemind) {
+		return s.handleRemindCallback(c)
+	}
+	if strings.HasPrefix(callbackData, callbackShare) {
+		return s.handleShareCallback(c)
+	}
 
-	return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.BotError)
+	return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(s.language(c), answers.BotError))
 }
 
 func (s *svc) handleProgressTableCallback(c tele.Context) error {
 	logger := log.With().Fields(extractTelebotFields(c)).Logger()
 	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
 
 	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
 	usefulData := strings.TrimPrefix(callbackData, callbackProgressTable)
 	if len(usefulData) == 0 {
-		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.BotError)
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
 	}
 
 	user, err := s.userSvc.User(ctx, c.Sender().ID)
 	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
 		logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
-		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.BotError)
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.ClientNotAuthorized))
 	}
 
-	progressTable := user.ProgressTable
+	if usefulData == callbackProgressTableSemestersOption {
+		return s.handleSemestersCallback(ctx, c, user)
+	}
+	if strings.HasPrefix(usefulData, callbackProgressTableSemesterOption) {
+		semesterID := strings.TrimPrefix(usefulData, callbackProgressTableSemesterOption)
+		return s.handleSemesterCallback(ctx, c, user, semesterID)
+	}
+
+	usefulData, semesterID, _ := strings.Cut(usefulData, callbackProgressTableSemesterSeparator)
+	progressTable, err := s.storedProgressTable(ctx, user, semesterID)
+	if err != nil {
+		err = fmt.Errorf("storedProgressTable: %w", err)
+		logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
 	if progressTable == nil || len(progressTable.Disciplines) == 0 {
-		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.GradesPageUnavailable)
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	if usefulData == callbackProgressTableImageOption || usefulData == callbackProgressTablePDFOption {
+		return s.sendProgressTableReport(ctx, c, progressTable, usefulData)
 	}
 
 	isHideControlEventsName := true
 	if usefulData == callbackProgressTableBackOption {
+		message, err := s.rendererSvc.ProgressTable(domain.MessageFormatMarkdownV2, language, progressTable)
+		if err != nil {
+			err = fmt.Errorf("rendererSvc.ProgressTable: %w", err)
+			logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
+			return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+		}
+
 		return s.EditMessageWithOpts(
 			c.Sender().ID,
 			c.Message().ID,
-			generateDisciplineListMessage(progressTable.Disciplines),
-			tele.ModeMarkdown,
-			s.generateDisciplineListMarkup(progressTable),
+			message.Text,
+			parseMode(message.Format),
+			s.generateDisciplineListMarkup(language, progressTable, semesterID),
 		)
 	}
 
+	if strings.HasPrefix(usefulData, callbackProgressTableChartOption) {
+		usefulData = strings.TrimPrefix(usefulData, callbackProgressTableChartOption)
+		return s.sendProgressTableChart(ctx, c, user, progressTable, usefulData)
+	}
+
+	isForecast := false
+	if strings.HasPrefix(usefulData, callbackProgressTableForecastOption) {
+		isForecast = true
+		usefulData = strings.TrimPrefix(usefulData, callbackProgressTableForecastOption)
+	}
+
 	if strings.HasPrefix(usefulData, callbackProgressTableDisciplineDetailsOption) {
 		isHideControlEventsName = false
 		usefulData = strings.TrimPrefix(usefulData, callbackProgressTableDisciplineDetailsOption)
 	}
 
-	disciplineNumber, err := strconv.Atoi(usefulData)
+	disciplineNumber, err := strconv.Atoi(usefulData)
+	if err != nil {
+		err = fmt.Errorf("strconv.Atoi: %w", err)
+		logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+	if disciplineNumber > len(progressTable.Disciplines) || disciplineNumber <= 0 {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	if isForecast {
+		message, err := s.rendererSvc.DisciplineForecast(
+			domain.MessageFormatMarkdownV2,
+			language,
+			progressTable.Disciplines[disciplineNumber-1],
+		)
+		if err != nil {
+			err = fmt.Errorf("rendererSvc.DisciplineForecast: %w", err)
+			logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
+			return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.EditMessageWithOpts(
+			c.Sender().ID,
+			c.Message().ID,
+			message.Text,
+			parseMode(message.Format),
+			s.generateDisciplineForecastMarkup(disciplineNumber, semesterID),
+		)
+	}
+
+	var distributions []*domain.GradeDistribution
+	if progressTable == user.ProgressTable {
+		distributions, err = s.courseDistributions(ctx, user.ID, progressTable, disciplineNumber)
+		if err != nil {
+			// без медианы курса оценки всё равно можно показать
+			err = fmt.Errorf("courseDistributions: %w", err)
+			logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
+		}
+	}
+
+	message, err := s.rendererSvc.Discipline(
+		domain.MessageFormatMarkdownV2,
+		language,
+		progressTable.Disciplines[disciplineNumber-1],
+		isHideControlEventsName,
+		distributions,
+	)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.Discipline: %w", err)
+		logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateDisciplineMarkup(language, disciplineNumber, isHideControlEventsName, semesterID),
+	)
+}
+
+// sendProgressTableReport отправляет таблицу картинкой или PDF-отчётом, сообщение со списком дисциплин не меняется
+func (s *svc) sendProgressTableReport(
+	ctx context.Context,
+	c tele.Context,
+	progressTable *domain.ProgressTable,
+	option string,
+) error {
+	logger := log.Ctx(ctx)
+	language := s.language(c)
+
+	var what interface{}
+	if option == callbackProgressTableImageOption {
+		content, err := report.ProgressTableImage(language, progressTable)
+		if err != nil {
+			err = fmt.Errorf("report.ProgressTableImage: %w", err)
+			logger.Error().Msgf("sendProgressTableReport: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+		what = &tele.Photo{File: tele.FromReader(bytes.NewReader(content))}
+	} else {
+		generatedAt := time.Now().In(config.BARSLocation)
+		content, err := report.ProgressTablePDF(language, progressTable, generatedAt)
+		if err != nil {
+			err = fmt.Errorf("report.ProgressTablePDF: %w", err)
+			logger.Error().Msgf("sendProgressTableReport: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+		what = &tele.Document{
+			File:     tele.FromReader(bytes.NewReader(content)),
+			FileName: fmt.Sprintf("report_%s.pdf", generatedAt.Format("2006-01-02")),
+			MIME:     "application/pdf",
+		}
+	}
+
+	_, err := s.bot.Send(tele.ChatID(c.Sender().ID), what)
+
+	return s.middlewareError(c.Sender().ID, err)
+}
+
+// sendProgressTableChart отправляет график динамики балла, построенный по истории изменений оценок
+func (s *svc) sendProgressTableChart(
+	ctx context.Context,
+	c tele.Context,
+	user *domain.User,
+	progressTable *domain.ProgressTable,
+	disciplineNumberData string,
+) error {
+	logger := log.Ctx(ctx)
+	language := s.language(c)
+
+	disciplineNumber, err := strconv.Atoi(disciplineNumberData)
+	if err != nil || disciplineNumber < 0 || disciplineNumber > len(progressTable.Disciplines) {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	history, err := s.userSvc.GradesHistory(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.GradesHistory: %w", err)
+		logger.Error().Msgf("sendProgressTableChart: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	var content []byte
+	if disciplineNumber == 0 {
+		content, err = report.TrendsChart(answers.Text(language, answers.TrendsChartTitle), progressTable.Trends(history))
+		if err != nil {
+			err = fmt.Errorf("report.TrendsChart: %w", err)
+		}
+	} else {
+		discipline := progressTable.Disciplines[disciplineNumber-1]
+		content, err = report.DisciplineTrendChart(discipline.Trend(progressTable.Semester.Name, history))
+		if err != nil {
+			err = fmt.Errorf("report.DisciplineTrendChart: %w", err)
+		}
+	}
+	if errors.Is(err, report.ErrEmptyTrend) {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.TrendsChartUnavailable))
+	}
+	if err != nil {
+		logger.Error().Msgf("sendProgressTableChart: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	_, err = s.bot.Send(tele.ChatID(c.Sender().ID), &tele.Photo{File: tele.FromReader(bytes.NewReader(content))})
+
+	return s.middlewareError(c.Sender().ID, err)
+}
+
+// courseDistributions медиана курса показывается только тем, кто сам делится оценками
+func (s *svc) courseDistributions(
+	ctx context.Context,
+	userID int64,
+	progressTable *domain.ProgressTable,
+	disciplineNumber int,
+) ([]*domain.GradeDistribution, error) {
+	settings, err := s.userSettingsSvc.UserSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsSvc.UserSettings: %w", err)
+	}
+	if settings == nil || !settings.ShareGrades {
+		return nil, nil
+	}
+
+	distributions, err := s.gradeDistributionsSvc.Distributions(
+		ctx,
+		progressTable.Semester.Name,
+		progressTable.Disciplines[disciplineNumber-1].Name,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gradeDistributionsSvc.Distributions: %w", err)
+	}
+
+	return distributions, nil
+}
+
+// storedProgressTable ищет таблицу семестра среди сохранённых, без запросов в БАРС
+func (s *svc) storedProgressTable(
+	ctx context.Context,
+	user *domain.User,
+	semesterID string,
+) (*domain.ProgressTable, error) {
+	if semesterID == "" || (user.ProgressTable != nil && user.ProgressTable.Semester.ID == semesterID) {
+		return user.ProgressTable, nil
+	}
+
+	archivedProgressTables, err := s.userSvc.ArchivedProgressTables(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.ArchivedProgressTables: %w", err)
+	}
+
+	for _, progressTable := range archivedProgressTables {
+		if progressTable.Semester.ID == semesterID {
+			return progressTable, nil
+		}
+	}
+
+	return nil, nil
+}
+
+func (s *svc) handleSemestersCallback(ctx context.Context, c tele.Context, user *domain.User) error {
+	language := s.language(c)
+	logger := log.Ctx(ctx)
+
+	var (
+		semesters []domain.Semester
+		err       error
+	)
+	if !user.BarsCredentials.IsSuspended() {
+		semesters, err = s.barsSvc.Semesters(ctx, user.ID)
+		if err != nil {
+			err = fmt.Errorf("barsSvc.Semesters: %w", err)
+			logger.Error().Msgf("handleSemestersCallback: %v", err.Error())
+		}
+	}
+	// БАРС может быть недоступен или отслеживание приостановлено, показываем хотя бы сохранённые семестры
+	if user.BarsCredentials.IsSuspended() || err != nil {
+		semesters, err = s.storedSemesters(ctx, user)
+		if err != nil {
+			err = fmt.Errorf("storedSemesters: %w", err)
+			logger.Error().Msgf("handleSemestersCallback: %v", err.Error())
+			return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+		}
+	}
+	if len(semesters) == 0 {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.SemestersUnavailable))
+	}
+
+	currentSemesterID := ""
+	if user.ProgressTable != nil {
+		currentSemesterID = user.ProgressTable.Semester.ID
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		answers.Text(language, answers.SemesterChoose),
+		s.generateSemestersMarkup(semesters, currentSemesterID),
+	)
+}
+
+func (s *svc) storedSemesters(ctx context.Context, user *domain.User) ([]domain.Semester, error) {
+	archivedProgressTables, err := s.userSvc.ArchivedProgressTables(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.ArchivedProgressTables: %w", err)
+	}
+
+	semesters := make([]domain.Semester, 0, len(archivedProgressTables)+1)
+	for _, progressTable := range archivedProgressTables {
+		semesters = append(semesters, progressTable.Semester)
+	}
+	if user.ProgressTable != nil && user.ProgressTable.Semester.ID != "" {
+		semesters = append(semesters, user.ProgressTable.Semester)
+	}
+
+	return semesters, nil
+}
+
+func (s *svc) handleSemesterCallback(
+	ctx context.Context,
+	c tele.Context,
+	user *domain.User,
+	semesterID string,
+) error {
+	logger := log.Ctx(ctx)
+	language := s.language(c)
+
+	var (
+		progressTable *domain.ProgressTable
+		err           error
+	)
+	switch {
+	case user.ProgressTable != nil && user.ProgressTable.Semester.ID == semesterID:
+		progressTable = user.ProgressTable
+	case !user.BarsCredentials.IsSuspended():
+		progressTable, err = s.barsSvc.SemesterProgressTable(ctx, user.ID, semesterID)
+		if err != nil {
+			err = fmt.Errorf("barsSvc.SemesterProgressTable: %w", err)
+			logger.Error().Msgf("handleSemesterCallback: %v", err.Error())
+			break
+		}
+		// дальнейшие нажатия по дисциплинам семестра читают таблицу из архива
+		if progressTable != nil && len(progressTable.Disciplines) > 0 {
+			err = s.userSvc.ArchiveProgressTable(ctx, user.ID, progressTable)
+			if err != nil {
+				err = fmt.Errorf("userSvc.ArchiveProgressTable: %w", err)
+				logger.Error().Msgf("handleSemesterCallback: %v", err.Error())
+			}
+		}
+	}
+	// пока отслеживание приостановлено, в БАРС не ходим и показываем сохранённую таблицу
+	if progressTable == nil {
+		progressTable, err = s.storedProgressTable(ctx, user, semesterID)
+		if err != nil {
+			err = fmt.Errorf("storedProgressTable: %w", err)
+			logger.Error().Msgf("handleSemesterCallback: %v", err.Error())
+			return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+		}
+	}
+	if progressTable == nil || len(progressTable.Disciplines) == 0 {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	message, err := s.rendererSvc.ProgressTable(domain.MessageFormatMarkdownV2, language, progressTable)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.ProgressTable: %w", err)
+		logger.Error().Msgf("handleSemesterCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateDisciplineListMarkup(language, progressTable, semesterID),
+	)
+}
+
+func (s *svc) handleStartCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+	err := s.userSvc.Save(ctx, &domain.User{
+		ID: c.Sender().ID,
+	})
+	if err != nil {
+		err = fmt.Errorf("userSvc.Save: %w", err)
+		logger.Error().Msgf("handleStartCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.Start))
+}
+
+func (s *svc) handleHelpCommand(c tele.Context) error {
+	language := s.language(c)
+	if isGroupChat(c.Chat()) {
+		return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.GroupHelp))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.Help))
+}
+
+func (s *svc) handleAuthCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	if c.Message().Payload == "" {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.CredentialsNoEntered))
+	}
+
+	userCredentials := strings.Split(c.Message().Payload, " ")
+
+	if len(userCredentials) != 2 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.CredentialsFormIgnored))
+	}
+
+	username := userCredentials[0]
+	password := userCredentials[1]
+
+	if !isValidUserData(username) {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.CredentialsIncorrectly))
+	}
+
+	// TODO в будущем нужно ввести проверку на то, что нет пользователя с таким username
+	err := s.barsSvc.Authorization(ctx, c.Sender().ID, username, []byte(password))
+	if err != nil {
+		err = fmt.Errorf("barsSvc.Authorization: %w", err)
+	}
+
+	return s.sendAuthorizationResult(c.Sender().ID, language, err, "handleAuthCommand")
+}
+
+func (s *svc) sendAuthorizationResult(
+	userID int64,
+	language domain.Language,
+	err error,
+	handlerName string,
+) error {
+	switch {
+	case errors.Is(err, ierrors.ErrWrongGradesPage):
+		return s.SendMessageWithOpts(userID, answers.Text(language, answers.GradesPageWrong))
+	case errors.Is(err, bars.ErrAuthorizationFailed):
+		return s.SendMessageWithOpts(userID, answers.Text(language, answers.CredentialsWrong))
+	case errors.Is(err, ierrors.ErrAlreadyAuth):
+		return s.SendMessageWithOpts(userID, answers.Text(language, answers.ClientAlreadyAuthorized))
+	case err != nil:
+		log.Error().Int64("sender", userID).Msgf("%s: %v", handlerName, err.Error())
+		return s.SendMessageWithOpts(userID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(userID, answers.Text(language, answers.SuccessfulAuthorization))
+}
+
+func (s *svc) handleReauthorizationCallback(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleReauthorizationCallback: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+	if !user.BarsCredentials.IsSuspended() {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ReauthorizationNotNeeded))
+	}
+
+	// ответ на это сообщение обрабатывается в handleText, состояние хранить не нужно
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ReauthorizationPrompt), tele.ForceReply)
+}
+
+func (s *svc) isReauthorizationReply(message *tele.Message) bool {
+	return message.ReplyTo != nil &&
+		message.ReplyTo.Sender != nil &&
+		message.ReplyTo.Sender.ID == s.bot.Me.ID &&
+		answers.IsText(answers.ReauthorizationPrompt, message.ReplyTo.Text)
+}
+
+func (s *svc) handleReauthorizationReply(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	// сообщение содержит пароль, не оставляем его в истории чата
+	if err := c.Delete(); err != nil {
+		err = fmt.Errorf("c.Delete: %w", err)
+		logger.Error().Msgf("handleReauthorizationReply: %v", err.Error())
+	}
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleReauthorizationReply: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+	if !user.BarsCredentials.IsSuspended() {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ReauthorizationNotNeeded))
+	}
+
+	password := strings.TrimSpace(c.Text())
+	err = s.barsSvc.Authorization(ctx, c.Sender().ID, user.BarsCredentials.Username, []byte(password))
+	if err != nil {
+		err = fmt.Errorf("barsSvc.Authorization: %w", err)
+	}
+
+	return s.sendAuthorizationResult(c.Sender().ID, language, err, "handleReauthorizationReply")
+}
+
+func (s *svc) handleLogoutCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	err := s.barsSvc.Logout(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("barsSvc.Logout: %w", err)
+		logger.Error().Msgf("handleLogoutCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	s.progressTableCache.Delete(c.Sender().ID)
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.SuccessfulLogout))
+}
+
+func (s *svc) handleProgressTableCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("progressTableSvc.User: %w", err)
+		logger.Error().Msgf("handleProgressTableCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+
+	progressTable := user.ProgressTable
+	if progressTable == nil || len(progressTable.Disciplines) == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	message, err := s.rendererSvc.ProgressTable(domain.MessageFormatMarkdownV2, language, progressTable)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.ProgressTable: %w", err)
+		logger.Error().Msgf("handleProgressTableCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(
+		c.Sender().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateDisciplineListMarkup(language, progressTable, ""),
+	)
+}
+
+func (s *svc) handleRecordBookCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleRecordBookCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+
+	recordBook, err := s.userSvc.RecordBook(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.RecordBook: %w", err)
+		logger.Error().Msgf("handleRecordBookCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if recordBook == nil {
+		// зачётная книжка ещё не была получена при проверке изменений
+		recordBook, err = s.barsSvc.RecordBook(ctx, user.ID)
+		if errors.Is(err, ierrors.ErrWrongRecordBookPage) {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.RecordBookUnavailable))
+		}
+		if err != nil {
+			err = fmt.Errorf("barsSvc.RecordBook: %w", err)
+			logger.Error().Msgf("handleRecordBookCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		err = s.userSvc.UpdateRecordBook(ctx, user.ID, recordBook, nil)
+		if err != nil {
+			err = fmt.Errorf("userSvc.UpdateRecordBook: %w", err)
+			logger.Error().Msgf("handleRecordBookCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+	}
+
+	semestersCount := len(recordBook.Semesters())
+	if semestersCount == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.RecordBookEmpty))
+	}
+
+	// по умолчанию показываем последний семестр
+	page := semestersCount - 1
+	message, err := s.rendererSvc.RecordBook(domain.MessageFormatMarkdownV2, language, recordBook, page)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.RecordBook: %w", err)
+		logger.Error().Msgf("handleRecordBookCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(
+		c.Sender().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateRecordBookMarkup(page, semestersCount),
+	)
+}
+
+func (s *svc) handleRecordBookCallback(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	page, err := strconv.Atoi(strings.TrimPrefix(callbackData, callbackRecordBook))
+	if err != nil {
+		err = fmt.Errorf("strconv.Atoi: %w", err)
+		logger.Error().Msgf("handleRecordBookCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	recordBook, err := s.userSvc.RecordBook(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.RecordBook: %w", err)
+		logger.Error().Msgf("handleRecordBookCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+	if recordBook == nil {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.RecordBookUnavailable))
+	}
+
+	semestersCount := len(recordBook.Semesters())
+	if page < 0 || page >= semestersCount {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.RecordBookEmpty))
+	}
+
+	message, err := s.rendererSvc.RecordBook(domain.MessageFormatMarkdownV2, language, recordBook, page)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.RecordBook: %w", err)
+		logger.Error().Msgf("handleRecordBookCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateRecordBookMarkup(page, semestersCount),
+	)
+}
+
+func (s *svc) handleScheduleCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleScheduleCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+
+	schedule, err := s.scheduleSvc.Schedule(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("scheduleSvc.Schedule: %w", err)
+		logger.Error().Msgf("handleScheduleCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ScheduleUnavailable))
+	}
+
+	period := domain.SchedulePeriodToday
+	from, to := scheduleRange(period, time.Now())
+	message, err := s.rendererSvc.Schedule(domain.MessageFormatMarkdownV2, language, schedule, period, from, to)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.Schedule: %w", err)
+		logger.Error().Msgf("handleScheduleCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(
+		c.Sender().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateScheduleMarkup(language),
+	)
+}
+
+func (s *svc) handleScheduleCallback(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	period := domain.SchedulePeriod(strings.TrimPrefix(callbackData, callbackSchedule))
+
+	schedule, err := s.scheduleSvc.Schedule(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("scheduleSvc.Schedule: %w", err)
+		logger.Error().Msgf("handleScheduleCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.ScheduleUnavailable))
+	}
+
+	from, to := scheduleRange(period, time.Now())
+	message, err := s.rendererSvc.Schedule(domain.MessageFormatMarkdownV2, language, schedule, period, from, to)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.Schedule: %w", err)
+		logger.Error().Msgf("handleScheduleCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		message.Text,
+		parseMode(message.Format),
+		s.generateScheduleMarkup(language),
+	)
+}
+
+func (s *svc) handleLanguageCommand(c tele.Context) error {
+	language := s.language(c)
+
+	if c.Message().Payload == "" {
+		return s.SendMessageWithOpts(
+			c.Sender().ID,
+			answers.Text(language, answers.LanguageChoose),
+			s.generateLanguageMarkup(),
+		)
+	}
+
+	newLanguage, ok := domain.ParseLanguage(strings.TrimSpace(c.Message().Payload))
+	if !ok {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.LanguageUnknown))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, s.setLanguage(c, newLanguage))
+}
+
+func (s *svc) handleLanguageCallback(c tele.Context) error {
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	newLanguage, ok := domain.ParseLanguage(strings.TrimPrefix(callbackData, callbackLanguage))
+	if !ok {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.LanguageUnknown))
+	}
+
+	return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, s.setLanguage(c, newLanguage))
+}
+
+// setLanguage сохраняет язык пользователя и возвращает ответ уже на новом языке
+func (s *svc) setLanguage(c tele.Context, language domain.Language) string {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	err := s.userSettingsSvc.SetLanguage(ctx, c.Sender().ID, language)
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetLanguage: %w", err)
+		logger.Error().Msgf("setLanguage: %v", err.Error())
+		return answers.Text(s.language(c), answers.BotError)
+	}
+	s.languageCache.Set(c.Sender().ID, language, ttlcache.DefaultTTL)
+
+	return answers.Text(language, answers.LanguageChanged)
+}
+
+func (s *svc) handleEmailCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	if c.Message().Payload == "" {
+		settings, err := s.userSettingsSvc.UserSettings(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("userSettingsSvc.UserSettings: %w", err)
+			logger.Error().Msgf("handleEmailCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+		if settings == nil || settings.Email == "" {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.EmailUsage))
+		}
+
+		status := answers.Text(language, answers.EmailStatusNotVerified)
+		if settings.IsEmailVerified() {
+			status = answers.Text(language, answers.EmailStatusVerified)
+		}
+
+		return s.SendMessageWithOpts(
+			c.Sender().ID,
+			answers.Textf(
+				language,
+				answers.EmailStatus,
+				settings.Email,
+				status,
+				notificationChannelsText(language, settings.NotificationChannels),
+			),
+		)
+	}
+
+	email := strings.TrimSpace(c.Message().Payload)
+	err := s.userSettingsSvc.RequestEmailVerification(ctx, c.Sender().ID, email)
+	switch {
+	case errors.Is(err, ierrors.ErrInvalidEmail):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.EmailInvalid))
+	case errors.Is(err, ierrors.ErrVerificationResendTooSoon):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.EmailResendTooSoon))
+	case errors.Is(err, ierrors.ErrVerificationCodesLimit):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.EmailCodesLimit))
+	case errors.Is(err, ierrors.ErrVerificationAttemptsEnded):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.VerificationAttemptsEnded))
+	case err != nil:
+		err = fmt.Errorf("userSettingsSvc.RequestEmailVerification: %w", err)
+		logger.Error().Msgf("handleEmailCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.EmailSendFailed))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.EmailCodeSent, email))
+}
+
+func (s *svc) handleVerifyCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	code := strings.TrimSpace(c.Message().Payload)
+	if code == "" {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.VerifyUsage))
+	}
+
+	settings, err := s.userSettingsSvc.VerifyEmail(ctx, c.Sender().ID, code)
+	switch {
+	case errors.Is(err, ierrors.ErrVerificationNotFound):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.VerificationNotFound))
+	case errors.Is(err, ierrors.ErrVerificationExpired):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.VerificationExpired))
+	case errors.Is(err, ierrors.ErrWrongVerificationCode):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.VerificationWrongCode))
+	case errors.Is(err, ierrors.ErrVerificationAttemptsEnded):
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.VerificationAttemptsEnded))
+	case err != nil:
+		err = fmt.Errorf("userSettingsSvc.VerifyEmail: %w", err)
+		logger.Error().Msgf("handleVerifyCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.EmailVerified, settings.Email))
+}
+
+func (s *svc) handleNotifyCommand(c tele.Context) error {
+	language := s.language(c)
+
+	if c.Message().Payload == "" {
+		return s.SendMessageWithOpts(
+			c.Sender().ID,
+			answers.Text(language, answers.NotifyChoose),
+			s.generateNotifyMarkup(language),
+		)
+	}
+
+	channels, ok := parseNotificationChannels(c.Message().Payload)
+	if !ok {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.NotifyUnknown))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, s.setNotificationChannels(c, channels))
+}
+
+func (s *svc) handleNotifyCallback(c tele.Context) error {
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	channels, ok := parseNotificationChannels(strings.TrimPrefix(callbackData, callbackNotify))
+	if !ok {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.NotifyUnknown))
+	}
+
+	return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, s.setNotificationChannels(c, channels))
+}
+
+func (s *svc) setNotificationChannels(c tele.Context, channels []domain.NotificationChannel) string {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	err := s.userSettingsSvc.SetNotificationChannels(ctx, c.Sender().ID, channels)
+	if errors.Is(err, ierrors.ErrEmailNotVerified) {
+		return answers.Text(language, answers.NotifyEmailNotVerified)
+	}
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetNotificationChannels: %w", err)
+		logger.Error().Msgf("setNotificationChannels: %v", err.Error())
+		return answers.Text(language, answers.BotError)
+	}
+
+	return answers.Textf(language, answers.NotifyChanged, notificationChannelsText(language, channels))
+}
+
+func parseNotificationChannels(option string) ([]domain.NotificationChannel, bool) {
+	if strings.ToLower(strings.TrimSpace(option)) == callbackNotifyBothOption {
+		return domain.NotificationChannels, true
+	}
+
+	channel, ok := domain.ParseNotificationChannel(option)
+	if !ok {
+		return nil, false
+	}
+
+	return []domain.NotificationChannel{channel}, true
+}
+
+func notificationChannelsText(language domain.Language, channels []domain.NotificationChannel) string {
+	names := make([]string, 0, len(channels))
+	for _, channel := range channels {
+		switch channel {
+		case domain.NotificationChannelTelegram:
+			names = append(names, answers.Text(language, answers.NotifyTelegramButton))
+		case domain.NotificationChannelEmail:
+			names = append(names, answers.Text(language, answers.NotifyEmailButton))
+		}
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// handleRemindCommand /remind, /remind summary on|off, /remind days N
+func (s *svc) handleRemindCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	args := strings.Fields(strings.ToLower(c.Message().Payload))
+	var (
+		settings *domain.UserSettings
+		err      error
+	)
+	switch {
+	case len(args) == 0:
+		settings, err = s.userSettingsSvc.UserSettings(ctx, c.Sender().ID)
+		if err == nil && settings == nil {
+			settings = domain.NewUserSettings(c.Sender().ID, language)
+		}
+	case len(args) == 2 && args[0] == "summary" && (args[1] == "on" || args[1] == "off"):
+		settings, err = s.userSettingsSvc.SetWeeklySummary(ctx, c.Sender().ID, args[1] == "on")
+	case len(args) == 2 && args[0] == "days":
+		days, parseErr := strconv.Atoi(args[1])
+		if parseErr != nil {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ReminderUsage))
+		}
+		settings, err = s.userSettingsSvc.SetReminderDaysBefore(ctx, c.Sender().ID, days)
+	default:
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ReminderUsage))
+	}
+	if errors.Is(err, ierrors.ErrInvalidReminderDays) {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ReminderUsage))
+	}
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetReminderDaysBefore: %w", err)
+		logger.Error().Msgf("handleRemindCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(
+		c.Sender().ID,
+		generateReminderSettingsMessage(language, settings),
+		s.generateRemindMarkup(language, settings),
+	)
+}
+
+func (s *svc) handleRemindCallback(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	usefulData := strings.TrimPrefix(callbackData, callbackRemind)
+
+	var (
+		settings *domain.UserSettings
+		err      error
+	)
+	switch {
+	case strings.HasPrefix(usefulData, callbackRemindSummaryOption):
+		enabled := strings.TrimPrefix(usefulData, callbackRemindSummaryOption) == "1"
+		settings, err = s.userSettingsSvc.SetWeeklySummary(ctx, c.Sender().ID, enabled)
+	case strings.HasPrefix(usefulData, callbackRemindDaysOption):
+		days, parseErr := strconv.Atoi(strings.TrimPrefix(usefulData, callbackRemindDaysOption))
+		if parseErr != nil {
+			return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+		}
+		settings, err = s.userSettingsSvc.SetReminderDaysBefore(ctx, c.Sender().ID, days)
+	default:
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetReminderDaysBefore: %w", err)
+		logger.Error().Msgf("handleRemindCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		generateReminderSettingsMessage(language, settings),
+		s.generateRemindMarkup(language, settings),
+	)
+}
+
+func generateReminderSettingsMessage(language domain.Language, settings *domain.UserSettings) string {
+	summary := answers.Text(language, answers.ReminderSummaryOff)
+	if settings.WeeklySummary {
+		summary = answers.Text(language, answers.ReminderSummaryOn)
+	}
+
+	days := answers.Text(language, answers.ReminderDaysOff)
+	if settings.ReminderDaysBefore > 0 {
+		days = answers.Textf(language, answers.ReminderDaysOn, settings.ReminderDaysBefore)
+	}
+
+	return answers.Textf(language, answers.ReminderSettings, summary, days)
+}
+
+// handleShareCommand /share, /share on|off
+func (s *svc) handleShareCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	var (
+		settings *domain.UserSettings
+		err      error
+	)
+	switch strings.ToLower(strings.TrimSpace(c.Message().Payload)) {
+	case "":
+		settings, err = s.userSettingsSvc.UserSettings(ctx, c.Sender().ID)
+		if err == nil && settings == nil {
+			settings = domain.NewUserSettings(c.Sender().ID, language)
+		}
+	case "on":
+		settings, err = s.userSettingsSvc.SetShareGrades(ctx, c.Sender().ID, true)
+	case "off":
+		settings, err = s.userSettingsSvc.SetShareGrades(ctx, c.Sender().ID, false)
+	default:
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ShareUsage))
+	}
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetShareGrades: %w", err)
+		logger.Error().Msgf("handleShareCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(
+		c.Sender().ID,
+		generateShareSettingsMessage(language, settings),
+		s.generateShareMarkup(language, settings),
+	)
+}
+
+func (s *svc) handleShareCallback(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	enabled := strings.TrimPrefix(callbackData, callbackShare) == "1"
+
+	settings, err := s.userSettingsSvc.SetShareGrades(ctx, c.Sender().ID, enabled)
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetShareGrades: %w", err)
+		logger.Error().Msgf("handleShareCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.EditMessageWithOpts(
+		c.Sender().ID,
+		c.Message().ID,
+		generateShareSettingsMessage(language, settings),
+		s.generateShareMarkup(language, settings),
+	)
+}
+
+func generateShareSettingsMessage(language domain.Language, settings *domain.UserSettings) string {
+	state := answers.Text(language, answers.ShareOff)
+	if settings.ShareGrades {
+		state = answers.Text(language, answers.ShareOn)
+	}
+
+	return answers.Textf(language, answers.ShareSettings, state)
+}
+
+// handleInlineQuery "@бот дисциплина" в любом чате: оценки по подходящим дисциплинам из сохранённой таблицы,
+// в БАРС за ними бот не ходит
+func (s *svc) handleInlineQuery(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	progressTable, err := s.inlineProgressTable(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("inlineProgressTable: %w", err)
+		logger.Error().Msgf("handleInlineQuery: %v", err.Error())
+		return c.Answer(&tele.QueryResponse{Results: tele.Results{}, IsPersonal: true})
+	}
+	if progressTable == nil {
+		return c.Answer(&tele.QueryResponse{
+			Results:           tele.Results{},
+			IsPersonal:        true,
+			SwitchPMText:      answers.Text(language, answers.InlineNotAuthorized),
+			SwitchPMParameter: inlineStartParameter,
+		})
+	}
+	if len(progressTable.Disciplines) == 0 {
+		return c.Answer(&tele.QueryResponse{
+			Results:           tele.Results{},
+			IsPersonal:        true,
+			SwitchPMText:      answers.Text(language, answers.InlineNoGrades),
+			SwitchPMParameter: inlineStartParameter,
+		})
+	}
+
+	disciplineNumbers := matchDisciplines(progressTable.Disciplines, c.Query().Text, inlineResultsLimit)
+	results := make(tele.Results, 0, len(disciplineNumbers))
+	for _, disciplineNumber := range disciplineNumbers {
+		discipline := progressTable.Disciplines[disciplineNumber-1]
+		message, err := s.rendererSvc.Discipline(domain.MessageFormatMarkdownV2, language, discipline, false, nil)
+		if err != nil {
+			err = fmt.Errorf("rendererSvc.Discipline: %w", err)
+			logger.Error().Msgf("handleInlineQuery: %v", err.Error())
+			continue
+		}
+
+		results = append(results, &tele.ArticleResult{
+			ResultBase: tele.ResultBase{
+				ID: strconv.Itoa(disciplineNumber),
+				Content: &tele.InputTextMessageContent{
+					Text:      message.Text,
+					ParseMode: parseMode(message.Format),
+				},
+			},
+			Title:       discipline.Name,
+			Description: disciplineDescription(language, discipline.Statistics()),
+		})
+	}
+
+	return c.Answer(&tele.QueryResponse{
+		Results:    results,
+		CacheTime:  int(s.cfg.InlineCacheTTL.Seconds()),
+		IsPersonal: true,
+	})
+}
+
+// inlineProgressTable nil, если пользователь не авторизован или таблица ещё не получена
+func (s *svc) inlineProgressTable(ctx context.Context, userID int64) (*domain.ProgressTable, error) {
+	if item := s.progressTableCache.Get(userID); item != nil {
+		return item.Value(), nil
+	}
+
+	user, err := s.userSvc.User(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSvc.User: %w", err)
+	}
+	// неавторизованного не кэшируем, чтобы после /auth оценки появились сразу
+	if user == nil || user.BarsCredentials == nil || user.ProgressTable == nil {
+		return nil, nil
+	}
+
+	s.progressTableCache.Set(userID, user.ProgressTable, ttlcache.DefaultTTL)
+
+	return user.ProgressTable, nil
+}
+
+// matchDisciplines номера подходящих под запрос дисциплин, начиная с 1, от лучшего совпадения к худшему
+func matchDisciplines(disciplines []domain.Discipline, query string, limit int) []int {
+	type match struct {
+		number int
+		score  int
+	}
+
+	matches := make([]match, 0, len(disciplines))
+	for i, discipline := range disciplines {
+		score, ok := fuzzy.Score(query, discipline.Name)
+		if ok {
+			matches = append(matches, match{number: i + 1, score: score})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	numbers := make([]int, 0, len(matches))
+	for _, m := range matches {
+		numbers = append(numbers, m.number)
+	}
+
+	return numbers
+}
+
+func disciplineDescription(language domain.Language, statistics domain.DisciplineStatistics) string {
+	average := answers.Text(language, answers.StatisticsNoGrades)
+	if statistics.HasAverage {
+		average = fmt.Sprintf("%.2f", statistics.Average)
+	}
+
+	return answers.Textf(
+		language,
+		answers.InlineDisciplineDescription,
+		average,
+		statistics.GradedCount,
+		statistics.PendingCount,
+	)
+}
+
+func (s *svc) handleJoinCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	if !isGroupChat(c.Chat()) {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.GroupOnlyCommand))
+	}
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleJoinCommand: %v", err.Error())
+		return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.GroupNotAuthorized))
+	}
+
+	err = s.chatsSvc.Join(ctx, newChat(c.Chat(), language), c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("chatsSvc.Join: %w", err)
+		logger.Error().Msgf("handleJoinCommand: %v", err.Error())
+		return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.GroupJoined))
+}
+
+func (s *svc) handleLeaveCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	if !isGroupChat(c.Chat()) {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.GroupOnlyCommand))
+	}
+
+	err := s.chatsSvc.Leave(ctx, c.Chat().ID, c.Sender().ID)
+	if errors.Is(err, ierrors.ErrNotChatMember) {
+		return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.GroupNotMember))
+	}
+	if err != nil {
+		err = fmt.Errorf("chatsSvc.Leave: %w", err)
+		logger.Error().Msgf("handleLeaveCommand: %v", err.Error())
+		return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.sendChatMessageWithOpts(c.Chat().ID, answers.Text(language, answers.GroupLeft))
+}
+
+// handleMyChatMember бота добавили в групповой чат или убрали из него. Язык чата - язык того, кто добавил бота
+func (s *svc) handleMyChatMember(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	update := c.ChatMember()
+	if update.NewChatMember == nil || update.OldChatMember == nil {
+		return nil
+	}
+
+	if isOutsideChat(update.NewChatMember.Role) {
+		err := s.chatsSvc.Remove(ctx, update.Chat.ID)
+		if err != nil {
+			err = fmt.Errorf("chatsSvc.Remove: %w", err)
+			logger.Error().Msgf("handleMyChatMember: %v", err.Error())
+		}
+
+		return nil
+	}
+	// изменились права бота в чате, где он уже есть
+	if !isOutsideChat(update.OldChatMember.Role) {
+		return nil
+	}
+
+	err := s.chatsSvc.Register(ctx, newChat(update.Chat, language))
+	if err != nil {
+		err = fmt.Errorf("chatsSvc.Register: %w", err)
+		logger.Error().Msgf("handleMyChatMember: %v", err.Error())
+		return nil
+	}
+
+	return s.sendChatMessageWithOpts(update.Chat.ID, answers.Text(language, answers.GroupHelp))
+}
+
+func isOutsideChat(role tele.MemberStatus) bool {
+	return role == tele.Left || role == tele.Kicked
+}
+
+// handleMigration группа стала супергруппой и получила новый идентификатор
+func (s *svc) handleMigration(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	fromChatID, toChatID := c.Migration()
+	err := s.chatsSvc.Migrate(ctx, fromChatID, toChatID)
+	if err != nil {
+		err = fmt.Errorf("chatsSvc.Migrate: %w", err)
+		logger.Error().Msgf("handleMigration: %v", err.Error())
+	}
+
+	return nil
+}
+
+// handleUserLeft участник вышел из группы или его удалили: сообщения о его оценках в чат больше не приходят
+func (s *svc) handleUserLeft(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	err := s.chatsSvc.Leave(ctx, c.Chat().ID, c.Message().UserLeft.ID)
+	if err != nil && !errors.Is(err, ierrors.ErrNotChatMember) {
+		err = fmt.Errorf("chatsSvc.Leave: %w", err)
+		logger.Error().Msgf("handleUserLeft: %v", err.Error())
+	}
+
+	return nil
+}
+
+func newChat(chat *tele.Chat, language domain.Language) *domain.Chat {
+	return &domain.Chat{
+		ID:        chat.ID,
+		Title:     chat.Title,
+		Language:  language,
+		CreatedAt: time.Now(),
+	}
+}
+
+// handleWebhooksCommand /webhooks, /webhooks add URL, /webhooks test ID, /webhooks remove ID
+func (s *svc) handleWebhooksCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	args := strings.Fields(c.Message().Payload)
+	if len(args) == 0 {
+		webhooks, err := s.webhooksSvc.Webhooks(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("webhooksSvc.Webhooks: %w", err)
+			logger.Error().Msgf("handleWebhooksCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(
+			c.Sender().ID,
+			generateWebhooksMessage(language, webhooks),
+			s.generateWebhooksMarkup(language, webhooks),
+			tele.NoPreview,
+		)
+	}
+
+	switch {
+	case args[0] == "add" && len(args) == 2:
+		webhook, secret, err := s.webhooksSvc.AddWebhook(ctx, c.Sender().ID, args[1])
+		switch {
+		case errors.Is(err, ierrors.ErrInvalidWebhookURL):
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.WebhookInvalidURL))
+		case errors.Is(err, ierrors.ErrWebhooksLimitReached):
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.WebhooksLimitReached))
+		case err != nil:
+			err = fmt.Errorf("webhooksSvc.AddWebhook: %w", err)
+			logger.Error().Msgf("handleWebhooksCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(
+			c.Sender().ID,
+			answers.Textf(language, answers.WebhookAdded, webhook.ID, webhook.URL, secret),
+			tele.NoPreview,
+		)
+	case args[0] == "test" && len(args) == 2:
+		webhookID, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.WebhookNotFound))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, s.testWebhook(c, webhookID))
+	case args[0] == "remove" && len(args) == 2:
+		webhookID, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.WebhookNotFound))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, s.removeWebhook(c, webhookID))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.WebhooksUsage))
+}
+
+func (s *svc) handleWebhooksCallback(c tele.Context) error {
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	usefulData := strings.TrimPrefix(callbackData, callbackWebhooks)
+
+	var option string
+	switch {
+	case strings.HasPrefix(usefulData, callbackWebhooksTestOption):
+		option = callbackWebhooksTestOption
+	case strings.HasPrefix(usefulData, callbackWebhooksRemoveOption):
+		option = callbackWebhooksRemoveOption
+	default:
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	webhookID, err := strconv.ParseInt(strings.TrimPrefix(usefulData, option), 10, 64)
+	if err != nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	if option == callbackWebhooksTestOption {
+		return s.SendMessageWithOpts(c.Sender().ID, s.testWebhook(c, webhookID))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, s.removeWebhook(c, webhookID))
+}
+
+func (s *svc) testWebhook(c tele.Context, webhookID int64) string {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	err := s.webhooksSvc.SendTest(ctx, c.Sender().ID, webhookID)
+	if errors.Is(err, ierrors.ErrWebhookNotFound) {
+		return answers.Text(language, answers.WebhookNotFound)
+	}
+	if err != nil {
+		// ошибка на стороне получателя, пользователю она нужна для отладки своего сервиса
+		return answers.Textf(language, answers.WebhookTestFailed, webhookID, err.Error())
+	}
+
+	return answers.Textf(language, answers.WebhookTestSucceeded, webhookID)
+}
+
+func (s *svc) removeWebhook(c tele.Context, webhookID int64) string {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	err := s.webhooksSvc.DeleteWebhook(ctx, c.Sender().ID, webhookID)
+	if errors.Is(err, ierrors.ErrWebhookNotFound) {
+		return answers.Text(language, answers.WebhookNotFound)
+	}
+	if err != nil {
+		err = fmt.Errorf("webhooksSvc.DeleteWebhook: %w", err)
+		logger.Error().Msgf("removeWebhook: %v", err.Error())
+		return answers.Text(language, answers.BotError)
+	}
+
+	return answers.Textf(language, answers.WebhookRemoved, webhookID)
+}
+
+func generateWebhooksMessage(language domain.Language, webhooks []*domain.Webhook) string {
+	var b strings.Builder
+	if len(webhooks) == 0 {
+		b.WriteString(answers.Text(language, answers.WebhooksEmpty))
+	} else {
+		b.WriteString(answers.Text(language, answers.WebhooksHeader))
+		for _, webhook := range webhooks {
+			b.WriteString("\n")
+			b.WriteString(answers.Textf(language, answers.WebhooksItem, webhook.ID, webhook.URL))
+		}
+	}
+	b.WriteString("\n\n")
+	b.WriteString(answers.Text(language, answers.WebhooksUsage))
+
+	return b.String()
+}
+
+// handleTokenCommand /token, /token new, /token revoke ID
+func (s *svc) handleTokenCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	args := strings.Fields(c.Message().Payload)
+	switch {
+	case len(args) == 0:
+		tokens, err := s.accessTokensSvc.AccessTokens(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("accessTokensSvc.AccessTokens: %w", err)
+			logger.Error().Msgf("handleTokenCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, generateTokensMessage(language, tokens))
+	case args[0] == "new" && len(args) == 1:
+		user, err := s.userSvc.User(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("userSvc.User: %w", err)
+			logger.Error().Msgf("handleTokenCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+		if user == nil || user.BarsCredentials == nil {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+		}
+
+		token, plainToken, err := s.accessTokensSvc.Issue(ctx, c.Sender().ID)
+		if errors.Is(err, ierrors.ErrAccessTokensLimitReached) {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.TokensLimitReached))
+		}
+		if err != nil {
+			err = fmt.Errorf("accessTokensSvc.Issue: %w", err)
+			logger.Error().Msgf("handleTokenCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(
+			c.Sender().ID,
+			answers.Textf(language, answers.TokenIssued, token.ID, plainToken, token.ID),
+		)
+	case args[0] == "revoke" && len(args) == 2:
+		tokenID, err := strconv.ParseInt(strings.TrimPrefix(args[1], "#"), 10, 64)
+		if err != nil {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.TokenNotFound))
+		}
+
+		err = s.accessTokensSvc.Revoke(ctx, c.Sender().ID, tokenID)
+		if errors.Is(err, ierrors.ErrAccessTokenNotFound) {
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.TokenNotFound))
+		}
+		if err != nil {
+			err = fmt.Errorf("accessTokensSvc.Revoke: %w", err)
+			logger.Error().Msgf("handleTokenCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.TokenRevoked, tokenID))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.TokensUsage))
+}
+
+// handleCalendarCommand /calendar, /calendar rotate
+func (s *svc) handleCalendarCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleCalendarCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+
+	switch strings.TrimSpace(c.Message().Payload) {
+	case "":
+		feedURL, err := s.calendarSvc.FeedURL(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("calendarSvc.FeedURL: %w", err)
+			logger.Error().Msgf("handleCalendarCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.CalendarFeedURL, feedURL))
+	case "rotate":
+		feedURL, err := s.calendarSvc.RotateFeedURL(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("calendarSvc.RotateFeedURL: %w", err)
+			logger.Error().Msgf("handleCalendarCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.CalendarFeedRotated, feedURL))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.CalendarUsage))
+}
+
+// handleFeedCommand /feed, /feed rotate
+func (s *svc) handleFeedCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleFeedCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+
+	switch strings.TrimSpace(c.Message().Payload) {
+	case "":
+		feedURL, err := s.gradesFeedSvc.FeedURL(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("gradesFeedSvc.FeedURL: %w", err)
+			logger.Error().Msgf("handleFeedCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.GradesFeedURL, feedURL))
+	case "rotate":
+		feedURL, err := s.gradesFeedSvc.RotateFeedURL(ctx, c.Sender().ID)
+		if err != nil {
+			err = fmt.Errorf("gradesFeedSvc.RotateFeedURL: %w", err)
+			logger.Error().Msgf("handleFeedCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.GradesFeedRotated, feedURL))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.GradesFeedUsage))
+}
+
+func generateTokensMessage(language domain.Language, tokens []*domain.AccessToken) string {
+	const dateLayout = "02.01.2006 15:04"
+
+	var b strings.Builder
+	if len(tokens) == 0 {
+		b.WriteString(answers.Text(language, answers.TokensEmpty))
+	} else {
+		b.WriteString(answers.Text(language, answers.TokensHeader))
+		for _, token := range tokens {
+			lastUsed := answers.Text(language, answers.TokenNeverUsed)
+			if token.LastUsedAt != nil {
+				lastUsed = token.LastUsedAt.In(config.BARSLocation).Format(dateLayout)
+			}
+
+			b.WriteString("\n")
+			b.WriteString(answers.Textf(
+				language,
+				answers.TokensItem,
+				token.ID,
+				token.CreatedAt.In(config.BARSLocation).Format(dateLayout),
+				lastUsed,
+			))
+		}
+	}
+	b.WriteString("\n\n")
+	b.WriteString(answers.Text(language, answers.TokensUsage))
+
+	return b.String()
+}
+
+func (s *svc) handleStatisticsCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleStatisticsCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+	if user.ProgressTable == nil || len(user.ProgressTable.Disciplines) == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	archivedProgressTables, err := s.userSvc.ArchivedProgressTables(ctx, user.ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.ArchivedProgressTables: %w", err)
+		logger.Error().Msgf("handleStatisticsCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	archivedStatistics := make([]*domain.SemesterStatistics, 0, len(archivedProgressTables))
+	for _, progressTable := range archivedProgressTables {
+		archivedStatistics = append(archivedStatistics, progressTable.Statistics())
+	}
+
+	message, err := s.rendererSvc.Statistics(
+		domain.MessageFormatMarkdownV2,
+		language,
+		user.ProgressTable.Statistics(),
+		archivedStatistics,
+	)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.Statistics: %w", err)
+		logger.Error().Msgf("handleStatisticsCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, message.Text, parseMode(message.Format))
+}
+
+func (s *svc) handleExportCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleExportCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+	if user.ProgressTable == nil || len(user.ProgressTable.Disciplines) == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.ExportChooseFormat), s.generateExportMarkup(language))
+}
+
+func (s *svc) handleExportCallback(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+	language := s.language(c)
+
+	callbackData := strings.Replace(c.Callback().Data, "\f", "", -1)
+	usefulData := strings.TrimPrefix(callbackData, callbackExport)
+	formatData, withHistory := strings.CutSuffix(usefulData, callbackExportHistorySuffix)
+
+	format, err := export.ParseFormat(formatData)
+	if err != nil {
+		err = fmt.Errorf("export.ParseFormat: %w", err)
+		logger.Error().Msgf("handleExportCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	user, err := s.userSvc.User(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.User: %w", err)
+		logger.Error().Msgf("handleExportCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+	if user == nil || user.BarsCredentials == nil {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.ClientNotAuthorized))
+	}
+	if user.ProgressTable == nil || len(user.ProgressTable.Disciplines) == 0 {
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.GradesPageUnavailable))
+	}
+
+	data := &export.Data{
+		ProgressTable: user.ProgressTable,
+		GeneratedAt:   time.Now().In(config.BARSLocation),
+		Language:      language,
+	}
+	if withHistory {
+		data.History, err = s.userSvc.GradesHistory(ctx, user.ID)
+		if err != nil {
+			err = fmt.Errorf("userSvc.GradesHistory: %w", err)
+			logger.Error().Msgf("handleExportCallback: %v", err.Error())
+			return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+		}
+		if data.History == nil {
+			data.History = make([]*domain.GradeChange, 0)
+		}
+	}
+
+	file, err := export.Export(format, data)
+	if err != nil {
+		err = fmt.Errorf("export.Export: %w", err)
+		logger.Error().Msgf("handleExportCallback: %v", err.Error())
+		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.Text(language, answers.BotError))
+	}
+
+	document := &tele.Document{
+		File:     tele.FromReader(bytes.NewReader(file.Content)),
+		FileName: file.Name,
+		MIME:     file.MIMEType,
+	}
+	_, err = s.bot.Send(tele.ChatID(c.Sender().ID), document)
+
+	return s.middlewareError(c.Sender().ID, err)
+}
+
+func (s *svc) handleGithubCommand(c tele.Context) error {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	language := s.language(c)
+
+	message, err := s.rendererSvc.Github(domain.MessageFormatMarkdownV2, language)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.Github: %w", err)
+		logger.Error().Msgf("handleGithubCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, message.Text, parseMode(message.Format))
+}
+
+func (s *svc) handleText(c tele.Context) error {
+	language := s.language(c)
+	if s.isReauthorizationReply(c.Message()) {
+		return s.handleReauthorizationReply(c)
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.Default))
+}
+
+func (s *svc) handleAdminEchoCommand(c tele.Context) error {
+	language := s.language(c)
+	input := strings.SplitN(c.Text(), " ", 2)
+	if len(input) <= 1 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminInvalidArgument))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, input[1])
+}
+
+func (s *svc) handleAdminSendMessageCommand(c tele.Context) error {
+	language := s.language(c)
+	input := strings.SplitN(c.Text(), " ", 3)
+	if len(input) <= 2 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminInvalidArgument))
+	}
+
+	userID, err := strconv.Atoi(input[1])
+	if err != nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminInvalidArgument))
+	}
+
+	err = s.SendMessageWithOpts(int64(userID), input[2])
+	if err != nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.AdminMessageSent, userID, input[2]))
+}
+
+// handleAdminBroadcastCommand /abroadcast [send Аудитория[:язык] Текст | Номер | pause|resume|cancel Номер]
+func (s *svc) handleAdminBroadcastCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	args := strings.Fields(c.Message().Payload)
+	switch {
+	case len(args) == 0:
+		return s.SendMessageWithOpts(c.Sender().ID, s.adminBroadcasts(ctx, language))
+	case args[0] == "send":
+		// текст берётся из c.Text(): в Payload телебот не включает строки после первой
+		cmdArgs, text := cutArgs(c.Text(), 3)
+		if len(cmdArgs) < 3 || text == "" {
+			break
+		}
+		filter, ok := domain.ParseBroadcastFilter(cmdArgs[2])
+		if !ok {
+			break
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, s.createBroadcast(ctx, language, c.Sender().ID, text, filter))
+	case len(args) == 1:
+		broadcastID, err := parseBroadcastID(args[0])
+		if err != nil {
+			break
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, s.adminBroadcastAction(ctx, language, "", broadcastID))
+	case len(args) == 2 && (args[0] == "pause" || args[0] == "resume" || args[0] == "cancel"):
+		broadcastID, err := parseBroadcastID(args[1])
+		if err != nil {
+			break
+		}
+
+		return s.SendMessageWithOpts(c.Sender().ID, s.adminBroadcastAction(ctx, language, args[0], broadcastID))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminBroadcastUsage))
+}
+
+func (s *svc) adminBroadcasts(ctx context.Context, language domain.Language) string {
+	broadcasts, err := s.broadcastsSvc.Broadcasts(ctx)
 	if err != nil {
-		logger.Error().Msgf("handleProgressTableCallback: %v", err.Error())
-		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.BotError)
+		err = fmt.Errorf("broadcastsSvc.Broadcasts: %w", err)
+		log.Ctx(ctx).Error().Msgf("adminBroadcasts: %v", err.Error())
+		return answers.Text(language, answers.BotError)
 	}
-	if disciplineNumber > len(user.ProgressTable.Disciplines) || disciplineNumber <= 0 {
-		return s.EditMessageWithOpts(c.Sender().ID, c.Message().ID, answers.GradesPageUnavailable)
+	if len(broadcasts) == 0 {
+		return answers.Text(language, answers.AdminBroadcastsEmpty)
 	}
 
-	return s.EditMessageWithOpts(
-		c.Sender().ID,
-		c.Message().ID,
-		generateDisciplineInfoMessage(
-			user.ProgressTable.Disciplines[disciplineNumber-1],
-			isHideControlEventsName,
-		),
-		tele.ModeMarkdown,
-		s.generateDisciplineMarkup(disciplineNumber, isHideControlEventsName),
-	)
+	var b strings.Builder
+	b.WriteString(answers.Text(language, answers.AdminBroadcastsHeader))
+	for _, broadcast := range broadcasts {
+		b.WriteString(adminBroadcast(language, broadcast))
+	}
+
+	return b.String()
 }
 
-func (s *svc) handleStartCommand(c tele.Context) error {
-	logger := log.With().Fields(extractTelebotFields(c)).Logger()
-	ctx := logger.WithContext(context.Background())
-	err := s.userSvc.Save(ctx, &domain.User{
-		ID: c.Sender().ID,
-	})
+func (s *svc) createBroadcast(
+	ctx context.Context,
+	language domain.Language,
+	adminID int64,
+	text string,
+	filter domain.BroadcastFilter,
+) string {
+	broadcast, err := s.broadcastsSvc.Create(ctx, adminID, text, filter)
 	if err != nil {
-		err = fmt.Errorf("userSvc.Save: %w", err)
-		logger.Error().Msgf("handleStartCommand: %v", err.Error())
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+		err = fmt.Errorf("broadcastsSvc.Create: %w", err)
+		log.Ctx(ctx).Error().Msgf("createBroadcast: %v", err.Error())
+		return answers.Text(language, answers.BotError)
 	}
 
-	return s.SendMessageWithOpts(c.Sender().ID, answers.Start)
+	return answers.Textf(language, answers.AdminBroadcastCreated, broadcast.ID, broadcast.Progress.Total, broadcast.ID)
 }
 
-func (s *svc) handleHelpCommand(c tele.Context) error {
-	return s.SendMessageWithOpts(c.Sender().ID, answers.Help)
-}
+// adminBroadcastAction с пустым action показывает ход рассылки
+func (s *svc) adminBroadcastAction(
+	ctx context.Context,
+	language domain.Language,
+	action string,
+	broadcastID int64,
+) string {
+	var (
+		broadcast *domain.Broadcast
+		err       error
+	)
+	switch action {
+	case "pause":
+		broadcast, err = s.broadcastsSvc.Pause(ctx, broadcastID)
+	case "resume":
+		broadcast, err = s.broadcastsSvc.Resume(ctx, broadcastID)
+	case "cancel":
+		broadcast, err = s.broadcastsSvc.Cancel(ctx, broadcastID)
+	default:
+		broadcast, err = s.broadcastsSvc.Broadcast(ctx, broadcastID)
+	}
 
-func (s *svc) handleAuthCommand(c tele.Context) error {
-	logger := log.With().Fields(extractTelebotFields(c)).Logger()
-	ctx := logger.WithContext(context.Background())
+	switch {
+	case errors.Is(err, ierrors.ErrBroadcastNotFound):
+		return answers.Text(language, answers.AdminBroadcastNotFound)
+	case errors.Is(err, ierrors.ErrBroadcastStatusConflict):
+		return answers.Textf(
+			language,
+			answers.AdminBroadcastStatusConflict,
+			broadcast.ID,
+			adminBroadcastStatus(language, broadcast.Status),
+		)
+	case err != nil:
+		err = fmt.Errorf("broadcastsSvc.Broadcast: %w", err)
+		log.Ctx(ctx).Error().Msgf("adminBroadcastAction: %v", err.Error())
+		return answers.Text(language, answers.BotError)
+	}
 
-	if c.Message().Payload == "" {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.CredentialsNoEntered)
+	switch action {
+	case "pause":
+		return answers.Textf(language, answers.AdminBroadcastPaused, broadcast.ID, broadcast.ID)
+	case "resume":
+		return answers.Textf(language, answers.AdminBroadcastResumed, broadcast.ID)
+	case "cancel":
+		return answers.Textf(
+			language,
+			answers.AdminBroadcastCancelled,
+			broadcast.ID,
+			broadcast.Progress.Sent,
+			broadcast.Progress.Total,
+		)
 	}
 
-	userCredentials := strings.Split(c.Message().Payload, " ")
+	return adminBroadcast(language, broadcast)
+}
 
-	if len(userCredentials) != 2 {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.CredentialsFormIgnored)
+func parseBroadcastID(arg string) (int64, error) {
+	return strconv.ParseInt(strings.TrimPrefix(arg, "#"), 10, 64)
+}
+
+// adminBroadcastPreviewLength столько символов текста рассылки показывается в списке
+const adminBroadcastPreviewLength = 100
+
+func adminBroadcast(language domain.Language, broadcast *domain.Broadcast) string {
+	preview := []rune(broadcast.Text)
+	if len(preview) > adminBroadcastPreviewLength {
+		preview = append(preview[:adminBroadcastPreviewLength], '…')
 	}
 
-	username := userCredentials[0]
-	password := userCredentials[1]
+	return answers.Textf(
+		language,
+		answers.AdminBroadcast,
+		broadcast.ID,
+		adminBroadcastStatus(language, broadcast.Status),
+		broadcast.Filter.String(),
+		broadcast.CreatedAt.In(config.BARSLocation).Format(time.DateTime),
+		broadcast.Progress.Sent,
+		broadcast.Progress.Total,
+		broadcast.Progress.Failed,
+		broadcast.Progress.Pending,
+		string(preview),
+	)
+}
 
-	if !isValidUserData(username) {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.CredentialsIncorrectly)
+func adminBroadcastStatus(language domain.Language, status domain.BroadcastStatus) string {
+	switch status {
+	case domain.BroadcastStatusPaused:
+		return answers.Text(language, answers.AdminBroadcastStatusPaused)
+	case domain.BroadcastStatusCancelled:
+		return answers.Text(language, answers.AdminBroadcastStatusCancelled)
+	case domain.BroadcastStatusCompleted:
+		return answers.Text(language, answers.AdminBroadcastStatusCompleted)
+	default:
+		return answers.Text(language, answers.AdminBroadcastStatusActive)
 	}
+}
 
-	// TODO в будущем нужно ввести проверку на то, что нет пользователя с таким username
-	err := s.barsSvc.Authorization(ctx, c.Sender().ID, username, []byte(password))
-	switch {
-	case errors.Is(err, ierrors.ErrWrongGradesPage):
-		return s.SendMessageWithOpts(c.Sender().ID, answers.GradesPageWrong)
-	case errors.Is(err, bars.ErrAuthorizationFailed):
-		return s.SendMessageWithOpts(c.Sender().ID, answers.CredentialsWrong)
-	case errors.Is(err, ierrors.ErrAlreadyAuth):
-		return s.SendMessageWithOpts(c.Sender().ID, answers.ClientAlreadyAuthorized)
-	case err != nil:
-		err = fmt.Errorf("barsSvc.Authorization: %w", err)
-		logger.Error().Msgf("handleAuthCommand: %v", err.Error())
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+// cutArgs отделяет от text первые n слов. Остаток возвращается как есть, с переводами строк
+func cutArgs(text string, n int) ([]string, string) {
+	args := make([]string, 0, n)
+	rest := strings.TrimSpace(text)
+	for len(args) < n && rest != "" {
+		i := strings.IndexFunc(rest, unicode.IsSpace)
+		if i < 0 {
+			args = append(args, rest)
+			rest = ""
+			break
+		}
+
+		args = append(args, rest[:i])
+		rest = strings.TrimSpace(rest[i:])
 	}
 
-	return s.SendMessageWithOpts(c.Sender().ID, answers.SuccessfulAuthorization)
+	return args, rest
 }
 
-func (s *svc) handleLogoutCommand(c tele.Context) error {
-	logger := log.With().Fields(extractTelebotFields(c)).Logger()
-	ctx := logger.WithContext(context.Background())
+func (s *svc) handleAdminCountAuthorizedCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
 
-	err := s.barsSvc.Logout(ctx, c.Sender().ID)
+	counts, err := s.userSvc.Counts(context.Background())
 	if err != nil {
-		err = fmt.Errorf("barsSvc.Logout: %w", err)
-		logger.Error().Msgf("handleLogoutCommand: %v", err.Error())
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+		err = fmt.Errorf("userSvc.Counts: %w", err)
+		logger.Error().Msgf("handleAdminCountAuthorizedCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
 	}
 
-	return s.SendMessageWithOpts(c.Sender().ID, answers.SuccessfulLogout)
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(
+		language,
+		answers.AdminUsersCounts,
+		counts.Registered,
+		counts.Authorized,
+		counts.Suspended,
+		counts.Deleted,
+	))
 }
 
-func (s *svc) handleProgressTableCommand(c tele.Context) error {
-	logger := log.With().Fields(extractTelebotFields(c)).Logger()
-	ctx := logger.WithContext(context.Background())
+// handleAdminUserCommand /auser <telegram id | логин БАРС>
+func (s *svc) handleAdminUserCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
 
-	user, err := s.userSvc.User(ctx, c.Sender().ID)
-	if err != nil {
-		logger.Error().Msgf(
-			"handleProgressTableCommand: %v",
-			fmt.Errorf("progressTableSvc.User: %w", err).Error(),
-		)
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+	payload := strings.TrimSpace(c.Message().Payload)
+	if payload == "" {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminInvalidArgument))
 	}
-	if user == nil || user.BarsCredentials == nil {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.ClientNotAuthorized)
+
+	var statuses []*domain.UserStatus
+	if userID, err := strconv.ParseInt(payload, 10, 64); err == nil {
+		status, err := s.userSvc.UserStatus(context.Background(), userID)
+		if err != nil {
+			err = fmt.Errorf("userSvc.UserStatus: %w", err)
+			logger.Error().Msgf("handleAdminUserCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+		if status != nil {
+			statuses = append(statuses, status)
+		}
+	} else {
+		statuses, err = s.userSvc.UserStatusesByUsername(context.Background(), payload)
+		if err != nil {
+			err = fmt.Errorf("userSvc.UserStatusesByUsername: %w", err)
+			logger.Error().Msgf("handleAdminUserCommand: %v", err.Error())
+			return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+		}
+	}
+	if len(statuses) == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminUserNotFound))
 	}
 
-	progressTable := user.ProgressTable
-	if progressTable == nil || len(progressTable.Disciplines) == 0 {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.GradesPageUnavailable)
+	var b strings.Builder
+	for _, status := range statuses {
+		b.WriteString(adminUserStatus(language, status))
 	}
 
-	return s.SendMessageWithOpts(
-		c.Sender().ID,
-		generateDisciplineListMessage(progressTable.Disciplines),
-		tele.ModeMarkdown,
-		s.generateDisciplineListMarkup(progressTable),
-	)
+	return s.SendMessageWithOpts(c.Sender().ID, b.String())
 }
 
-func (s *svc) handleGithubCommand(c tele.Context) error {
-	return s.SendMessageWithOpts(c.Sender().ID, answers.Github, tele.ModeMarkdown)
-}
+// handleAdminCheckCommand /acheck <telegram id>
+func (s *svc) handleAdminCheckCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
 
-func (s *svc) handleText(c tele.Context) error {
-	return s.SendMessageWithOpts(c.Sender().ID, answers.Default)
-}
+	userID, err := strconv.ParseInt(strings.TrimSpace(c.Message().Payload), 10, 64)
+	if err != nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminInvalidArgument))
+	}
 
-func (s *svc) handleAdminEchoCommand(c tele.Context) error {
-	input := strings.SplitN(c.Text(), " ", 2)
-	if len(input) <= 1 {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.AdminInvalidArgument)
+	requested, err := s.userSvc.RequestCheck(context.Background(), userID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.RequestCheck: %w", err)
+		logger.Error().Msgf("handleAdminCheckCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if !requested {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.AdminCheckNotTracked, userID))
 	}
 
-	return s.SendMessageWithOpts(c.Sender().ID, input[1], tele.ModeMarkdown)
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.AdminCheckRequested, userID, userID))
 }
 
-func (s *svc) handleAdminSendMessageAllCommand(c tele.Context) error {
-	input := strings.SplitN(c.Text(), " ", 2)
-	if len(input) <= 1 {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.AdminInvalidArgument)
+// handleAdminLogoutCommand /alogout <telegram id>
+func (s *svc) handleAdminLogoutCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	userID, err := strconv.ParseInt(strings.TrimSpace(c.Message().Payload), 10, 64)
+	if err != nil {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminInvalidArgument))
 	}
 
-	logger := log.With().Int64("admin", c.Sender().ID).Logger()
+	status, err := s.userSvc.UserStatus(ctx, userID)
+	if err != nil {
+		err = fmt.Errorf("userSvc.UserStatus: %w", err)
+		logger.Error().Msgf("handleAdminLogoutCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if status == nil || status.State == domain.UserStateDeleted {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminUserNotFound))
+	}
 
-	users, err := s.userSvc.Users(context.Background())
+	err = s.barsSvc.Logout(ctx, userID)
 	if err != nil {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+		err = fmt.Errorf("barsSvc.Logout: %w", err)
+		logger.Error().Msgf("handleAdminLogoutCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
 	}
+	s.progressTableCache.Delete(userID)
 
-	errCounter := 0
-	for _, user := range users {
-		sendErr := s.SendMessageWithOpts(user.ID, input[1])
-		if sendErr != nil {
-			errCounter++
-			logger.Error().Int64("receiver", user.ID).Msg("failed to send message")
+	if status.State != domain.UserStateNotAuthorized {
+		userLanguage, err := s.userSettingsSvc.Language(ctx, userID)
+		if err != nil {
+			logger.Error().Int64("user", userID).Msgf("userSettingsSvc.Language: %v", err.Error())
+		}
+		err = s.SendMessageWithOpts(userID, answers.Text(userLanguage, answers.LogoutByAdmin))
+		if err != nil {
+			logger.Error().Int64("receiver", userID).Msgf("handleAdminLogoutCommand: %v", err.Error())
 		}
 	}
 
-	return s.SendMessageWithOpts(
-		c.Sender().ID,
-		fmt.Sprintf("Разослано сообщение (успешно: %d, ошибок: %d)\n%s",
-			len(users)-errCounter, errCounter, input[1]),
-		tele.ModeMarkdown,
-	)
+	return s.SendMessageWithOpts(c.Sender().ID, answers.Textf(language, answers.AdminLogoutDone, userID))
 }
 
-func (s *svc) handleAdminSendMessageAuthCommand(c tele.Context) error {
-	input := strings.SplitN(c.Text(), " ", 2)
-	if len(input) <= 1 {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.AdminInvalidArgument)
+// adminUserStatus время показывается в часовом поясе БАРС
+func adminUserStatus(language domain.Language, status *domain.UserStatus) string {
+	none := answers.Text(language, answers.AdminNone)
+	formatTime := func(t *time.Time) string {
+		if t == nil {
+			return none
+		}
+		return t.In(config.BARSLocation).Format(time.DateTime)
 	}
 
-	logger := log.With().Int64("admin", c.Sender().ID).Logger()
-
-	users, err := s.userSvc.Users(context.Background())
-	if err != nil {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+	var state string
+	switch status.State {
+	case domain.UserStateAuthorized:
+		state = answers.Text(language, answers.AdminUserStateAuthorized)
+	case domain.UserStateSuspended:
+		state = answers.Textf(language, answers.AdminUserStateSuspended, formatTime(status.SuspendedAt))
+	case domain.UserStateDeleted:
+		state = answers.Text(language, answers.AdminUserStateDeleted)
+	default:
+		state = answers.Text(language, answers.AdminUserStateNotAuthorized)
 	}
 
-	errCounter := 0
-	for _, user := range users {
-		sendErr := s.SendMessageWithOpts(user.ID, input[1])
-		if sendErr != nil {
-			errCounter++
-			logger.
-				Error().
-				Int64("receiver", user.ID).
-				Msg("failed to send message")
-		}
+	username := status.Username
+	if username == "" {
+		username = none
+	}
+	lastError := status.LastError
+	if lastError == "" {
+		lastError = none
 	}
 
-	return s.SendMessageWithOpts(
-		c.Sender().ID,
-		fmt.Sprintf("Разослано сообщение (успешно: %d, ошибок: %d)\n%s",
-			len(users)-errCounter, errCounter, input[1]),
-		tele.ModeMarkdown,
+	return answers.Textf(
+		language,
+		answers.AdminUserStatus,
+		status.UserID,
+		state,
+		username,
+		formatTime(&status.CreatedAt),
+		formatTime(status.CheckedAt),
+		formatTime(status.CheckRequestedAt),
+		status.FailuresCount,
+		lastError,
+		status.OutboxBacklog,
 	)
 }
 
-func (s *svc) handleAdminSendMessageCommand(c tele.Context) error {
-	input := strings.SplitN(c.Text(), " ", 3)
-	if len(input) <= 2 {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.AdminInvalidArgument)
-	}
+const adminAuthorizationFailuresLimit = 30
 
-	userID, err := strconv.Atoi(input[1])
+func (s *svc) handleAdminAuthorizationFailuresCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
+
+	failures, err := s.authorizationFailuresSvc.AuthorizationFailures(context.Background())
 	if err != nil {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.AdminInvalidArgument)
+		err = fmt.Errorf("authorizationFailuresSvc.AuthorizationFailures: %w", err)
+		logger.Error().Msgf("handleAdminAuthorizationFailuresCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if len(failures) == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminNoAuthorizationFailures))
 	}
 
-	err = s.SendMessageWithOpts(int64(userID), input[2], tele.ModeMarkdown)
-	if err != nil {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+	var b strings.Builder
+	b.WriteString(answers.Textf(language, answers.AdminAuthorizationFailuresHeader, len(failures)))
+	for i, failure := range failures {
+		if i == adminAuthorizationFailuresLimit {
+			b.WriteString(answers.Textf(
+				language,
+				answers.AdminAuthorizationFailuresMore,
+				len(failures)-adminAuthorizationFailuresLimit,
+			))
+			break
+		}
+		b.WriteString(answers.Textf(
+			language,
+			answers.AdminAuthorizationFailure,
+			failure.UserID,
+			failure.Count,
+			failure.FirstFailureAt.Format(time.DateTime),
+			failure.LastFailureAt.Format(time.DateTime),
+			failure.LastError,
+		))
 	}
 
-	return s.SendMessageWithOpts(c.Sender().ID,
-		fmt.Sprintf("Пользователю %d успешно отправлено сообщение:\n%s",
-			userID, input[2]), tele.ModeMarkdown)
+	return s.SendMessageWithOpts(c.Sender().ID, b.String())
 }
 
-// TODO
-/*
-func (s *svc) handleAdminCountAuthorizedCommand(c tele.Context) error {
-	count, err := s.barsCredentialsRepo.Count(context.Background())
+// handleAdminDistributionsCommand /adist [часть названия дисциплины]
+func (s *svc) handleAdminDistributionsCommand(c tele.Context) error {
+	language := s.language(c)
+	logger := log.With().Int64("admin", c.Sender().ID).Logger()
+
+	distributions, err := s.gradeDistributionsSvc.Search(context.Background(), strings.TrimSpace(c.Message().Payload))
 	if err != nil {
-		return s.SendMessageWithOpts(c.Sender().ID, answers.BotError)
+		err = fmt.Errorf("gradeDistributionsSvc.Search: %w", err)
+		logger.Error().Msgf("handleAdminDistributionsCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+	if len(distributions) == 0 {
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.AdminNoDistributions))
 	}
 
-	return s.SendMessageWithOpts(
-		c.Sender().ID,
-		fmt.Sprintf("Количество авторизованных: %d", count),
-	)
+	var b strings.Builder
+	b.WriteString(answers.Textf(language, answers.AdminDistributionsHeader, len(distributions)))
+	for _, distribution := range distributions {
+		b.WriteString(answers.Textf(
+			language,
+			answers.AdminDistribution,
+			distribution.Semester,
+			distribution.Discipline,
+			distribution.ControlEvent,
+			distribution.Q1,
+			distribution.Median,
+			distribution.Q3,
+			distribution.Contributors,
+		))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, b.String())
 }
-*/
 
 func (s *svc) handleFixGradesCommand(c tele.Context) error {
-	return s.SendMessageWithOpts(c.Sender().ID, answers.FixGrades, tele.ModeMarkdown)
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	language := s.language(c)
+
+	message, err := s.rendererSvc.FixGrades(domain.MessageFormatMarkdownV2, language)
+	if err != nil {
+		err = fmt.Errorf("rendererSvc.FixGrades: %w", err)
+		logger.Error().Msgf("handleFixGradesCommand: %v", err.Error())
+		return s.SendMessageWithOpts(c.Sender().ID, answers.Text(language, answers.BotError))
+	}
+
+	return s.SendMessageWithOpts(c.Sender().ID, message.Text, parseMode(message.Format))
 }
 
 func isValidUserData(username string) bool {
@@ -325,43 +2469,190 @@ func extractTelebotFields(c tele.Context) map[string]interface{} {
 	}
 }
 
-func generateDisciplineListMessage(disciplines []domain.Discipline) string {
-	var b strings.Builder
+func (s *svc) generateRecordBookMarkup(page int, pagesCount int) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
 
-	for i, discipline := range disciplines {
-		b.WriteString(fmt.Sprintf("*%d:* %s\n\n", i+1, discipline.Name))
+	row := make([]tele.Btn, 0, 2)
+	if page > 0 {
+		row = append(row, markup.Data("←", fmt.Sprintf("%s%d", callbackRecordBook, page-1)))
+	}
+	if page < pagesCount-1 {
+		row = append(row, markup.Data("→", fmt.Sprintf("%s%d", callbackRecordBook, page+1)))
+	}
+
+	// с одним семестром листать некуда, и сообщение отправляется без клавиатуры
+	if len(row) == 0 {
+		return nil
 	}
+	markup.Inline(row)
 
-	b.WriteString("Для просмотра оценок по определённому предмету, воспользуйтесь кнопочным меню.")
+	return markup
+}
 
-	return b.String()
+// scheduleRange возвращает границы периода расписания в часовом поясе БАРС
+func scheduleRange(period domain.SchedulePeriod, now time.Time) (time.Time, time.Time) {
+	now = now.In(config.BARSLocation)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, config.BARSLocation)
+
+	switch period {
+	case domain.SchedulePeriodTomorrow:
+		return today.AddDate(0, 0, 1), today.AddDate(0, 0, 2)
+	case domain.SchedulePeriodWeek:
+		// неделя начинается с понедельника
+		monday := today.AddDate(0, 0, -((int(today.Weekday()) + 6) % 7))
+		return monday, monday.AddDate(0, 0, 7)
+	default:
+		return today, today.AddDate(0, 0, 1)
+	}
 }
 
-func generateDisciplineInfoMessage(
-	discipline domain.Discipline,
-	isHideControlEventNames bool,
-) string {
-	var b strings.Builder
+func (s *svc) generateExportMarkup(language domain.Language) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+	formats := []export.Format{export.FormatCSV, export.FormatJSON, export.FormatXLSX}
+
+	withoutHistory := make([]tele.Btn, 0, len(formats))
+	withHistory := make([]tele.Btn, 0, len(formats))
+	for _, format := range formats {
+		name := strings.ToUpper(string(format))
+		withoutHistory = append(withoutHistory, markup.Data(name, callbackExport+string(format)))
+		withHistory = append(withHistory, markup.Data(
+			answers.Textf(language, answers.ExportWithHistoryButton, name),
+			callbackExport+string(format)+callbackExportHistorySuffix,
+		))
+	}
+	markup.Inline(markup.Row(withoutHistory...), markup.Row(withHistory...))
+
+	return markup
+}
+
+func (s *svc) generateLanguageMarkup() *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	row := make([]tele.Btn, 0, len(domain.Languages))
+	for _, language := range domain.Languages {
+		row = append(row, markup.Data(answers.LanguageNames[language], callbackLanguage+string(language)))
+	}
+
+	markup.Inline(row)
+
+	return markup
+}
+
+func (s *svc) generateNotifyMarkup(language domain.Language) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	markup.Inline([]tele.Btn{
+		markup.Data(
+			answers.Text(language, answers.NotifyTelegramButton),
+			callbackNotify+string(domain.NotificationChannelTelegram),
+		),
+		markup.Data(
+			answers.Text(language, answers.NotifyEmailButton),
+			callbackNotify+string(domain.NotificationChannelEmail),
+		),
+		markup.Data(answers.Text(language, answers.NotifyBothButton), callbackNotify+callbackNotifyBothOption),
+	})
+
+	return markup
+}
+
+func (s *svc) generateRemindMarkup(language domain.Language, settings *domain.UserSettings) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
 
-	b.WriteString(fmt.Sprintf("*Название дисциплины:*\n%s\n\n", discipline.Name))
+	summaryButton := markup.Data(
+		answers.Text(language, answers.ReminderSummaryEnableButton),
+		callbackRemind+callbackRemindSummaryOption+"1",
+	)
+	if settings.WeeklySummary {
+		summaryButton = markup.Data(
+			answers.Text(language, answers.ReminderSummaryDisableButton),
+			callbackRemind+callbackRemindSummaryOption+"0",
+		)
+	}
 
-	for i, ce := range discipline.ControlEvents {
-		name := ce.Name
-		if isHideControlEventNames &&
-			(!(strings.HasPrefix(ce.Name, "Балл текущего контроля") ||
-				strings.HasPrefix(ce.Name, "Итоговая оценка:") ||
-				strings.HasPrefix(ce.Name, "Промежуточная аттестация"))) {
-			name = fmt.Sprintf("КМ-%d", i+1)
+	daysButtons := make([]tele.Btn, 0, len(reminderDaysOptions)+1)
+	for _, days := range reminderDaysOptions {
+		if days == settings.ReminderDaysBefore {
+			continue
 		}
-		b.WriteString(fmt.Sprintf("%s\n*Оценка:* %s\n\n", name, ce.Grade))
+		daysButtons = append(daysButtons, markup.Data(
+			answers.Textf(language, answers.ReminderDaysButton, days),
+			callbackRemind+callbackRemindDaysOption+strconv.Itoa(days),
+		))
+	}
+	if settings.ReminderDaysBefore > 0 {
+		daysButtons = append(daysButtons, markup.Data(
+			answers.Text(language, answers.ReminderDaysOffButton),
+			callbackRemind+callbackRemindDaysOption+"0",
+		))
 	}
 
-	return b.String()
+	markup.Inline(markup.Row(summaryButton), markup.Row(daysButtons...))
+
+	return markup
+}
+
+func (s *svc) generateShareMarkup(language domain.Language, settings *domain.UserSettings) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	button := markup.Data(answers.Text(language, answers.ShareEnableButton), callbackShare+"1")
+	if settings.ShareGrades {
+		button = markup.Data(answers.Text(language, answers.ShareDisableButton), callbackShare+"0")
+	}
+	markup.Inline(markup.Row(button))
+
+	return markup
+}
+
+func (s *svc) generateWebhooksMarkup(language domain.Language, webhooks []*domain.Webhook) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	rows := make([]tele.Row, 0, len(webhooks))
+	for _, webhook := range webhooks {
+		id := strconv.FormatInt(webhook.ID, 10)
+		rows = append(rows, markup.Row(
+			markup.Data(
+				answers.Textf(language, answers.WebhookTestButton, webhook.ID),
+				callbackWebhooks+callbackWebhooksTestOption+id,
+			),
+			markup.Data(
+				answers.Textf(language, answers.WebhookRemoveButton, webhook.ID),
+				callbackWebhooks+callbackWebhooksRemoveOption+id,
+			),
+		))
+	}
+	markup.Inline(rows...)
+
+	return markup
+}
+
+func (s *svc) generateScheduleMarkup(language domain.Language) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	markup.Inline([]tele.Btn{
+		markup.Data(answers.Text(language, answers.ScheduleTodayButton), callbackSchedule+callbackScheduleTodayOption),
+		markup.Data(answers.Text(language, answers.ScheduleTomorrowButton), callbackSchedule+callbackScheduleTomorrowOption),
+		markup.Data(answers.Text(language, answers.ScheduleWeekButton), callbackSchedule+callbackScheduleWeekOption),
+	})
+
+	return markup
 }
 
 const buttonsCountInRowDisciplineList = 5
 
-func (s *svc) generateDisciplineListMarkup(progressTable *domain.ProgressTable) *tele.ReplyMarkup {
+func semesterCallbackSuffix(semesterID string) string {
+	if semesterID == "" {
+		return ""
+	}
+
+	return callbackProgressTableSemesterSeparator + semesterID
+}
+
+func (s *svc) generateDisciplineListMarkup(
+	language domain.Language,
+	progressTable *domain.ProgressTable,
+	semesterID string,
+) *tele.ReplyMarkup {
 	markup := s.bot.NewMarkup()
 
 	rowsCount, buttonsCountInLastRow := 0, 0
@@ -390,7 +2681,7 @@ func (s *svc) generateDisciplineListMarkup(progressTable *domain.ProgressTable)
 			disciplineNumber := i*buttonsCountInRowDisciplineList + j + 1
 			button := markup.Data(
 				strconv.Itoa(disciplineNumber),
-				fmt.Sprintf("pt%d", disciplineNumber),
+				fmt.Sprintf("pt%d%s", disciplineNumber, semesterCallbackSuffix(semesterID)),
 			)
 			row = append(row, button)
 		}
@@ -402,11 +2693,49 @@ func (s *svc) generateDisciplineListMarkup(progressTable *domain.ProgressTable)
 		disciplineNumber := (rowsCount-1)*buttonsCountInRowDisciplineList + j + 1
 		button := markup.Data(
 			strconv.Itoa(disciplineNumber),
-			fmt.Sprintf("pt%d", disciplineNumber),
+			fmt.Sprintf("pt%d%s", disciplineNumber, semesterCallbackSuffix(semesterID)),
 		)
 		row = append(row, button)
 	}
 	rows = append(rows, row)
+	rows = append(rows, tele.Row{
+		markup.Data(
+			answers.Text(language, answers.ProgressTableImageButton),
+			callbackProgressTable+callbackProgressTableImageOption+semesterCallbackSuffix(semesterID),
+		),
+		markup.Data(
+			answers.Text(language, answers.ProgressTablePDFButton),
+			callbackProgressTable+callbackProgressTablePDFOption+semesterCallbackSuffix(semesterID),
+		),
+	})
+	rows = append(rows, tele.Row{markup.Data(
+		answers.Text(language, answers.SemestersButton),
+		fmt.Sprintf("%s%s", callbackProgressTable, callbackProgressTableSemestersOption),
+	)})
+
+	markup.Inline(rows...)
+
+	return markup
+}
+
+func (s *svc) generateSemestersMarkup(semesters []domain.Semester, currentSemesterID string) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	rows := make([]tele.Row, 0, len(semesters)+1)
+	for _, semester := range semesters {
+		text := semester.Name
+		if semester.ID == currentSemesterID {
+			text = fmt.Sprintf("• %s", semester.Name)
+		}
+		rows = append(rows, tele.Row{markup.Data(
+			text,
+			fmt.Sprintf("%s%s%s", callbackProgressTable, callbackProgressTableSemesterOption, semester.ID),
+		)})
+	}
+	rows = append(rows, tele.Row{markup.Data(
+		"←",
+		fmt.Sprintf("%s%s", callbackProgressTable, callbackProgressTableBackOption),
+	)})
 
 	markup.Inline(rows...)
 
@@ -414,14 +2743,21 @@ func (s *svc) generateDisciplineListMarkup(progressTable *domain.ProgressTable)
 }
 
 func (s *svc) generateDisciplineMarkup(
+	language domain.Language,
 	disciplineNumber int,
 	isHideControlEventNames bool,
+	semesterID string,
 ) *tele.ReplyMarkup {
 	markup := s.bot.NewMarkup()
 
 	backButton := markup.Data(
 		"←",
-		fmt.Sprintf("%s%s", callbackProgressTable, callbackProgressTableBackOption),
+		fmt.Sprintf(
+			"%s%s%s",
+			callbackProgressTable,
+			callbackProgressTableBackOption,
+			semesterCallbackSuffix(semesterID),
+		),
 	)
 	showOrHideButton := tele.Btn{}
 	if isHideControlEventNames {
@@ -429,20 +2765,69 @@ func (s *svc) generateDisciplineMarkup(
 		showOrHideButton = markup.Data(
 			"↓",
 			fmt.Sprintf(
-				"%s%s%d",
+				"%s%s%d%s",
 				callbackProgressTable,
 				callbackProgressTableDisciplineDetailsOption,
 				disciplineNumber,
+				semesterCallbackSuffix(semesterID),
 			),
 		)
 	} else {
 		showOrHideButton = markup.Data(
 			"↑",
-			fmt.Sprintf("%s%d", callbackProgressTable, disciplineNumber),
+			fmt.Sprintf("%s%d%s", callbackProgressTable, disciplineNumber, semesterCallbackSuffix(semesterID)),
 		)
 	}
 
-	markup.Inline([]tele.Btn{backButton, showOrHideButton})
+	forecastButton := markup.Data(
+		answers.Text(language, answers.ForecastButton),
+		fmt.Sprintf(
+			"%s%s%d%s",
+			callbackProgressTable,
+			callbackProgressTableForecastOption,
+			disciplineNumber,
+			semesterCallbackSuffix(semesterID),
+		),
+	)
+
+	chartButton := markup.Data(
+		answers.Text(language, answers.TrendChartButton),
+		fmt.Sprintf(
+			"%s%s%d%s",
+			callbackProgressTable,
+			callbackProgressTableChartOption,
+			disciplineNumber,
+			semesterCallbackSuffix(semesterID),
+		),
+	)
+	trendsChartButton := markup.Data(
+		answers.Text(language, answers.TrendsChartButton),
+		fmt.Sprintf(
+			"%s%s%d%s",
+			callbackProgressTable,
+			callbackProgressTableChartOption,
+			0,
+			semesterCallbackSuffix(semesterID),
+		),
+	)
+
+	markup.Inline(
+		[]tele.Btn{backButton, showOrHideButton, forecastButton},
+		[]tele.Btn{chartButton, trendsChartButton},
+	)
+
+	return markup
+}
+
+func (s *svc) generateDisciplineForecastMarkup(disciplineNumber int, semesterID string) *tele.ReplyMarkup {
+	markup := s.bot.NewMarkup()
+
+	backButton := markup.Data(
+		"←",
+		fmt.Sprintf("%s%d%s", callbackProgressTable, disciplineNumber, semesterCallbackSuffix(semesterID)),
+	)
+
+	markup.Inline([]tele.Btn{backButton})
 
 	return markup
 }
@@ -0,0 +1,161 @@
+package telegram
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service/renderer"
+	tele "gopkg.in/telebot.v3"
+)
+
+type (
+	userService = service.User
+	barsService = service.Bars
+)
+
+type memoryUsers struct {
+	userService
+	user                   *domain.User
+	archivedProgressTables map[string]*domain.ProgressTable
+}
+
+func (m *memoryUsers) User(_ context.Context, _ int64) (*domain.User, error) {
+	return m.user, nil
+}
+
+func (m *memoryUsers) ArchiveProgressTable(
+	_ context.Context,
+	_ int64,
+	progressTable *domain.ProgressTable,
+) error {
+	m.archivedProgressTables[progressTable.Semester.ID] = progressTable
+	return nil
+}
+
+func (m *memoryUsers) ArchivedProgressTables(_ context.Context, _ int64) ([]*domain.ProgressTable, error) {
+	progressTables := make([]*domain.ProgressTable, 0, len(m.archivedProgressTables))
+	for _, progressTable := range m.archivedProgressTables {
+		progressTables = append(progressTables, progressTable)
+	}
+
+	return progressTables, nil
+}
+
+type semesterBars struct {
+	barsService
+	progressTables map[string]*domain.ProgressTable
+}
+
+func (b *semesterBars) SemesterProgressTable(
+	_ context.Context,
+	_ int64,
+	semesterID string,
+) (*domain.ProgressTable, error) {
+	return b.progressTables[semesterID], nil
+}
+
+// telegramAPI запоминает тексты отредактированных сообщений
+type telegramAPI struct {
+	mu    sync.Mutex
+	texts []string
+}
+
+func (a *telegramAPI) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var params map[string]interface{}
+	_ = json.NewDecoder(r.Body).Decode(&params)
+
+	a.mu.Lock()
+	if text, ok := params["text"].(string); ok {
+		a.texts = append(a.texts, text)
+	}
+	a.mu.Unlock()
+
+	_, _ = w.Write([]byte(`{"ok":true,"result":{"message_id":1,"chat":{"id":1},"date":0}}`))
+}
+
+func (a *telegramAPI) lastText() string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.texts) == 0 {
+		return ""
+	}
+
+	return a.texts[len(a.texts)-1]
+}
+
+func TestPastSemesterDisciplineCallback(t *testing.T) {
+	api := &telegramAPI{}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	bot, err := tele.NewBot(tele.Settings{Token: "token", URL: server.URL, Offline: true})
+	if err != nil {
+		t.Fatalf("tele.NewBot: %v", err)
+	}
+	rendererSvc, err := renderer.NewService(config.Render{})
+	if err != nil {
+		t.Fatalf("renderer.NewService: %v", err)
+	}
+
+	currentProgressTable := &domain.ProgressTable{
+		Semester:    domain.Semester{ID: "2", Name: "Весна 2024"},
+		Disciplines: []domain.Discipline{{Name: "Физика"}},
+	}
+	pastProgressTable := &domain.ProgressTable{
+		Semester: domain.Semester{ID: "1", Name: "Осень 2023"},
+		Disciplines: []domain.Discipline{{
+			Name: "Химия",
+			ControlEvents: []domain.ControlEvent{{
+				Name:  "Контрольная работа",
+				Grade: domain.ParseGrade("4"),
+			}},
+		}},
+	}
+	users := &memoryUsers{
+		user: &domain.User{
+			ID:              1,
+			BarsCredentials: &domain.BarsCredentials{Username: "student"},
+			ProgressTable:   currentProgressTable,
+		},
+		archivedProgressTables: make(map[string]*domain.ProgressTable),
+	}
+	s := &svc{
+		userSvc:     users,
+		barsSvc:     &semesterBars{progressTables: map[string]*domain.ProgressTable{"1": pastProgressTable}},
+		rendererSvc: rendererSvc,
+		bot:         bot,
+	}
+
+	callback := func(data string) tele.Context {
+		return bot.NewContext(tele.Update{Callback: &tele.Callback{
+			Data:    "\f" + data,
+			Sender:  &tele.User{ID: 1, LanguageCode: "ru"},
+			Message: &tele.Message{ID: 1, Chat: &tele.Chat{ID: 1}},
+		}})
+	}
+
+	err = s.handleProgressTableCallback(callback(callbackProgressTable + callbackProgressTableSemesterOption + "1"))
+	if err != nil {
+		t.Fatalf("semester callback: %v", err)
+	}
+	if _, ok := users.archivedProgressTables["1"]; !ok {
+		t.Fatalf("past semester progress table was not archived")
+	}
+
+	err = s.handleProgressTableCallback(callback(callbackProgressTable + "1" + semesterCallbackSuffix("1")))
+	if err != nil {
+		t.Fatalf("discipline callback: %v", err)
+	}
+	if text := api.lastText(); !strings.Contains(text, "Химия") {
+		t.Errorf("discipline callback edited message to %q, want past semester discipline", text)
+	}
+}
@@ -4,25 +4,62 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"html"
+	"net/http"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
 	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/markdown"
+	"github.com/jellydator/ttlcache/v3"
 	"github.com/rs/zerolog/log"
 	tele "gopkg.in/telebot.v3"
 	"gopkg.in/telebot.v3/middleware"
 )
 
+const contextKeyLanguage = "language"
+
+var htmlTagRegexp = regexp.MustCompile(`<[^>]*>`)
+
 type svc struct {
-	userSvc service.User
-	barsSvc service.Bars
-	bot     *tele.Bot
-	cfg     config.Telegram
+	userSvc                  service.User
+	barsSvc                  service.Bars
+	authorizationFailuresSvc service.AuthorizationFailures
+	scheduleSvc              service.Schedule
+	userSettingsSvc          service.UserSettings
+	rendererSvc              service.Renderer
+	webhooksSvc              service.Webhooks
+	accessTokensSvc          service.AccessTokens
+	calendarSvc              service.Calendar
+	gradesFeedSvc            service.GradesFeed
+	gradeDistributionsSvc    service.GradeDistributions
+	chatsSvc                 service.Chats
+	broadcastsSvc            service.Broadcasts
+	progressTableCache       *ttlcache.Cache[int64, *domain.ProgressTable]
+	languageCache            *ttlcache.Cache[int64, domain.Language]
+	bot                      *tele.Bot
+	cfg                      config.Telegram
 }
 
 func NewService(
 	userSvc service.User,
 	barsSvc service.Bars,
+	authorizationFailuresSvc service.AuthorizationFailures,
+	scheduleSvc service.Schedule,
+	userSettingsSvc service.UserSettings,
+	rendererSvc service.Renderer,
+	webhooksSvc service.Webhooks,
+	accessTokensSvc service.AccessTokens,
+	calendarSvc service.Calendar,
+	gradesFeedSvc service.GradesFeed,
+	gradeDistributionsSvc service.GradeDistributions,
+	chatsSvc service.Chats,
+	broadcastsSvc service.Broadcasts,
 	cfg config.Telegram,
 ) (*svc, error) {
 	bot, err := createBot(cfg)
@@ -30,11 +67,38 @@ func NewService(
 		return nil, fmt.Errorf("createBot: %w", err)
 	}
 
+	// пока пользователь набирает название дисциплины, телеграм присылает inline-запрос на каждую букву,
+	// и каждый раз читать таблицу успеваемости из БД незачем
+	progressTableCache := ttlcache.New[int64, *domain.ProgressTable](
+		ttlcache.WithTTL[int64, *domain.ProgressTable](cfg.InlineCacheTTL),
+		ttlcache.WithDisableTouchOnHit[int64, *domain.ProgressTable](),
+	)
+
+	// язык нужен на каждое обновление, включая inline-запросы на каждую букву,
+	// а меняется он только через бота, поэтому при смене язык в кэше обновляется сразу
+	languageCache := ttlcache.New[int64, domain.Language](
+		ttlcache.WithTTL[int64, domain.Language](cfg.LanguageCacheTTL),
+		ttlcache.WithDisableTouchOnHit[int64, domain.Language](),
+	)
+
 	s := &svc{
-		userSvc: userSvc,
-		barsSvc: barsSvc,
-		bot:     bot,
-		cfg:     cfg,
+		userSvc:                  userSvc,
+		barsSvc:                  barsSvc,
+		authorizationFailuresSvc: authorizationFailuresSvc,
+		scheduleSvc:              scheduleSvc,
+		userSettingsSvc:          userSettingsSvc,
+		rendererSvc:              rendererSvc,
+		webhooksSvc:              webhooksSvc,
+		accessTokensSvc:          accessTokensSvc,
+		calendarSvc:              calendarSvc,
+		gradesFeedSvc:            gradesFeedSvc,
+		gradeDistributionsSvc:    gradeDistributionsSvc,
+		chatsSvc:                 chatsSvc,
+		broadcastsSvc:            broadcastsSvc,
+		progressTableCache:       progressTableCache,
+		languageCache:            languageCache,
+		bot:                      bot,
+		cfg:                      cfg,
 	}
 
 	s.setBotSettings()
@@ -63,8 +127,18 @@ func createBot(cfg config.Telegram) (*tele.Bot, error) {
 
 func (s *svc) setBotSettings() {
 	// TODO в общем-то нужен ratelimiter на все эти ручки
+	s.bot.Use(s.chatTypeMiddleware, s.languageMiddleware)
+
 	s.bot.Handle(tele.OnCallback, s.handleOnCallback)
 
+	s.bot.Handle(tele.OnQuery, s.handleInlineQuery)
+
+	s.bot.Handle(tele.OnMyChatMember, s.handleMyChatMember)
+
+	s.bot.Handle(tele.OnMigration, s.handleMigration)
+
+	s.bot.Handle(tele.OnUserLeft, s.handleUserLeft)
+
 	s.bot.Handle("/start", s.handleStartCommand)
 
 	s.bot.Handle("/help", s.handleHelpCommand)
@@ -77,6 +151,38 @@ func (s *svc) setBotSettings() {
 
 	s.bot.Handle("/pt", s.handleProgressTableCommand)
 
+	s.bot.Handle("/stats", s.handleStatisticsCommand)
+
+	s.bot.Handle("/record", s.handleRecordBookCommand)
+
+	s.bot.Handle("/schedule", s.handleScheduleCommand)
+
+	s.bot.Handle("/export", s.handleExportCommand)
+
+	s.bot.Handle("/lang", s.handleLanguageCommand)
+
+	s.bot.Handle("/email", s.handleEmailCommand)
+
+	s.bot.Handle("/verify", s.handleVerifyCommand)
+
+	s.bot.Handle("/notify", s.handleNotifyCommand)
+
+	s.bot.Handle("/remind", s.handleRemindCommand)
+
+	s.bot.Handle("/share", s.handleShareCommand)
+
+	s.bot.Handle("/webhooks", s.handleWebhooksCommand)
+
+	s.bot.Handle("/token", s.handleTokenCommand)
+
+	s.bot.Handle("/calendar", s.handleCalendarCommand)
+
+	s.bot.Handle("/feed", s.handleFeedCommand)
+
+	s.bot.Handle("/join", s.handleJoinCommand)
+
+	s.bot.Handle("/leave", s.handleLeaveCommand)
+
 	s.bot.Handle("/gh", s.handleGithubCommand)
 
 	s.bot.Handle(tele.OnText, s.handleText)
@@ -90,22 +196,248 @@ func (s *svc) setBotSettings() {
 
 	adminGroup.Handle("/aecho", s.handleAdminEchoCommand)
 
-	// TODO ждут доработки users service & repo
-	//adminGroup.Handle("/asmall", s.handleAdminSendMessageAllCommand)
+	adminGroup.Handle("/asm", s.handleAdminSendMessageCommand)
 
-	//adminGroup.Handle("/asmauth", s.handleAdminSendMessageAuthCommand)
+	adminGroup.Handle("/abroadcast", s.handleAdminBroadcastCommand)
 
-	adminGroup.Handle("/asm", s.handleAdminSendMessageCommand)
+	adminGroup.Handle("/acauth", s.handleAdminCountAuthorizedCommand)
+
+	adminGroup.Handle("/auser", s.handleAdminUserCommand)
 
-	//adminGroup.Handle("/acauth", s.handleAdminCountAuthorizedCommand)
+	adminGroup.Handle("/acheck", s.handleAdminCheckCommand)
+
+	adminGroup.Handle("/alogout", s.handleAdminLogoutCommand)
+
+	adminGroup.Handle("/aaf", s.handleAdminAuthorizationFailuresCommand)
+
+	adminGroup.Handle("/adist", s.handleAdminDistributionsCommand)
+}
+
+func (s *svc) SendMessage(id int64, message *domain.Message) error {
+	return s.SendMessageWithOpts(id, message.Text, parseMode(message.Format))
+}
+
+// parseMode режим разметки телеграма для формата сообщения, HTML телеграм поддерживает лишь частично
+func parseMode(format domain.MessageFormat) tele.ParseMode {
+	switch format {
+	case domain.MessageFormatMarkdownV2:
+		return tele.ModeMarkdownV2
+	case domain.MessageFormatHTML:
+		return tele.ModeHTML
+	default:
+		return tele.ModeDefault
+	}
 }
 
 func (s *svc) SendMessageWithOpts(id int64, message string, opts ...interface{}) error {
+	return sendError(s.middlewareError(id, s.send(id, message, opts...)))
+}
+
+// sendError отделяет ошибки, после которых отправку стоит повторить, от отказов телеграма.
+// Ошибки сети и сбои на стороне телеграма возвращаются как есть
+func sendError(err error) error {
+	var (
+		floodErr tele.FloodError
+		teleErr  *tele.Error
+	)
+	switch {
+	case err == nil:
+		return nil
+	case errors.As(err, &floodErr):
+		return &ierrors.RetryAfterError{
+			RetryAfter: time.Duration(floodErr.RetryAfter) * time.Second,
+			Err:        err,
+		}
+	case errors.As(err, &teleErr) && teleErr.Code >= http.StatusBadRequest && teleErr.Code < http.StatusInternalServerError:
+		return fmt.Errorf("%w: %w", ierrors.ErrMessageRejected, err)
+	default:
+		return err
+	}
+}
+
+func (s *svc) SendChatMessage(chatID int64, message *domain.Message) error {
+	return s.sendChatMessageWithOpts(chatID, message.Text, parseMode(message.Format))
+}
+
+// sendChatMessageWithOpts в отличие от SendMessageWithOpts не удаляет пользователя при ошибке: адресат - групповой чат.
+// Если бота убрали из чата, а обновление об этом потерялось, чат удаляется здесь
+func (s *svc) sendChatMessageWithOpts(chatID int64, message string, opts ...interface{}) error {
+	err := s.send(chatID, message, opts...)
+	if errors.Is(err, tele.ErrKickedFromGroup) ||
+		errors.Is(err, tele.ErrKickedFromSuperGroup) ||
+		errors.Is(err, tele.ErrChatNotFound) {
+		ctx, cancel := context.WithTimeout(context.Background(), 15*time.Second)
+		defer cancel()
+		log.Info().
+			Int64("chat", chatID).
+			Msgf("removing chat due to an error: %v", err)
+
+		removeErr := s.chatsSvc.Remove(ctx, chatID)
+		if removeErr != nil {
+			log.Error().
+				Int64("chat", chatID).
+				Msgf("removing chat due to an error %v failed: %v", err, removeErr)
+		}
+	}
+
+	return err
+}
+
+func (s *svc) send(id int64, message string, opts ...interface{}) error {
 	chat := tele.ChatID(id)
 
 	_, err := s.bot.Send(chat, message, opts...)
+	if isParseEntitiesError(err) {
+		log.Warn().Int64("chat", id).Msgf("sending message without markup: %v", err)
+		plainMessage, plainOpts := withoutMarkup(message, opts)
+		_, err = s.bot.Send(chat, plainMessage, plainOpts...)
+	}
 
-	return s.middlewareError(id, err)
+	return err
+}
+
+// isParseEntitiesError телеграм не смог разобрать разметку сообщения
+func isParseEntitiesError(err error) bool {
+	var tgErr *tele.Error
+	return errors.As(err, &tgErr) && strings.Contains(tgErr.Description, "can't parse entities")
+}
+
+// withoutMarkup текст сообщения без разметки и опции без режима разбора. У устаревшего Markdown те же символы
+// разметки и экранирование обратной косой чертой, что и у MarkdownV2, поэтому они обрабатываются одинаково
+func withoutMarkup(message string, opts []interface{}) (string, []interface{}) {
+	plainOpts := make([]interface{}, 0, len(opts))
+	for _, opt := range opts {
+		mode, ok := opt.(tele.ParseMode)
+		if !ok {
+			plainOpts = append(plainOpts, opt)
+			continue
+		}
+
+		switch mode {
+		case tele.ModeMarkdown, tele.ModeMarkdownV2:
+			message = markdown.StripV2(message)
+		case tele.ModeHTML:
+			message = html.UnescapeString(htmlTagRegexp.ReplaceAllString(message, ""))
+		}
+	}
+
+	return message, plainOpts
+}
+
+func (s *svc) SendReauthorizationRequest(id int64, message string) error {
+	language, err := s.userSettingsSvc.Language(context.Background(), id)
+	if err != nil {
+		log.Error().Int64("user", id).Msgf("userSettingsSvc.Language: %v", err.Error())
+	}
+
+	markup := s.bot.NewMarkup()
+	markup.Inline(markup.Row(markup.Data(
+		answers.Text(language, answers.ReauthorizationButton),
+		callbackReauthorization,
+	)))
+
+	return s.SendMessageWithOpts(id, message, markup)
+}
+
+// groupCommands команды, которые бот обрабатывает в групповых чатах
+var groupCommands = map[string]bool{
+	"/help":  true,
+	"/join":  true,
+	"/leave": true,
+}
+
+// chatTypeMiddleware в групповом чате бот не отвечает на переписку и не показывает личные данные: проходят только
+// groupCommands и служебные обновления о самом чате. Каналы бот не поддерживает
+func (s *svc) chatTypeMiddleware(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		chat := c.Chat()
+		if chat == nil || chat.Type == tele.ChatPrivate {
+			return next(c)
+		}
+		if !isGroupChat(chat) || c.Callback() != nil {
+			return nil
+		}
+		if c.ChatMember() != nil {
+			return next(c)
+		}
+
+		message := c.Message()
+		if message == nil {
+			return nil
+		}
+		if message.MigrateTo != 0 || message.UserLeft != nil || groupCommands[commandName(message.Text)] {
+			return next(c)
+		}
+
+		return nil
+	}
+}
+
+func isGroupChat(chat *tele.Chat) bool {
+	return chat != nil && (chat.Type == tele.ChatGroup || chat.Type == tele.ChatSuperGroup)
+}
+
+// commandName команда без упоминания бота: "/join@bot" -> "/join"
+func commandName(text string) string {
+	fields := strings.Fields(text)
+	if len(fields) == 0 {
+		return ""
+	}
+
+	name, _, _ := strings.Cut(fields[0], "@")
+	return name
+}
+
+// languageMiddleware определяет язык пользователя один раз на каждое обновление, см. svc.language
+func (s *svc) languageMiddleware(next tele.HandlerFunc) tele.HandlerFunc {
+	return func(c tele.Context) error {
+		if c.Sender() != nil {
+			c.Set(contextKeyLanguage, s.resolveLanguage(c))
+		}
+
+		return next(c)
+	}
+}
+
+// resolveLanguage язык из настроек пользователя. Новым пользователям он определяется по language_code
+// и сохраняется, чтобы уведомления вне диалога приходили на том же языке
+func (s *svc) resolveLanguage(c tele.Context) domain.Language {
+	logger := log.With().Fields(extractTelebotFields(c)).Logger()
+	ctx := logger.WithContext(context.Background())
+
+	if item := s.languageCache.Get(c.Sender().ID); item != nil {
+		return item.Value()
+	}
+
+	settings, err := s.userSettingsSvc.UserSettings(ctx, c.Sender().ID)
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.UserSettings: %w", err)
+		logger.Error().Msgf("resolveLanguage: %v", err.Error())
+		return domain.LanguageFromCode(c.Sender().LanguageCode)
+	}
+	if settings != nil {
+		s.languageCache.Set(c.Sender().ID, settings.Language, ttlcache.DefaultTTL)
+		return settings.Language
+	}
+
+	language := domain.LanguageFromCode(c.Sender().LanguageCode)
+	err = s.userSettingsSvc.SetLanguage(ctx, c.Sender().ID, language)
+	if err != nil {
+		err = fmt.Errorf("userSettingsSvc.SetLanguage: %w", err)
+		logger.Error().Msgf("resolveLanguage: %v", err.Error())
+		return language
+	}
+	s.languageCache.Set(c.Sender().ID, language, ttlcache.DefaultTTL)
+
+	return language
+}
+
+func (s *svc) language(c tele.Context) domain.Language {
+	if language, ok := c.Get(contextKeyLanguage).(domain.Language); ok {
+		return language
+	}
+
+	return domain.LanguageFromCode(c.Sender().LanguageCode)
 }
 
 func (s *svc) EditMessageWithOpts(id int64, messageID int, msg string, opts ...interface{}) error {
@@ -117,6 +449,18 @@ func (s *svc) EditMessageWithOpts(id int64, messageID int, msg string, opts ...i
 		msg,
 		opts...,
 	)
+	if isParseEntitiesError(err) {
+		log.Warn().Int64("user", id).Msgf("editing message without markup: %v", err)
+		plainMsg, plainOpts := withoutMarkup(msg, opts)
+		_, err = s.bot.Edit(
+			&editableMessage{
+				messageID: messageID,
+				chatID:    id,
+			},
+			plainMsg,
+			plainOpts...,
+		)
+	}
 
 	if errors.Is(err, tele.ErrTrueResult) {
 		err = s.SendMessageWithOpts(id, "TODO bot error")
@@ -160,9 +504,14 @@ func (s *svc) middlewareError(targetUserID int64, err error) error {
 }
 
 func (s *svc) Start() {
+	// удаляют устаревшие записи, чтобы кэши не росли за счёт тех, кто больше не пишет боту
+	go s.progressTableCache.Start()
+	go s.languageCache.Start()
 	s.bot.Start()
 }
 
 func (s *svc) Stop() {
 	s.bot.Stop()
+	s.progressTableCache.Stop()
+	s.languageCache.Stop()
 }
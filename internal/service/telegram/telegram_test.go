@@ -0,0 +1,88 @@
+package telegram
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	tele "gopkg.in/telebot.v3"
+)
+
+func TestIsParseEntitiesError(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "nil", err: nil, want: false},
+		{name: "other error", err: errors.New("can't parse entities"), want: false},
+		{name: "other telegram error", err: tele.ErrBlockedByUser, want: false},
+		{
+			name: "parse error",
+			err:  tele.NewError(400, "Bad Request: can't parse entities: Character '_' is reserved"),
+			want: true,
+		},
+		{
+			name: "wrapped parse error",
+			err:  fmt.Errorf("bot.Send: %w", tele.NewError(400, "Bad Request: can't parse entities")),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		if got := isParseEntitiesError(tt.err); got != tt.want {
+			t.Errorf("%s: isParseEntitiesError() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestWithoutMarkup(t *testing.T) {
+	markup := &tele.ReplyMarkup{}
+	tests := []struct {
+		name        string
+		message     string
+		opts        []interface{}
+		wantMessage string
+		wantOpts    []interface{}
+	}{
+		{
+			name:        "markdownv2",
+			message:     `*Программирование\_на\_C\+\+*: 5`,
+			opts:        []interface{}{tele.ModeMarkdownV2, markup},
+			wantMessage: "Программирование_на_C++: 5",
+			wantOpts:    []interface{}{markup},
+		},
+		{
+			name:        "markdown",
+			message:     "*Физика*",
+			opts:        []interface{}{tele.ModeMarkdown},
+			wantMessage: "Физика",
+			wantOpts:    []interface{}{},
+		},
+		{
+			name:        "html",
+			message:     "<b>Физика &amp; химия</b>",
+			opts:        []interface{}{tele.ModeHTML},
+			wantMessage: "Физика & химия",
+			wantOpts:    []interface{}{},
+		},
+		{
+			name:        "without parse mode",
+			message:     "*Физика*",
+			opts:        []interface{}{markup},
+			wantMessage: "*Физика*",
+			wantOpts:    []interface{}{markup},
+		},
+	}
+
+	for _, tt := range tests {
+		message, opts := withoutMarkup(tt.message, tt.opts)
+		if message != tt.wantMessage {
+			t.Errorf("%s: message = %q, want %q", tt.name, message, tt.wantMessage)
+		}
+		if !reflect.DeepEqual(opts, tt.wantOpts) {
+			t.Errorf("%s: opts = %v, want %v", tt.name, opts, tt.wantOpts)
+		}
+	}
+}
@@ -2,6 +2,7 @@ package service
 
 import (
 	"context"
+	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 )
@@ -11,11 +12,42 @@ type User interface {
 	User(ctx context.Context, userID int64) (*domain.User, error)
 	// TODO добавить фильтр по deleted_at, фильтры. провалидировать всю логику
 	Users(ctx context.Context) ([]*domain.User, error)
+	Suspend(ctx context.Context, userID int64) error
+	SuspendedUserIDs(ctx context.Context, suspendedBefore time.Time) ([]int64, error)
 	Delete(ctx context.Context, userID int64) error
+	// UserStatus возвращает nil, если пользователь ни разу не писал боту
+	UserStatus(ctx context.Context, userID int64) (*domain.UserStatus, error)
+	UserStatusesByUsername(ctx context.Context, username string) ([]*domain.UserStatus, error)
+	Counts(ctx context.Context) (*domain.UsersCounts, error)
+	SaveCheckedAt(ctx context.Context, userID int64, checkedAt time.Time) error
+	// RequestCheck ставит внеочередную проверку оценок, false - оценки пользователя сейчас не отслеживаются
+	RequestCheck(ctx context.Context, userID int64) (bool, error)
+	CheckRequestedUsers(ctx context.Context) ([]*domain.User, error)
+	ClearCheckRequest(ctx context.Context, userID int64) error
 	UpdateProgressTable(
 		ctx context.Context,
 		userID int64,
 		progressTable *domain.ProgressTable,
 		gradesChanges []*domain.GradeChange,
 	) error
+	RolloverProgressTable(
+		ctx context.Context,
+		userID int64,
+		oldProgressTable *domain.ProgressTable,
+		newProgressTable *domain.ProgressTable,
+	) error
+	ArchiveProgressTable(ctx context.Context, userID int64, progressTable *domain.ProgressTable) error
+	ArchivedProgressTables(ctx context.Context, userID int64) ([]*domain.ProgressTable, error)
+	RecordBook(ctx context.Context, userID int64) (*domain.RecordBook, error)
+	UpdateRecordBook(
+		ctx context.Context,
+		userID int64,
+		recordBook *domain.RecordBook,
+		gradesChanges []*domain.GradeChange,
+	) error
+	Schedule(ctx context.Context, userID int64) (*domain.Schedule, error)
+	SaveSchedule(ctx context.Context, userID int64, schedule *domain.Schedule) error
+	GradesHistory(ctx context.Context, userID int64) ([]*domain.GradeChange, error)
+	// GradesHistorySince изменения, сохранённые строго позже since
+	GradesHistorySince(ctx context.Context, userID int64, since time.Time) ([]*domain.GradeChange, error)
 }
@@ -3,6 +3,7 @@ package user
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
 	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
@@ -47,6 +48,24 @@ func (s *svc) Users(ctx context.Context) ([]*domain.User, error) {
 	return users, nil
 }
 
+func (s *svc) Suspend(ctx context.Context, userID int64) error {
+	err := s.usersRepository.Suspend(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("usersRepository.Suspend: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) SuspendedUserIDs(ctx context.Context, suspendedBefore time.Time) ([]int64, error) {
+	userIDs, err := s.usersRepository.SuspendedUserIDs(ctx, suspendedBefore)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.SuspendedUserIDs: %w", err)
+	}
+
+	return userIDs, nil
+}
+
 func (s *svc) Delete(ctx context.Context, userID int64) error {
 	err := s.usersRepository.Delete(ctx, userID)
 	if err != nil {
@@ -56,6 +75,69 @@ func (s *svc) Delete(ctx context.Context, userID int64) error {
 	return nil
 }
 
+func (s *svc) UserStatus(ctx context.Context, userID int64) (*domain.UserStatus, error) {
+	status, err := s.usersRepository.UserStatus(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.UserStatus: %w", err)
+	}
+
+	return status, nil
+}
+
+func (s *svc) UserStatusesByUsername(ctx context.Context, username string) ([]*domain.UserStatus, error) {
+	statuses, err := s.usersRepository.UserStatusesByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.UserStatusesByUsername: %w", err)
+	}
+
+	return statuses, nil
+}
+
+func (s *svc) Counts(ctx context.Context) (*domain.UsersCounts, error) {
+	counts, err := s.usersRepository.Counts(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.Counts: %w", err)
+	}
+
+	return counts, nil
+}
+
+func (s *svc) SaveCheckedAt(ctx context.Context, userID int64, checkedAt time.Time) error {
+	err := s.usersRepository.SaveCheckedAt(ctx, userID, checkedAt)
+	if err != nil {
+		return fmt.Errorf("usersRepository.SaveCheckedAt: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) RequestCheck(ctx context.Context, userID int64) (bool, error) {
+	requested, err := s.usersRepository.RequestCheck(ctx, userID)
+	if err != nil {
+		return false, fmt.Errorf("usersRepository.RequestCheck: %w", err)
+	}
+
+	return requested, nil
+}
+
+func (s *svc) CheckRequestedUsers(ctx context.Context) ([]*domain.User, error) {
+	users, err := s.usersRepository.CheckRequestedUsers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.CheckRequestedUsers: %w", err)
+	}
+
+	return users, nil
+}
+
+func (s *svc) ClearCheckRequest(ctx context.Context, userID int64) error {
+	err := s.usersRepository.ClearCheckRequest(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("usersRepository.ClearCheckRequest: %w", err)
+	}
+
+	return nil
+}
+
 func (s *svc) UpdateProgressTable(
 	ctx context.Context,
 	userID int64,
@@ -69,3 +151,93 @@ func (s *svc) UpdateProgressTable(
 
 	return nil
 }
+
+func (s *svc) RolloverProgressTable(
+	ctx context.Context,
+	userID int64,
+	oldProgressTable *domain.ProgressTable,
+	newProgressTable *domain.ProgressTable,
+) error {
+	err := s.usersRepository.RolloverProgressTable(ctx, userID, oldProgressTable, newProgressTable)
+	if err != nil {
+		return fmt.Errorf("usersRepository.RolloverProgressTable: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) ArchiveProgressTable(
+	ctx context.Context,
+	userID int64,
+	progressTable *domain.ProgressTable,
+) error {
+	err := s.usersRepository.ArchiveProgressTable(ctx, userID, progressTable)
+	if err != nil {
+		return fmt.Errorf("usersRepository.ArchiveProgressTable: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) ArchivedProgressTables(ctx context.Context, userID int64) ([]*domain.ProgressTable, error) {
+	progressTables, err := s.usersRepository.ArchivedProgressTables(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.ArchivedProgressTables: %w", err)
+	}
+
+	return progressTables, nil
+}
+
+func (s *svc) RecordBook(ctx context.Context, userID int64) (*domain.RecordBook, error) {
+	recordBook, err := s.usersRepository.RecordBook(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.RecordBook: %w", err)
+	}
+
+	return recordBook, nil
+}
+
+func (s *svc) UpdateRecordBook(
+	ctx context.Context,
+	userID int64,
+	recordBook *domain.RecordBook,
+	gradesChanges []*domain.GradeChange,
+) error {
+	err := s.usersRepository.UpdateRecordBook(ctx, userID, recordBook, gradesChanges)
+	if err != nil {
+		return fmt.Errorf("usersRepository.UpdateRecordBook: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) Schedule(ctx context.Context, userID int64) (*domain.Schedule, error) {
+	schedule, err := s.usersRepository.Schedule(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.Schedule: %w", err)
+	}
+
+	return schedule, nil
+}
+
+func (s *svc) SaveSchedule(ctx context.Context, userID int64, schedule *domain.Schedule) error {
+	err := s.usersRepository.SaveSchedule(ctx, userID, schedule)
+	if err != nil {
+		return fmt.Errorf("usersRepository.SaveSchedule: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) GradesHistory(ctx context.Context, userID int64) ([]*domain.GradeChange, error) {
+	return s.GradesHistorySince(ctx, userID, time.Time{})
+}
+
+func (s *svc) GradesHistorySince(ctx context.Context, userID int64, since time.Time) ([]*domain.GradeChange, error) {
+	gradesChanges, err := s.usersRepository.GradesHistory(ctx, userID, since)
+	if err != nil {
+		return nil, fmt.Errorf("usersRepository.GradesHistory: %w", err)
+	}
+
+	return gradesChanges, nil
+}
@@ -0,0 +1,23 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type UserSettings interface {
+	UserSettings(ctx context.Context, userID int64) (*domain.UserSettings, error)
+	// Language язык пользователя или domain.DefaultLanguage, если он ещё не сохранён
+	Language(ctx context.Context, userID int64) (domain.Language, error)
+	SetLanguage(ctx context.Context, userID int64, language domain.Language) error
+	// RequestEmailVerification отправляет на email код, которым адрес подтверждается в VerifyEmail
+	RequestEmailVerification(ctx context.Context, userID int64, email string) error
+	VerifyEmail(ctx context.Context, userID int64, code string) (*domain.UserSettings, error)
+	// SetNotificationChannels канал email доступен только с подтверждённым адресом
+	SetNotificationChannels(ctx context.Context, userID int64, channels []domain.NotificationChannel) error
+	SetWeeklySummary(ctx context.Context, userID int64, enabled bool) (*domain.UserSettings, error)
+	// SetReminderDaysBefore 0 выключает напоминания, больше domain.MaxReminderDaysBefore - ErrInvalidReminderDays
+	SetReminderDaysBefore(ctx context.Context, userID int64, days int) (*domain.UserSettings, error)
+	SetShareGrades(ctx context.Context, userID int64, enabled bool) (*domain.UserSettings, error)
+}
@@ -0,0 +1,308 @@
+package user_settings
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"fmt"
+	"math/big"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/config/answers"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/ilyadubrovsky/tracking-bars/internal/service"
+)
+
+const verificationCodeDigits = 6
+
+type svc struct {
+	userSettingsRepo repository.UserSettings
+	emailSvc         service.Email
+	cfg              config.Email
+}
+
+func NewService(
+	userSettingsRepo repository.UserSettings,
+	emailSvc service.Email,
+	cfg config.Email,
+) *svc {
+	return &svc{
+		userSettingsRepo: userSettingsRepo,
+		emailSvc:         emailSvc,
+		cfg:              cfg,
+	}
+}
+
+func (s *svc) UserSettings(ctx context.Context, userID int64) (*domain.UserSettings, error) {
+	settings, err := s.userSettingsRepo.UserSettings(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.UserSettings: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *svc) Language(ctx context.Context, userID int64) (domain.Language, error) {
+	settings, err := s.UserSettings(ctx, userID)
+	if err != nil {
+		return domain.DefaultLanguage, err
+	}
+	if settings == nil {
+		return domain.DefaultLanguage, nil
+	}
+
+	return settings.Language, nil
+}
+
+func (s *svc) SetLanguage(ctx context.Context, userID int64, language domain.Language) error {
+	settings, err := s.userSettingsOrDefault(ctx, userID)
+	if err != nil {
+		return err
+	}
+	settings.Language = language
+
+	err = s.userSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return fmt.Errorf("userSettingsRepo.Save: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) RequestEmailVerification(ctx context.Context, userID int64, email string) error {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	// адрес вида "Имя <user@host>" тоже разбирается, но сохраняем только сам адрес
+	if err != nil || address.Name != "" || address.Address != strings.TrimSpace(email) {
+		return ierrors.ErrInvalidEmail
+	}
+
+	language, err := s.Language(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	previous, err := s.userSettingsRepo.EmailVerification(ctx, userID)
+	if err != nil {
+		return fmt.Errorf("userSettingsRepo.EmailVerification: %w", err)
+	}
+
+	now := time.Now()
+	verification, err := s.nextEmailVerification(previous, userID, address.Address, now)
+	if err != nil {
+		return err
+	}
+
+	code, err := generateVerificationCode()
+	if err != nil {
+		return fmt.Errorf("generateVerificationCode: %w", err)
+	}
+	verification.CodeHash = hashVerificationCode(code)
+
+	err = s.userSettingsRepo.SaveEmailVerification(ctx, verification)
+	if err != nil {
+		return fmt.Errorf("userSettingsRepo.SaveEmailVerification: %w", err)
+	}
+
+	ttlMinutes := int(s.cfg.VerificationCodeTTL.Minutes())
+	message := domain.NewPlainMessage(answers.Textf(language, answers.EmailVerificationBody, code, code, ttlMinutes))
+	err = s.emailSvc.Send(ctx, address.Address, answers.Text(language, answers.EmailVerificationSubject), message)
+	if err != nil {
+		return fmt.Errorf("emailSvc.Send: %w", err)
+	}
+
+	return nil
+}
+
+// nextEmailVerification новый код не выдаётся чаще VerificationResendInterval и больше
+// VerificationMaxCodesPerDay раз за сутки, а попытки ввода в течение суток копятся по всем кодам
+func (s *svc) nextEmailVerification(
+	previous *domain.EmailVerification,
+	userID int64,
+	email string,
+	now time.Time,
+) (*domain.EmailVerification, error) {
+	verification := &domain.EmailVerification{
+		UserID:         userID,
+		Email:          email,
+		ExpiresAt:      now.Add(s.cfg.VerificationCodeTTL),
+		CreatedAt:      now,
+		CodesSent:      1,
+		CodesSentSince: now,
+	}
+	if previous == nil || previous.IsCodesPeriodOver(now) {
+		return verification, nil
+	}
+
+	if now.Before(previous.CreatedAt.Add(s.cfg.VerificationResendInterval)) {
+		return nil, ierrors.ErrVerificationResendTooSoon
+	}
+	if previous.Attempts >= s.cfg.VerificationMaxAttempts {
+		return nil, ierrors.ErrVerificationAttemptsEnded
+	}
+	if previous.CodesSent >= s.cfg.VerificationMaxCodesPerDay {
+		return nil, ierrors.ErrVerificationCodesLimit
+	}
+
+	verification.Attempts = previous.Attempts
+	verification.CodesSent = previous.CodesSent + 1
+	verification.CodesSentSince = previous.CodesSentSince
+
+	return verification, nil
+}
+
+func (s *svc) VerifyEmail(ctx context.Context, userID int64, code string) (*domain.UserSettings, error) {
+	verification, err := s.userSettingsRepo.EmailVerification(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.EmailVerification: %w", err)
+	}
+	if verification == nil {
+		return nil, ierrors.ErrVerificationNotFound
+	}
+
+	now := time.Now()
+	if verification.IsExpired(now) {
+		return nil, ierrors.ErrVerificationExpired
+	}
+	if verification.Attempts >= s.cfg.VerificationMaxAttempts {
+		return nil, ierrors.ErrVerificationAttemptsEnded
+	}
+
+	if subtle.ConstantTimeCompare(verification.CodeHash, hashVerificationCode(strings.TrimSpace(code))) != 1 {
+		verification.Attempts++
+		err = s.userSettingsRepo.SaveEmailVerification(ctx, verification)
+		if err != nil {
+			return nil, fmt.Errorf("userSettingsRepo.SaveEmailVerification: %w", err)
+		}
+		if verification.Attempts >= s.cfg.VerificationMaxAttempts {
+			return nil, ierrors.ErrVerificationAttemptsEnded
+		}
+
+		return nil, ierrors.ErrWrongVerificationCode
+	}
+
+	settings, err := s.userSettingsOrDefault(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.Email = verification.Email
+	settings.EmailVerifiedAt = &now
+
+	err = s.userSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.Save: %w", err)
+	}
+
+	err = s.userSettingsRepo.DeleteEmailVerification(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.DeleteEmailVerification: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *svc) SetNotificationChannels(ctx context.Context, userID int64, channels []domain.NotificationChannel) error {
+	settings, err := s.userSettingsOrDefault(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, channel := range channels {
+		if channel == domain.NotificationChannelEmail && !settings.IsEmailVerified() {
+			return ierrors.ErrEmailNotVerified
+		}
+	}
+	settings.NotificationChannels = channels
+
+	err = s.userSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return fmt.Errorf("userSettingsRepo.Save: %w", err)
+	}
+
+	return nil
+}
+
+func (s *svc) SetWeeklySummary(ctx context.Context, userID int64, enabled bool) (*domain.UserSettings, error) {
+	settings, err := s.userSettingsOrDefault(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.WeeklySummary = enabled
+
+	err = s.userSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.Save: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *svc) SetReminderDaysBefore(ctx context.Context, userID int64, days int) (*domain.UserSettings, error) {
+	if days < 0 || days > domain.MaxReminderDaysBefore {
+		return nil, ierrors.ErrInvalidReminderDays
+	}
+
+	settings, err := s.userSettingsOrDefault(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ReminderDaysBefore = days
+
+	err = s.userSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.Save: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *svc) SetShareGrades(ctx context.Context, userID int64, enabled bool) (*domain.UserSettings, error) {
+	settings, err := s.userSettingsOrDefault(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ShareGrades = enabled
+
+	err = s.userSettingsRepo.Save(ctx, settings)
+	if err != nil {
+		return nil, fmt.Errorf("userSettingsRepo.Save: %w", err)
+	}
+
+	return settings, nil
+}
+
+func (s *svc) userSettingsOrDefault(ctx context.Context, userID int64) (*domain.UserSettings, error) {
+	settings, err := s.UserSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if settings == nil {
+		settings = domain.NewUserSettings(userID, domain.DefaultLanguage)
+	}
+
+	return settings, nil
+}
+
+func generateVerificationCode() (string, error) {
+	max := big.NewInt(1)
+	for i := 0; i < verificationCodeDigits; i++ {
+		max.Mul(max, big.NewInt(10))
+	}
+
+	n, err := rand.Int(rand.Reader, max)
+	if err != nil {
+		return "", fmt.Errorf("rand.Int: %w", err)
+	}
+
+	return fmt.Sprintf("%0*d", verificationCodeDigits, n), nil
+}
+
+func hashVerificationCode(code string) []byte {
+	hash := sha256.Sum256([]byte(code))
+	return hash[:]
+}
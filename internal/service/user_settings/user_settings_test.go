@@ -0,0 +1,74 @@
+package user_settings
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+)
+
+func TestNextEmailVerification(t *testing.T) {
+	s := NewService(nil, nil, config.Email{
+		VerificationCodeTTL:        15 * time.Minute,
+		VerificationMaxAttempts:    5,
+		VerificationResendInterval: time.Minute,
+		VerificationMaxCodesPerDay: 3,
+	})
+	now := time.Date(2024, time.October, 18, 12, 0, 0, 0, time.UTC)
+	previous := func(sentAgo time.Duration, codesSent int, attempts int) *domain.EmailVerification {
+		return &domain.EmailVerification{
+			Attempts:       attempts,
+			CreatedAt:      now.Add(-sentAgo),
+			CodesSent:      codesSent,
+			CodesSentSince: now.Add(-2 * time.Hour),
+		}
+	}
+
+	tests := []struct {
+		name         string
+		previous     *domain.EmailVerification
+		wantErr      error
+		wantCodes    int
+		wantAttempts int
+	}{
+		{name: "first code", wantCodes: 1},
+		{name: "too soon", previous: previous(30*time.Second, 1, 0), wantErr: ierrors.ErrVerificationResendTooSoon},
+		// попытки не сбрасываются, иначе новыми кодами можно перебирать код бесконечно
+		{name: "resend keeps attempts", previous: previous(2*time.Minute, 1, 2), wantCodes: 2, wantAttempts: 2},
+		{name: "attempts ended", previous: previous(2*time.Minute, 1, 5), wantErr: ierrors.ErrVerificationAttemptsEnded},
+		{name: "codes limit", previous: previous(2*time.Minute, 3, 0), wantErr: ierrors.ErrVerificationCodesLimit},
+		{
+			name: "next day",
+			previous: &domain.EmailVerification{
+				Attempts:       5,
+				CreatedAt:      now.Add(-25 * time.Hour),
+				CodesSent:      3,
+				CodesSentSince: now.Add(-25 * time.Hour),
+			},
+			wantCodes: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		verification, err := s.nextEmailVerification(tt.previous, 1, "student@mpei.ru", now)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: error = %v, want %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if tt.wantErr != nil {
+			continue
+		}
+		if verification.CodesSent != tt.wantCodes || verification.Attempts != tt.wantAttempts {
+			t.Errorf(
+				"%s: codes sent %d, attempts %d, want %d and %d",
+				tt.name, verification.CodesSent, verification.Attempts, tt.wantCodes, tt.wantAttempts,
+			)
+		}
+		if !verification.ExpiresAt.Equal(now.Add(15 * time.Minute)) {
+			t.Errorf("%s: expires at %s, want %s", tt.name, verification.ExpiresAt, now.Add(15*time.Minute))
+		}
+	}
+}
@@ -0,0 +1,17 @@
+package service
+
+import (
+	"context"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+type Webhooks interface {
+	Webhooks(ctx context.Context, userID int64) ([]*domain.Webhook, error)
+	// AddWebhook генерирует общий секрет и возвращает его в открытом виде: в БД он хранится зашифрованным
+	AddWebhook(ctx context.Context, userID int64, rawURL string) (*domain.Webhook, string, error)
+	DeleteWebhook(ctx context.Context, userID int64, webhookID int64) error
+	// SendGradeChange отправляет изменение на вебхук его владельца подписанным POST-запросом
+	SendGradeChange(ctx context.Context, webhookID int64, gradeChange *domain.GradeChange) error
+	SendTest(ctx context.Context, userID int64, webhookID int64) error
+}
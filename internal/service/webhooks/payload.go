@@ -0,0 +1,44 @@
+package webhooks
+
+import (
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+)
+
+const (
+	eventGradeChange = "grade_change"
+	eventTest        = "test"
+)
+
+// payload тело запроса. Получатель может отбрасывать повторы по grade_change.id:
+// при сетевых ошибках одно изменение может быть доставлено несколько раз
+type payload struct {
+	Event       string              `json:"event"`
+	SentAt      time.Time           `json:"sent_at"`
+	GradeChange *gradeChangePayload `json:"grade_change,omitempty"`
+}
+
+type gradeChangePayload struct {
+	ID           int64     `json:"id"`
+	Discipline   string    `json:"discipline"`
+	ControlEvent string    `json:"control_event"`
+	OldGrade     string    `json:"old_grade"`
+	NewGrade     string    `json:"new_grade"`
+	CreatedAt    time.Time `json:"created_at"`
+	Semester     string    `json:"semester,omitempty"`
+	RecordBook   bool      `json:"record_book,omitempty"`
+}
+
+func gradeChangePayloadFromDomain(gradeChange *domain.GradeChange) *gradeChangePayload {
+	return &gradeChangePayload{
+		ID:           gradeChange.ID,
+		Discipline:   gradeChange.Discipline,
+		ControlEvent: gradeChange.ControlEvent,
+		OldGrade:     gradeChange.OldGrade,
+		NewGrade:     gradeChange.NewGrade,
+		CreatedAt:    gradeChange.CreatedAt,
+		Semester:     gradeChange.Semester,
+		RecordBook:   gradeChange.RecordBook,
+	}
+}
@@ -0,0 +1,239 @@
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+
+	"github.com/ilyadubrovsky/tracking-bars/internal/config"
+	"github.com/ilyadubrovsky/tracking-bars/internal/domain"
+	ierrors "github.com/ilyadubrovsky/tracking-bars/internal/errors"
+	"github.com/ilyadubrovsky/tracking-bars/internal/repository"
+	"github.com/ilyadubrovsky/tracking-bars/pkg/aes"
+)
+
+const (
+	// signatureHeader содержит "sha256=" и hex HMAC-SHA256 тела запроса на общем секрете
+	signatureHeader = "X-Tracking-Bars-Signature"
+	eventHeader     = "X-Tracking-Bars-Event"
+
+	secretBytes = 32
+	// maxResponseBodySize тело ответа не нужно, но его стоит дочитать, чтобы переиспользовать соединение
+	maxResponseBodySize = 64 << 10
+)
+
+var errPrivateAddress = errors.New("address is in a private network")
+
+type svc struct {
+	webhooksRepo  repository.Webhooks
+	httpClient    *http.Client
+	encryptionKey string
+	cfg           config.Webhooks
+}
+
+func NewService(
+	webhooksRepo repository.Webhooks,
+	encryptionKey string,
+	cfg config.Webhooks,
+) *svc {
+	dialer := &net.Dialer{Timeout: cfg.Timeout}
+	if !cfg.AllowPrivateNetworks {
+		dialer.Control = denyPrivateAddresses
+	}
+
+	return &svc{
+		webhooksRepo: webhooksRepo,
+		httpClient: &http.Client{
+			Timeout:   cfg.Timeout,
+			Transport: &http.Transport{DialContext: dialer.DialContext},
+			// редирект мог бы увести запрос на http или во внутреннюю сеть
+			CheckRedirect: func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		encryptionKey: encryptionKey,
+		cfg:           cfg,
+	}
+}
+
+func (s *svc) Webhooks(ctx context.Context, userID int64) ([]*domain.Webhook, error) {
+	webhooks, err := s.webhooksRepo.Webhooks(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("webhooksRepo.Webhooks: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+func (s *svc) AddWebhook(ctx context.Context, userID int64, rawURL string) (*domain.Webhook, string, error) {
+	if err := s.validateURL(rawURL); err != nil {
+		return nil, "", err
+	}
+
+	webhooks, err := s.Webhooks(ctx, userID)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(webhooks) >= s.cfg.MaxPerUser {
+		return nil, "", ierrors.ErrWebhooksLimitReached
+	}
+
+	secret := make([]byte, secretBytes)
+	if _, err = rand.Read(secret); err != nil {
+		return nil, "", fmt.Errorf("rand.Read: %w", err)
+	}
+	hexSecret := hex.EncodeToString(secret)
+
+	encryptedSecret, err := aes.Encrypt([]byte(s.encryptionKey), []byte(hexSecret))
+	if err != nil {
+		return nil, "", fmt.Errorf("aes.Encrypt (secret): %w", err)
+	}
+
+	webhook := &domain.Webhook{
+		UserID:    userID,
+		URL:       rawURL,
+		Secret:    encryptedSecret,
+		CreatedAt: time.Now(),
+	}
+	err = s.webhooksRepo.Create(ctx, webhook)
+	if err != nil {
+		return nil, "", fmt.Errorf("webhooksRepo.Create: %w", err)
+	}
+
+	return webhook, hexSecret, nil
+}
+
+func (s *svc) DeleteWebhook(ctx context.Context, userID int64, webhookID int64) error {
+	deleted, err := s.webhooksRepo.Delete(ctx, userID, webhookID)
+	if err != nil {
+		return fmt.Errorf("webhooksRepo.Delete: %w", err)
+	}
+	if !deleted {
+		return ierrors.ErrWebhookNotFound
+	}
+
+	return nil
+}
+
+func (s *svc) SendGradeChange(ctx context.Context, webhookID int64, gradeChange *domain.GradeChange) error {
+	webhook, err := s.webhook(ctx, gradeChange.UserID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, webhook, &payload{
+		Event:       eventGradeChange,
+		SentAt:      time.Now(),
+		GradeChange: gradeChangePayloadFromDomain(gradeChange),
+	})
+}
+
+func (s *svc) SendTest(ctx context.Context, userID int64, webhookID int64) error {
+	webhook, err := s.webhook(ctx, userID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	return s.send(ctx, webhook, &payload{
+		Event:  eventTest,
+		SentAt: time.Now(),
+	})
+}
+
+func (s *svc) webhook(ctx context.Context, userID int64, webhookID int64) (*domain.Webhook, error) {
+	webhook, err := s.webhooksRepo.Webhook(ctx, userID, webhookID)
+	if err != nil {
+		return nil, fmt.Errorf("webhooksRepo.Webhook: %w", err)
+	}
+	if webhook == nil {
+		return nil, ierrors.ErrWebhookNotFound
+	}
+
+	return webhook, nil
+}
+
+func (s *svc) send(ctx context.Context, webhook *domain.Webhook, p *payload) error {
+	body, err := json.Marshal(p)
+	if err != nil {
+		return fmt.Errorf("json.Marshal: %w", err)
+	}
+
+	secret, err := aes.Decrypt([]byte(s.encryptionKey), webhook.Secret)
+	if err != nil {
+		return fmt.Errorf("aes.Decrypt: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("http.NewRequestWithContext: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "tracking-bars-webhooks")
+	req.Header.Set(eventHeader, p.Event)
+	req.Header.Set(signatureHeader, "sha256="+sign([]byte(secret), body))
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("httpClient.Do: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBodySize))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("unexpected status code %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func (s *svc) validateURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || u.User != nil {
+		return ierrors.ErrInvalidWebhookURL
+	}
+	if u.Scheme != "https" && !(s.cfg.AllowPrivateNetworks && u.Scheme == "http") {
+		return ierrors.ErrInvalidWebhookURL
+	}
+
+	return nil
+}
+
+// sign hex HMAC-SHA256 тела запроса, получатель сверяет его с заголовком signatureHeader
+func sign(secret []byte, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// denyPrivateAddresses проверяет уже разрешённый адрес, поэтому DNS не позволит обойти запрет
+func denyPrivateAddresses(_ string, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return fmt.Errorf("net.SplitHostPort: %w", err)
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil ||
+		ip.IsLoopback() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsMulticast() {
+		return fmt.Errorf("%s: %w", host, errPrivateAddress)
+	}
+
+	return nil
+}
@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE authorization_failures (
+    user_id BIGINT PRIMARY KEY,
+    count INT NOT NULL,
+    last_error TEXT NOT NULL,
+    first_failure_at TIMESTAMPTZ NOT NULL,
+    last_failure_at TIMESTAMPTZ NOT NULL
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS authorization_failures;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bars_credentials
+ADD COLUMN suspended_at TIMESTAMPTZ NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE bars_credentials
+DROP COLUMN IF EXISTS suspended_at;
+-- +goose StatementEnd
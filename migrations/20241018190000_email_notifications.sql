@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_settings
+    ADD COLUMN email TEXT,
+    ADD COLUMN email_verified_at TIMESTAMPTZ,
+    ADD COLUMN notification_channels TEXT[] NOT NULL DEFAULT '{telegram}';
+
+CREATE TABLE email_verifications (
+    user_id BIGINT PRIMARY KEY,
+    email TEXT NOT NULL,
+    code_hash BYTEA NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    codes_sent INT NOT NULL DEFAULT 1,
+    codes_sent_since TIMESTAMPTZ NOT NULL
+);
+
+CREATE TABLE grades_changes_deliveries (
+    grades_change_id BIGINT NOT NULL,
+    channel TEXT NOT NULL,
+    delivered_at TIMESTAMPTZ,
+    failures_count INT NOT NULL DEFAULT 0,
+    last_error TEXT NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (grades_change_id, channel)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS grades_changes_deliveries;
+DROP TABLE IF EXISTS email_verifications;
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS notification_channels,
+    DROP COLUMN IF EXISTS email_verified_at,
+    DROP COLUMN IF EXISTS email;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL,
+    url TEXT NOT NULL,
+    secret BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL
+);
+-- +goose StatementEnd
+-- +goose StatementBegin
+CREATE INDEX webhooks_user_id_idx ON webhooks (user_id);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE grades_changes_deliveries
+    ADD COLUMN endpoint TEXT NOT NULL DEFAULT '',
+    DROP CONSTRAINT grades_changes_deliveries_pkey,
+    ADD PRIMARY KEY (grades_change_id, channel, endpoint);
+-- +goose StatementEnd
+-- +goose StatementBegin
+ALTER TABLE grades_changes_outbox
+    ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+ALTER TABLE grades_changes_deliveries
+    ADD COLUMN next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX grades_changes_outbox_next_attempt_at_idx ON grades_changes_outbox (next_attempt_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS grades_changes_outbox_next_attempt_at_idx;
+ALTER TABLE grades_changes_deliveries
+    DROP COLUMN IF EXISTS next_attempt_at;
+ALTER TABLE grades_changes_outbox
+    DROP COLUMN IF EXISTS next_attempt_at;
+DELETE FROM grades_changes_deliveries WHERE endpoint <> '';
+ALTER TABLE grades_changes_deliveries
+    DROP CONSTRAINT grades_changes_deliveries_pkey,
+    DROP COLUMN IF EXISTS endpoint,
+    ADD PRIMARY KEY (grades_change_id, channel);
+DROP TABLE IF EXISTS webhooks;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE feed_tokens (
+    user_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    token_hash BYTEA NOT NULL UNIQUE,
+    token BYTEA NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, kind)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS feed_tokens;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_settings
+    ADD COLUMN weekly_summary BOOLEAN NOT NULL DEFAULT TRUE,
+    ADD COLUMN reminder_days_before INT NOT NULL DEFAULT 2;
+
+CREATE TABLE sent_reminders (
+    user_id BIGINT NOT NULL,
+    kind TEXT NOT NULL,
+    key TEXT NOT NULL,
+    sent_at TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (user_id, kind, key)
+);
+
+CREATE INDEX sent_reminders_sent_at_idx ON sent_reminders (sent_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS sent_reminders;
+
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS reminder_days_before,
+    DROP COLUMN IF EXISTS weekly_summary;
+-- +goose StatementEnd
@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE user_settings
+    ADD COLUMN share_grades BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE grade_distributions (
+    semester TEXT NOT NULL,
+    discipline TEXT NOT NULL,
+    control_event TEXT NOT NULL,
+    contributors INT NOT NULL,
+    q1 DOUBLE PRECISION NOT NULL,
+    median DOUBLE PRECISION NOT NULL,
+    q3 DOUBLE PRECISION NOT NULL,
+    updated_at TIMESTAMPTZ NOT NULL,
+    contributor_ids BIGINT[] NOT NULL,
+    PRIMARY KEY (semester, discipline, control_event)
+);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS grade_distributions;
+
+ALTER TABLE user_settings
+    DROP COLUMN IF EXISTS share_grades;
+-- +goose StatementEnd
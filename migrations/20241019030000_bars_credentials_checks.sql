@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+ALTER TABLE bars_credentials
+    ADD COLUMN checked_at TIMESTAMPTZ NULL,
+    ADD COLUMN check_requested_at TIMESTAMPTZ NULL;
+
+CREATE INDEX bars_credentials_username_idx ON bars_credentials (LOWER(username));
+
+CREATE INDEX bars_credentials_check_requested_at_idx ON bars_credentials (check_requested_at)
+    WHERE check_requested_at IS NOT NULL;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS bars_credentials_check_requested_at_idx;
+
+DROP INDEX IF EXISTS bars_credentials_username_idx;
+
+ALTER TABLE bars_credentials
+    DROP COLUMN IF EXISTS check_requested_at,
+    DROP COLUMN IF EXISTS checked_at;
+-- +goose StatementEnd
@@ -0,0 +1,107 @@
+// Package atom минимальная запись ленты в формате Atom (RFC 4287) с HTML-содержимым записей
+package atom
+
+import (
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+const namespace = "http://www.w3.org/2005/Atom"
+
+type Feed struct {
+	ID     string
+	Title  string
+	Author string
+	// SelfURL адрес самой ленты, по нему читалка обновляет подписку
+	SelfURL string
+	Updated time.Time
+	Entries []Entry
+}
+
+type Entry struct {
+	// ID не должен меняться между загрузками ленты, иначе читалка покажет запись повторно
+	ID         string
+	Title      string
+	Updated    time.Time
+	Categories []string
+	// HTML содержимое записи в разметке HTML
+	HTML string
+}
+
+type feedXML struct {
+	XMLName xml.Name   `xml:"feed"`
+	XMLNS   string     `xml:"xmlns,attr"`
+	ID      string     `xml:"id"`
+	Title   textXML    `xml:"title"`
+	Updated string     `xml:"updated"`
+	Author  authorXML  `xml:"author"`
+	Links   []linkXML  `xml:"link"`
+	Entries []entryXML `xml:"entry"`
+}
+
+type entryXML struct {
+	ID         string        `xml:"id"`
+	Title      textXML       `xml:"title"`
+	Updated    string        `xml:"updated"`
+	Categories []categoryXML `xml:"category"`
+	Content    textXML       `xml:"content"`
+}
+
+type textXML struct {
+	Type string `xml:"type,attr"`
+	Text string `xml:",chardata"`
+}
+
+type authorXML struct {
+	Name string `xml:"name"`
+}
+
+type linkXML struct {
+	Rel  string `xml:"rel,attr"`
+	Href string `xml:"href,attr"`
+}
+
+type categoryXML struct {
+	Term string `xml:"term,attr"`
+}
+
+func (f *Feed) Marshal() ([]byte, error) {
+	feed := feedXML{
+		XMLNS:   namespace,
+		ID:      f.ID,
+		Title:   textXML{Type: "text", Text: f.Title},
+		Updated: formatTime(f.Updated),
+		Author:  authorXML{Name: f.Author},
+		Entries: make([]entryXML, 0, len(f.Entries)),
+	}
+	if f.SelfURL != "" {
+		feed.Links = append(feed.Links, linkXML{Rel: "self", Href: f.SelfURL})
+	}
+
+	for _, entry := range f.Entries {
+		categories := make([]categoryXML, 0, len(entry.Categories))
+		for _, category := range entry.Categories {
+			categories = append(categories, categoryXML{Term: category})
+		}
+
+		feed.Entries = append(feed.Entries, entryXML{
+			ID:         entry.ID,
+			Title:      textXML{Type: "text", Text: entry.Title},
+			Updated:    formatTime(entry.Updated),
+			Categories: categories,
+			Content:    textXML{Type: "html", Text: entry.HTML},
+		})
+	}
+
+	content, err := xml.MarshalIndent(feed, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("xml.MarshalIndent: %w", err)
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,90 @@
+package atom
+
+import (
+	"bytes"
+	"encoding/xml"
+	"flag"
+	"os"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestFeedMarshal(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	feed := &Feed{
+		ID:      "urn:tracking-bars:grades:1",
+		Title:   "Оценки <БАРС> & изменения",
+		Author:  "tracking-bars",
+		SelfURL: "https://example.com/feeds/grades/token.atom?lang=ru&v=1",
+		// время записывается в UTC независимо от зоны
+		Updated: time.Date(2024, time.October, 14, 2, 15, 0, 0, moscow),
+		Entries: []Entry{
+			{
+				ID:         "urn:tracking-bars:grade-change:42",
+				Title:      "Математический анализ: КМ-1 \"Контрольная\"",
+				Updated:    time.Date(2024, time.October, 14, 2, 15, 0, 0, moscow),
+				Categories: []string{"2023-2024, весенний семестр"},
+				HTML:       "<p>Оценка: <b>5</b> &amp; зачёт</p>",
+			},
+			{
+				ID:      "urn:tracking-bars:grade-change:41",
+				Title:   "Физика",
+				Updated: time.Date(2024, time.October, 13, 23, 59, 59, 0, time.UTC),
+				HTML:    "<p>отсутствует → 4</p>",
+			},
+		},
+	}
+
+	got, err := feed.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	assertGolden(t, "testdata/feed.atom", got)
+
+	// экранированное содержимое должно читаться обратно без потерь
+	var parsed feedXML
+	if err = xml.Unmarshal(got, &parsed); err != nil {
+		t.Fatalf("xml.Unmarshal: %v", err)
+	}
+	if parsed.Title.Text != feed.Title {
+		t.Errorf("title %q, want %q", parsed.Title.Text, feed.Title)
+	}
+	if parsed.Entries[0].Content.Text != feed.Entries[0].HTML {
+		t.Errorf("content %q, want %q", parsed.Entries[0].Content.Text, feed.Entries[0].HTML)
+	}
+	if parsed.Links[0].Href != feed.SelfURL {
+		t.Errorf("self link %q, want %q", parsed.Links[0].Href, feed.SelfURL)
+	}
+}
+
+func TestFeedMarshalWithoutSelfURL(t *testing.T) {
+	feed := &Feed{ID: "urn:tracking-bars:grades:1", Updated: time.Unix(0, 0)}
+
+	got, err := feed.Marshal()
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if bytes.Contains(got, []byte("<link")) {
+		t.Errorf("feed without SelfURL contains a link:\n%s", got)
+	}
+}
+
+func assertGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("os.WriteFile: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,143 @@
+// Package fuzzy нечёткий поиск по коротким названиям: подстрока, сокращение или опечатка в слове
+package fuzzy
+
+import (
+	"strings"
+	"unicode"
+)
+
+const (
+	// substringScore совпадение подстрокой всегда лучше сокращения и опечатки
+	substringScore = 1000
+	// minTypoWordLength в более коротких словах запроса опечатки не допускаются
+	minTypoWordLength = 4
+)
+
+// Score насколько target подходит под query: чем больше, тем лучше. false - не подходит.
+// Регистр, буква ё и знаки препинания не учитываются, пустой запрос подходит под всё
+func Score(query string, target string) (int, bool) {
+	q := normalize(query)
+	t := normalize(target)
+	if q == "" {
+		return 0, true
+	}
+
+	if i := strings.Index(t, q); i >= 0 {
+		return substringScore - len([]rune(t[:i])), true
+	}
+
+	if score, ok := subsequenceScore([]rune(strings.ReplaceAll(q, " ", "")), []rune(t)); ok {
+		return score, true
+	}
+
+	if distance, ok := typoDistance(strings.Fields(q), strings.Fields(t)); ok {
+		return -distance, true
+	}
+
+	return 0, false
+}
+
+// normalize нижний регистр, ё -> е, всё кроме букв и цифр заменяется одним пробелом
+func normalize(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	space := false
+	for _, r := range strings.ToLower(s) {
+		if r == 'ё' {
+			r = 'е'
+		}
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			space = b.Len() != 0
+			continue
+		}
+		if space {
+			b.WriteRune(' ')
+			space = false
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// subsequenceScore буквы запроса встречаются в target по порядку, например "тоэ" в
+// "теоретические основы электротехники". Начала слов и подряд идущие буквы ценятся выше
+func subsequenceScore(q []rune, t []rune) (int, bool) {
+	score := 0
+	qi := 0
+	previous := -2
+	for ti := 0; ti < len(t) && qi < len(q); ti++ {
+		if t[ti] != q[qi] {
+			continue
+		}
+
+		switch {
+		case ti == 0 || t[ti-1] == ' ':
+			score += 3
+		case ti == previous+1:
+			score += 2
+		default:
+			score++
+		}
+		previous = ti
+		qi++
+	}
+
+	return score, qi == len(q)
+}
+
+// typoDistance каждое слово запроса должно совпасть с началом какого-нибудь слова target
+// с точностью до опечатки. Возвращает суммарное число исправлений
+func typoDistance(queryWords []string, targetWords []string) (int, bool) {
+	total := 0
+	for _, queryWord := range queryWords {
+		q := []rune(queryWord)
+		allowed := 0
+		if len(q) >= minTypoWordLength {
+			allowed = 1 + len(q)/8
+		}
+
+		best := allowed + 1
+		for _, targetWord := range targetWords {
+			t := []rune(targetWord)
+			// опечатка могла добавить или съесть букву, поэтому сравниваются начала трёх длин
+			for n := len(q) - 1; n <= len(q)+1; n++ {
+				if n < 1 || n > len(t) {
+					continue
+				}
+				if distance := levenshtein(q, t[:n]); distance < best {
+					best = distance
+				}
+			}
+		}
+		if best > allowed {
+			return 0, false
+		}
+
+		total += best
+	}
+
+	return total, true
+}
+
+func levenshtein(a []rune, b []rune) int {
+	previous := make([]int, len(b)+1)
+	current := make([]int, len(b)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		current[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(b)]
+}
@@ -0,0 +1,91 @@
+package fuzzy
+
+import "testing"
+
+func TestScoreMatches(t *testing.T) {
+	tests := []struct {
+		query  string
+		target string
+		match  bool
+	}{
+		{query: "", target: "Физика", match: true},
+		{query: "физ", target: "Физика", match: true},
+		{query: "МАТ. АНАЛИЗ", target: "Математический анализ", match: true},
+		{query: "анализ", target: "Математический анализ", match: true},
+		{query: "тоэ", target: "Теоретические основы электротехники", match: true},
+		{query: "елочка", target: "Ёлочка", match: true},
+		{query: "матиматика", target: "Высшая математика", match: true},
+		// в коротких словах опечатки не допускаются
+		{query: "фиш", target: "Физика", match: false},
+		{query: "химия", target: "Физика", match: false},
+	}
+
+	for _, tt := range tests {
+		if _, ok := Score(tt.query, tt.target); ok != tt.match {
+			t.Errorf("Score(%q, %q) match = %v, want %v", tt.query, tt.target, ok, tt.match)
+		}
+	}
+}
+
+func TestScoreOrder(t *testing.T) {
+	tests := []struct {
+		query  string
+		better string
+		worse  string
+	}{
+		// подстрока лучше сокращения, а ближе к началу - лучше
+		{query: "физ", better: "Физика", worse: "Фаза измерений"},
+		{query: "физ", better: "Физика", worse: "Общая физика"},
+		// сокращение лучше опечатки
+		{query: "физика", better: "Физическая культура", worse: "Фзика"},
+		// буквы в начале слов ценятся выше
+		{query: "оэ", better: "Основы электротехники", worse: "Полиэтилен"},
+	}
+
+	for _, tt := range tests {
+		better, ok := Score(tt.query, tt.better)
+		if !ok {
+			t.Fatalf("Score(%q, %q) did not match", tt.query, tt.better)
+		}
+		worse, ok := Score(tt.query, tt.worse)
+		if !ok {
+			t.Fatalf("Score(%q, %q) did not match", tt.query, tt.worse)
+		}
+		if better <= worse {
+			t.Errorf("query %q: %q scored %d, not above %q with %d", tt.query, tt.better, better, tt.worse, worse)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	tests := map[string]string{
+		"  Мат. анализ (ЛР-1)  ": "мат анализ лр 1",
+		"Ёж":                     "еж",
+		"...":                    "",
+	}
+
+	for input, want := range tests {
+		if got := normalize(input); got != want {
+			t.Errorf("normalize(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{a: "", b: "", want: 0},
+		{a: "мат", b: "", want: 3},
+		{a: "матиматика", b: "математика", want: 1},
+		{a: "физика", b: "фзика", want: 1},
+		{a: "кот", b: "ток", want: 2},
+	}
+
+	for _, tt := range tests {
+		if got := levenshtein([]rune(tt.a), []rune(tt.b)); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,97 @@
+// Package ical минимальная запись календаря в формате iCalendar (RFC 5545) с событиями на весь день
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	dateLayout      = "20060102"
+	timestampLayout = "20060102T150405Z"
+	// maxLineOctets RFC 5545 требует переносить строки длиннее 75 байт
+	maxLineOctets = 75
+)
+
+var textEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	";", `\;`,
+	",", `\,`,
+	"\r\n", `\n`,
+	"\n", `\n`,
+	"\r", `\n`,
+)
+
+type Calendar struct {
+	ProdID string
+	Name   string
+	Events []Event
+}
+
+// Event событие на весь день: от Start до End, End не включается
+type Event struct {
+	UID         string
+	Summary     string
+	Description string
+	Start       time.Time
+	End         time.Time
+}
+
+// Marshal stamp записывается в DTSTAMP всех событий
+func (c *Calendar) Marshal(stamp time.Time) []byte {
+	var b bytes.Buffer
+
+	writeLine(&b, "BEGIN:VCALENDAR")
+	writeLine(&b, "VERSION:2.0")
+	writeLine(&b, "PRODID:"+c.ProdID)
+	writeLine(&b, "CALSCALE:GREGORIAN")
+	writeLine(&b, "METHOD:PUBLISH")
+	if c.Name != "" {
+		writeLine(&b, "X-WR-CALNAME:"+EscapeText(c.Name))
+	}
+
+	for _, event := range c.Events {
+		writeLine(&b, "BEGIN:VEVENT")
+		writeLine(&b, "UID:"+event.UID)
+		writeLine(&b, "DTSTAMP:"+stamp.UTC().Format(timestampLayout))
+		writeLine(&b, "DTSTART;VALUE=DATE:"+event.Start.Format(dateLayout))
+		writeLine(&b, "DTEND;VALUE=DATE:"+event.End.Format(dateLayout))
+		writeLine(&b, "SUMMARY:"+EscapeText(event.Summary))
+		if event.Description != "" {
+			writeLine(&b, "DESCRIPTION:"+EscapeText(event.Description))
+		}
+		writeLine(&b, "TRANSP:TRANSPARENT")
+		writeLine(&b, "END:VEVENT")
+	}
+
+	writeLine(&b, "END:VCALENDAR")
+
+	return b.Bytes()
+}
+
+// EscapeText экранирование значения типа TEXT
+func EscapeText(s string) string {
+	return textEscaper.Replace(s)
+}
+
+// writeLine переносит строку по границам символов UTF-8, продолжение начинается с пробела
+func writeLine(b *bytes.Buffer, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// пробел в начале продолжения тоже считается
+		limit = maxLineOctets - 1
+	}
+
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
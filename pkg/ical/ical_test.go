@@ -0,0 +1,98 @@
+package ical
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+var update = flag.Bool("update", false, "rewrite golden files")
+
+func TestCalendarMarshal(t *testing.T) {
+	moscow := time.FixedZone("MSK", 3*60*60)
+	calendar := &Calendar{
+		ProdID: "-//tracking-bars//calendar//RU",
+		Name:   "Дедлайны БАРС",
+		Events: []Event{
+			{
+				UID:         "1-28-0-0@tracking-bars",
+				Summary:     "КМ-1 Контрольная работа; вес 0,2",
+				Description: "Математический анализ\nоценка: нет\\черновик",
+				// полночь по Москве - ещё предыдущий день по UTC, дата события берётся в зоне Start
+				Start: time.Date(2024, time.October, 14, 0, 0, 0, 0, moscow),
+				End:   time.Date(2024, time.October, 15, 0, 0, 0, 0, moscow),
+			},
+			{
+				UID:     "1-28-1-0@tracking-bars",
+				Summary: "КМ-2 Очень длинное название контрольного мероприятия, которое не помещается в одну строку",
+				Start:   time.Date(2024, time.December, 31, 0, 0, 0, 0, moscow),
+				End:     time.Date(2025, time.January, 1, 0, 0, 0, 0, moscow),
+			},
+		},
+	}
+
+	got := calendar.Marshal(time.Date(2024, time.October, 10, 12, 30, 0, 0, moscow))
+	assertGolden(t, "testdata/calendar.ics", got)
+
+	for _, line := range strings.Split(strings.TrimSuffix(string(got), "\r\n"), "\r\n") {
+		if len(line) > maxLineOctets {
+			t.Errorf("line is %d octets long, want at most %d: %q", len(line), maxLineOctets, line)
+		}
+		if strings.ContainsAny(line, "\r\n") {
+			t.Errorf("bare line break inside a content line: %q", line)
+		}
+	}
+}
+
+func TestWriteLineKeepsRunesWhole(t *testing.T) {
+	var b bytes.Buffer
+	// 37 двухбайтовых символов после "A": граница в 75 байт попадает в середину символа
+	writeLine(&b, "A"+strings.Repeat("ж", 100))
+
+	unfolded := strings.ReplaceAll(strings.TrimSuffix(b.String(), "\r\n"), "\r\n ", "")
+	if unfolded != "A"+strings.Repeat("ж", 100) {
+		t.Errorf("unfolded line differs from the original: %q", unfolded)
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(b.String(), "\r\n"), "\r\n") {
+		if !strings.HasPrefix(line, "A") && !strings.HasPrefix(line, " ж") {
+			t.Errorf("continuation line starts inside a rune: %q", line)
+		}
+	}
+}
+
+func TestEscapeText(t *testing.T) {
+	tests := map[string]string{
+		"простой текст":    "простой текст",
+		`a\b`:              `a\\b`,
+		"a;b,c":            `a\;b\,c`,
+		"строка\r\nвторая": `строка\nвторая`,
+		"a\rb\nc":          `a\nb\nc`,
+	}
+
+	for input, want := range tests {
+		if got := EscapeText(input); got != want {
+			t.Errorf("EscapeText(%q) = %q, want %q", input, got, want)
+		}
+	}
+}
+
+func assertGolden(t *testing.T, path string, got []byte) {
+	t.Helper()
+
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatalf("os.WriteFile: %v", err)
+		}
+	}
+
+	want, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("os.ReadFile: %v", err)
+	}
+	if !bytes.Equal(got, want) {
+		t.Errorf("output differs from %s:\ngot:\n%s\nwant:\n%s", path, got, want)
+	}
+}
@@ -0,0 +1,45 @@
+// Package markdown экранирование текста для режима MarkdownV2 телеграма
+package markdown
+
+import "strings"
+
+// specialChars символы, которые телеграм требует экранировать в MarkdownV2 вне сущностей
+const specialChars = "_*[]()~`>#+-=|{}.!\\"
+
+// markupChars символы разметки, которые остаются в тексте только как сущности
+const markupChars = "*_~`|"
+
+func EscapeV2(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+	for _, r := range s {
+		if strings.ContainsRune(specialChars, r) {
+			b.WriteByte('\\')
+		}
+		b.WriteRune(r)
+	}
+
+	return b.String()
+}
+
+// StripV2 убирает из текста MarkdownV2 разметку и экранирование, оставляя текст, который видит пользователь
+func StripV2(s string) string {
+	var b strings.Builder
+	b.Grow(len(s))
+
+	escaped := false
+	for _, r := range s {
+		switch {
+		case escaped:
+			b.WriteRune(r)
+			escaped = false
+		case r == '\\':
+			escaped = true
+		case strings.ContainsRune(markupChars, r):
+		default:
+			b.WriteRune(r)
+		}
+	}
+
+	return b.String()
+}
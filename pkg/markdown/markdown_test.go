@@ -0,0 +1,57 @@
+package markdown
+
+import "testing"
+
+func TestEscapeV2(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "Физика", want: "Физика"},
+		{in: "Программирование_на_C++", want: `Программирование\_на\_C\+\+`},
+		{in: "*Физика*", want: `\*Физика\*`},
+		{in: "[ссылка](https://example.com)", want: `\[ссылка\]\(https://example\.com\)`},
+		{in: "КМ-1 (вес 0,2).", want: `КМ\-1 \(вес 0,2\)\.`},
+		{in: "`код` ~ > # = | { } !", want: "\\`код\\` \\~ \\> \\# \\= \\| \\{ \\} \\!"},
+		{in: `a\b`, want: `a\\b`},
+	}
+
+	for _, tt := range tests {
+		if got := EscapeV2(tt.in); got != tt.want {
+			t.Errorf("EscapeV2(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStripV2(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "", want: ""},
+		{in: "*Физика*: 5", want: "Физика: 5"},
+		{in: `*Программирование\_на\_C\+\+*`, want: "Программирование_на_C++"},
+		{in: "_курсив_ ~зачёркнутый~ ||спойлер|| `код`", want: "курсив зачёркнутый спойлер код"},
+		{in: `a\\b`, want: `a\b`},
+	}
+
+	for _, tt := range tests {
+		if got := StripV2(tt.in); got != tt.want {
+			t.Errorf("StripV2(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+// TestStripV2EscapeV2 экранированный текст после удаления разметки совпадает с исходным
+func TestStripV2EscapeV2(t *testing.T) {
+	for _, s := range []string{
+		"Математический анализ",
+		"a_b*c[d]e(f)g~h`i>j#k+l-m=n|o{p}q.r!s",
+		`\*\`,
+	} {
+		if got := StripV2(EscapeV2(s)); got != s {
+			t.Errorf("StripV2(EscapeV2(%q)) = %q", s, got)
+		}
+	}
+}